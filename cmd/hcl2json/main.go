@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hclconv"
+	"github.com/hashicorp/hcl2/hclparse"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const versionStr = "0.0.1-dev"
+
+var (
+	showVersion = flag.Bool("version", false, "show the version number and immediately exit")
+)
+
+var parser = hclparse.NewParser()
+var diagWr hcl.DiagnosticWriter // initialized in init
+
+func init() {
+	color := terminal.IsTerminal(int(os.Stderr.Fd()))
+	w, _, err := terminal.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		w = 80
+	}
+	diagWr = hcl.NewDiagnosticTextWriter(os.Stderr, parser.Files(), uint(w), color)
+}
+
+func main() {
+	err := realmain()
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+func realmain() error {
+	flag.Usage = usage
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Println(versionStr)
+		return nil
+	}
+
+	switch flag.NArg() {
+	case 0:
+		return processFile("<stdin>", os.Stdin)
+	case 1:
+		return processFile(flag.Arg(0), nil)
+	default:
+		// Each file produces a separate JSON document, so we can't write
+		// more than one of them to stdout.
+		return errors.New("can convert only one file at a time")
+	}
+}
+
+func processFile(fn string, in *os.File) error {
+	var err error
+	if in == nil {
+		in, err = os.Open(fn)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %s", fn, err)
+		}
+	}
+
+	inSrc, err := ioutil.ReadAll(in)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %s", fn, err)
+	}
+
+	file, diags := parser.ParseHCL(inSrc, fn)
+	if diags.HasErrors() {
+		diagWr.WriteDiagnostics(diags)
+		return errors.New("failed to parse input")
+	}
+
+	outSrc, diags := hclconv.NativeToJSON(file)
+	diagWr.WriteDiagnostics(diags)
+	if diags.HasErrors() {
+		return errors.New("failed to convert input")
+	}
+
+	_, err = os.Stdout.Write(outSrc)
+	return err
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: hcl2json [flags] [path]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
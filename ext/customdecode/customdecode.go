@@ -0,0 +1,32 @@
+package customdecode
+
+import (
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// CustomExpressionDecoder is the signature of a function that can produce
+// a value from an expression in some custom way, rather than by evaluating
+// it in the normal way.
+//
+// The returned value should be of the type that the decoder was selected
+// for, or an unknown value of that type if the decoder returns errors.
+type CustomExpressionDecoder func(expr hcl.Expression, ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics)
+
+// CustomExpressionDecoderForType returns the custom expression decoder
+// associated with the given type, or nil if the type has no custom decoder.
+//
+// Code that evaluates expressions in contexts that support custom decoding
+// should call this with the type that a value is being decoded into. If the
+// result is non-nil, it should be called instead of evaluating the
+// expression, and its result used without any further type conversion.
+func CustomExpressionDecoderForType(ty cty.Type) CustomExpressionDecoder {
+	switch {
+	case ty.Equals(ExpressionType):
+		return decodeExpression
+	case ty.Equals(ExpressionClosureType):
+		return decodeExpressionClosure
+	default:
+		return nil
+	}
+}
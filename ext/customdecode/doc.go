@@ -0,0 +1,12 @@
+// Package customdecode contains a HCL extension that allows, in certain
+// contexts, expression evaluation to be overridden by custom static analysis.
+//
+// This mechanism is only supported in certain specific contexts during
+// expression evaluation. Currently the only such context is the arguments of
+// a function call in the native syntax: a function parameter whose type is
+// one of the capsule types defined in this package will receive the argument
+// expression itself, rather than the result of evaluating it.
+//
+// This is useful for functions that need to handle errors in their
+// arguments, such as those in the sibling package tryfunc.
+package customdecode
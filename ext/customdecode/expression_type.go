@@ -0,0 +1,83 @@
+package customdecode
+
+import (
+	"reflect"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ExpressionType is a cty capsule type that carries hcl.Expression values.
+//
+// A function parameter of this type receives the argument expression itself,
+// without evaluating it, which allows the function to analyze the expression
+// statically.
+var ExpressionType cty.Type
+
+// ExpressionVal returns a new cty value of type ExpressionType, wrapping the
+// given expression.
+func ExpressionVal(expr hcl.Expression) cty.Value {
+	return cty.CapsuleVal(ExpressionType, &expr)
+}
+
+// ExpressionFromVal returns the expression encapsulated in the given value,
+// which must be a known, non-null value of type ExpressionType.
+func ExpressionFromVal(v cty.Value) hcl.Expression {
+	if !v.Type().Equals(ExpressionType) {
+		panic("value is not of ExpressionType")
+	}
+	ptr := v.EncapsulatedValue().(*hcl.Expression)
+	return *ptr
+}
+
+// ExpressionClosureType is a cty capsule type that carries an hcl.Expression
+// along with the hcl.EvalContext it would normally be evaluated in.
+//
+// A function parameter of this type receives the argument expression itself,
+// without evaluating it, along with the context of the function call. The
+// function may then evaluate the expression itself and handle any errors
+// that result.
+var ExpressionClosureType cty.Type
+
+// ExpressionClosure is the type encapsulated in ExpressionClosureType.
+type ExpressionClosure struct {
+	Expression  hcl.Expression
+	EvalContext *hcl.EvalContext
+}
+
+// ExpressionClosureVal returns a new cty value of type ExpressionClosureType,
+// wrapping the given closure.
+func ExpressionClosureVal(closure *ExpressionClosure) cty.Value {
+	return cty.CapsuleVal(ExpressionClosureType, closure)
+}
+
+// ExpressionClosureFromVal returns the closure encapsulated in the given
+// value, which must be a known, non-null value of type ExpressionClosureType.
+func ExpressionClosureFromVal(v cty.Value) *ExpressionClosure {
+	if !v.Type().Equals(ExpressionClosureType) {
+		panic("value is not of ExpressionClosureType")
+	}
+	return v.EncapsulatedValue().(*ExpressionClosure)
+}
+
+// Value evaluates the closure's expression in the closure's EvalContext,
+// returning the result.
+func (c *ExpressionClosure) Value() (cty.Value, hcl.Diagnostics) {
+	return c.Expression.Value(c.EvalContext)
+}
+
+func decodeExpression(expr hcl.Expression, ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	return ExpressionVal(expr), nil
+}
+
+func decodeExpressionClosure(expr hcl.Expression, ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	return ExpressionClosureVal(&ExpressionClosure{
+		Expression:  expr,
+		EvalContext: ctx,
+	}), nil
+}
+
+func init() {
+	ExpressionType = cty.Capsule("expression", reflect.TypeOf((*hcl.Expression)(nil)).Elem())
+	ExpressionClosureType = cty.Capsule("expression closure", reflect.TypeOf(ExpressionClosure{}))
+}
@@ -381,3 +381,68 @@ func TestExpand(t *testing.T) {
 	})
 
 }
+
+func TestExpandSensitive(t *testing.T) {
+	srcBody := hcltest.MockBody(&hcl.BodyContent{
+		Blocks: hcl.Blocks{
+			{
+				Type:        "dynamic",
+				Labels:      []string{"b"},
+				LabelRanges: []hcl.Range{hcl.Range{}},
+				Body: hcltest.MockBody(&hcl.BodyContent{
+					Attributes: hcltest.MockAttrs(map[string]hcl.Expression{
+						"for_each": hcltest.MockExprTraversalSrc("secrets"),
+					}),
+					Blocks: hcl.Blocks{
+						{
+							Type: "content",
+							Body: hcltest.MockBody(&hcl.BodyContent{
+								Attributes: hcltest.MockAttrs(map[string]hcl.Expression{
+									"val":   hcltest.MockExprTraversalSrc("b.value"),
+									"token": hcltest.MockExprTraversalSrc("token"),
+								}),
+							}),
+						},
+					},
+				}),
+			},
+		},
+	})
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"secrets": cty.ListVal([]cty.Value{cty.StringVal("hunter2")}),
+			"token":   cty.StringVal("abc123"),
+		},
+		Sensitive: []hcl.Traversal{
+			{hcl.TraverseRoot{Name: "secrets"}},
+			{hcl.TraverseRoot{Name: "token"}},
+		},
+	}
+
+	dynBody := Expand(srcBody, ctx)
+	content, diags := dynBody.Content(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "b"}},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Error())
+	}
+	if len(content.Blocks) != 1 {
+		t.Fatalf("wrong number of blocks %d; want 1", len(content.Blocks))
+	}
+	attrs, diags := content.Blocks[0].Body.JustAttributes()
+	if diags.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %s", diags.Error())
+	}
+
+	// A reference to a sensitive variable in the content is still reported.
+	if !hcl.ExprSensitive(attrs["token"].Expr, ctx) {
+		t.Errorf("token is not sensitive")
+	}
+
+	// The iterator is defined in a child context that doesn't carry the
+	// sensitivity of the for_each value, as documented for
+	// EvalContext.Sensitive, so a result derived from it isn't reported.
+	if hcl.ExprSensitive(attrs["val"].Expr, ctx) {
+		t.Errorf("val is sensitive, but the iterator is not expected to be")
+	}
+}
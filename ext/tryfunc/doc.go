@@ -0,0 +1,9 @@
+// Package tryfunc contains some optional functions that can be exposed in
+// HCL-based languages to allow authors to test whether a particular
+// expression can succeed and take dynamic action based on that result.
+//
+// These functions are implemented in terms of the customdecode extension
+// from the sibling directory customdecode, and so they are only useful when
+// used within an HCL EvalContext. Other systems using cty functions are
+// unlikely to support the HCL-specific "customdecode" extension.
+package tryfunc
@@ -162,13 +162,16 @@ func (w *diagnosticTextWriter) WriteDiagnostic(diag *Diagnostic) error {
 					continue // don't show duplicates when the same variable is referenced multiple times
 				}
 				switch {
+				case ctx.IsSensitive(traversal):
+					// We must not reveal any part of a sensitive value.
+					stmts = append(stmts, fmt.Sprintf("%s as (sensitive value)", traversalStr))
 				case !val.IsKnown():
 					// Can't say anything about this yet, then.
 					continue
 				case val.IsNull():
 					stmts = append(stmts, fmt.Sprintf("%s set to null", traversalStr))
 				default:
-					stmts = append(stmts, fmt.Sprintf("%s as %s", traversalStr, w.valueStr(val)))
+					stmts = append(stmts, fmt.Sprintf("%s as %s", traversalStr, valueStr(val)))
 				}
 				seen[traversalStr] = struct{}{}
 			}
@@ -226,12 +229,15 @@ func (w *diagnosticTextWriter) traversalStr(traversal Traversal) string {
 		case TraverseRoot:
 			buf.WriteString(tStep.Name)
 		case TraverseAttr:
+			if tStep.Optional {
+				buf.WriteByte('?')
+			}
 			buf.WriteByte('.')
 			buf.WriteString(tStep.Name)
 		case TraverseIndex:
 			buf.WriteByte('[')
 			if keyTy := tStep.Key.Type(); keyTy.IsPrimitiveType() {
-				buf.WriteString(w.valueStr(tStep.Key))
+				buf.WriteString(valueStr(tStep.Key))
 			} else {
 				// We'll just use a placeholder for more complex values,
 				// since otherwise our result could grow ridiculously long.
@@ -243,10 +249,10 @@ func (w *diagnosticTextWriter) traversalStr(traversal Traversal) string {
 	return buf.String()
 }
 
-func (w *diagnosticTextWriter) valueStr(val cty.Value) string {
+func valueStr(val cty.Value) string {
 	// This is a specialized subset of value rendering tailored to producing
-	// helpful but concise messages in diagnostics. It is not comprehensive
-	// nor intended to be used for other purposes.
+	// helpful but concise messages in diagnostics and evaluation traces. It
+	// is not comprehensive nor intended to be used for other purposes.
 
 	ty := val.Type()
 	switch {
@@ -183,6 +183,87 @@ with bar.baz as empty list of string,
 This diagnostic includes an expression
 and an evalcontext.
 
+`,
+		},
+		{
+			&Diagnostic{
+				Severity: DiagError,
+				Summary:  "Test of redacting sensitive values",
+				Detail:   `This diagnostic refers to sensitive values.`,
+				Subject: &Range{
+					Start: Pos{
+						Byte:   42,
+						Column: 3,
+						Line:   5,
+					},
+					End: Pos{
+						Byte:   47,
+						Column: 8,
+						Line:   5,
+					},
+				},
+				Expression: &diagnosticTestExpr{
+					vars: []Traversal{
+						{
+							TraverseRoot{
+								Name: "creds",
+							},
+							TraverseAttr{
+								Name: "username",
+							},
+						},
+						{
+							TraverseRoot{
+								Name: "creds",
+							},
+							TraverseAttr{
+								Name: "password",
+							},
+						},
+						{
+							TraverseRoot{
+								Name: "token",
+							},
+						},
+					},
+				},
+				EvalContext: &EvalContext{
+					Variables: map[string]cty.Value{
+						"creds": cty.ObjectVal(map[string]cty.Value{
+							"username": cty.StringVal("admin"),
+							"password": cty.StringVal("hunter2"),
+						}),
+						"token": cty.StringVal("s3cr3t"),
+					},
+					Sensitive: []Traversal{
+						{
+							TraverseRoot{
+								Name: "creds",
+							},
+							TraverseIndex{
+								Key: cty.StringVal("password"),
+							},
+						},
+						{
+							TraverseRoot{
+								Name: "token",
+							},
+						},
+					},
+				},
+			},
+			`Error: Test of redacting sensitive values
+
+  on  line 5, in hardcoded-context:
+   5:   pizza = "cheese"
+
+with creds.password as (sensitive value),
+     creds.username as "admin",
+     token as (sensitive value).
+
+This diagnostic refers to sensitive
+values.
+
 `,
 		},
 	}
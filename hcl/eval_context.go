@@ -10,7 +10,36 @@ import (
 type EvalContext struct {
 	Variables map[string]cty.Value
 	Functions map[string]function.Function
-	parent    *EvalContext
+
+	// Sensitive marks the parts of the values in Variables that are
+	// sensitive. Each traversal must begin with the name of a variable
+	// defined in this context and may continue with attribute and index
+	// steps to mark only part of that variable's value.
+	//
+	// Any expression result that derives from a sensitive value is itself
+	// considered to be sensitive, as reported by ExprSensitive, and the
+	// values of sensitive variables are not included in diagnostic
+	// messages.
+	//
+	// Sensitivity is decided only by matching the variable references in
+	// an expression against these traversals, so it does not follow a value
+	// that is passed into a child context under another name. For example,
+	// the iterator of a dynamic block expanded by ext/dynblock and the
+	// parameters of a function defined by ext/userfunc are not sensitive,
+	// even if their values derive from sensitive variables.
+	Sensitive []Traversal
+
+	// Provenance, if set, enables tracking of the provenance of the results
+	// of expressions evaluated in this context and its descendents, which
+	// can then be retrieved from the tracker.
+	Provenance *ProvenanceTracker
+
+	// Tracer, if set, enables recording of the intermediate results of
+	// evaluating expressions in this context and its descendents, which
+	// can then be retrieved from the tracer.
+	Tracer *EvalTracer
+
+	parent *EvalContext
 }
 
 // NewChild returns a new EvalContext that is a child of the receiver.
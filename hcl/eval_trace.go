@@ -0,0 +1,120 @@
+package hcl
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// EvalTrace records the result of evaluating an expression, along with the
+// traces of any nested expressions that were evaluated in order to produce
+// it.
+type EvalTrace struct {
+	Expr Expression
+
+	// Value and Diagnostics are the results of the evaluation.
+	Value       cty.Value
+	Diagnostics Diagnostics
+
+	// Sensitive is true if the result might contain sensitive values, as
+	// reported by ExprSensitive, in which case the value should not be
+	// shown to the user.
+	Sensitive bool
+
+	// Children are the traces of the nested expressions in the order they
+	// were evaluated. An expression that is evaluated more than once, such
+	// as the value expression of a "for" expression, has a separate trace
+	// for each evaluation.
+	Children []*EvalTrace
+}
+
+// Range returns the source range of the traced expression.
+func (t *EvalTrace) Range() Range {
+	return t.Expr.Range()
+}
+
+// EvalTracer records traces of the expressions evaluated in an EvalContext
+// whose Tracer field refers to it, or in any descendent of such a context.
+//
+// This is intended to help users understand how an expression produced a
+// surprising result, by showing the intermediate results of its
+// sub-expressions. Tracing adds overhead to evaluation, so it should be
+// enabled only when the traces will be shown.
+//
+// A tracer may be shared across many evaluations, but it is not safe for
+// concurrent use.
+type EvalTracer struct {
+	traces []*EvalTrace
+	stack  []*EvalTrace
+}
+
+// NewEvalTracer returns a new EvalTracer that has not yet recorded any traces.
+func NewEvalTracer() *EvalTracer {
+	return &EvalTracer{}
+}
+
+// EvalTracer returns the tracer that records the evaluation of expressions
+// in the receiving context, or nil if tracing is not enabled for it.
+func (ctx *EvalContext) EvalTracer() *EvalTracer {
+	for thisCtx := ctx; thisCtx != nil; thisCtx = thisCtx.parent {
+		if thisCtx.Tracer != nil {
+			return thisCtx.Tracer
+		}
+	}
+	return nil
+}
+
+// Traces returns the traces of the outermost expressions evaluated using
+// the receiving tracer, in the order they were evaluated.
+func (t *EvalTracer) Traces() []*EvalTrace {
+	if t == nil {
+		return nil
+	}
+	return t.traces
+}
+
+// Reset discards all of the traces recorded so far, so that the tracer can
+// be reused for another evaluation.
+func (t *EvalTracer) Reset() {
+	if t == nil {
+		return
+	}
+	t.traces = nil
+	t.stack = nil
+}
+
+// BeginExpr and EndExpr are for use by expression implementations, which
+// must call BeginExpr before evaluating an expression and EndExpr with the
+// result once it has been evaluated. Any expressions evaluated in between
+// are recorded as children of the expression.
+//
+// It is safe to call the methods of a nil tracer, which do nothing.
+func (t *EvalTracer) BeginExpr(expr Expression) {
+	if t == nil {
+		return
+	}
+	t.stack = append(t.stack, &EvalTrace{
+		Expr: expr,
+	})
+}
+
+// EndExpr completes the trace started by the most recent call to BeginExpr.
+// See BeginExpr for details.
+//
+// The given context is the one the expression was evaluated in, which is
+// used to determine whether the result is sensitive.
+func (t *EvalTracer) EndExpr(expr Expression, ctx *EvalContext, val cty.Value, diags Diagnostics) {
+	if t == nil || len(t.stack) == 0 {
+		return
+	}
+	trace := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+	trace.Value = val
+	trace.Diagnostics = diags
+	trace.Sensitive = ExprSensitive(expr, ctx)
+
+	if len(t.stack) > 0 {
+		parent := t.stack[len(t.stack)-1]
+		parent.Children = append(parent.Children, trace)
+	} else {
+		t.traces = append(t.traces, trace)
+	}
+}
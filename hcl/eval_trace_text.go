@@ -0,0 +1,256 @@
+package hcl
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// An EvalTraceWriter presents evaluation traces to the user.
+type EvalTraceWriter interface {
+	WriteTrace(*EvalTrace) error
+	WriteTraces([]*EvalTrace) error
+}
+
+type evalTraceTextWriter struct {
+	files map[string]*File
+	wr    io.Writer
+}
+
+// evalTraceMaxValues is the maximum number of results shown for an
+// expression that was evaluated more than once.
+const evalTraceMaxValues = 5
+
+// NewEvalTraceTextWriter creates an EvalTraceWriter that writes traces to the
+// given writer as formatted text, to be printed in a monospaced font.
+//
+// Each trace is shown as the source code of the traced expression with the
+// intermediate results of its sub-expressions written underneath, each
+// connected by a vertical line to the operator or the start of the
+// sub-expression that produced it. For example:
+//
+//	on example.hcl line 1:
+//	 1: n * 2 > limit ? "big" : "small"
+//	    | |   | |     |
+//	    | |   | |     "small"
+//	    | |   | 10
+//	    | |   false
+//	    | 6
+//	    3
+//
+// Sub-expressions that are evaluated more than once, such as those in the
+// body of a "for" expression, are shown with each of their results. Constant
+// sub-expressions are not annotated, and sensitive results are not shown.
+func NewEvalTraceTextWriter(wr io.Writer, files map[string]*File) EvalTraceWriter {
+	return &evalTraceTextWriter{
+		files: files,
+		wr:    wr,
+	}
+}
+
+func (w *evalTraceTextWriter) WriteTrace(trace *EvalTrace) error {
+	if trace == nil {
+		return errors.New("nil trace")
+	}
+
+	rng := trace.Range()
+	file := w.files[rng.Filename]
+	if file == nil || file.Bytes == nil {
+		text, _ := evalTraceResultStr(trace)
+		_, err := fmt.Fprintf(w.wr, "  on %s line %d:\n  (source code not available)\n  result: %s\n\n", rng.Filename, rng.Start.Line, text)
+		return err
+	}
+	src := file.Bytes
+
+	annots := map[int]*evalTraceAnnotation{}
+	collectEvalTraceAnnotations(trace, src, annots)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "  on %s line %d:\n", rng.Filename, rng.Start.Line)
+
+	sc := NewRangeScanner(src, rng.Filename, bufio.ScanLines)
+	for sc.Scan() {
+		lineRange := sc.Range()
+		if !lineRange.Overlaps(rng) {
+			continue
+		}
+		line := sc.Bytes()
+		fmt.Fprintf(&buf, "%4d: %s\n", lineRange.Start.Line, bytes.Replace(line, []byte{'\t'}, []byte{' '}, -1))
+
+		var inLine []*evalTraceAnnotation
+		for offset, annot := range annots {
+			if offset >= lineRange.Start.Byte && offset < lineRange.End.Byte {
+				annot.column = utf8.RuneCount(src[lineRange.Start.Byte:offset])
+				inLine = append(inLine, annot)
+			}
+		}
+		if len(inLine) == 0 {
+			continue
+		}
+		sort.Slice(inLine, func(i, j int) bool {
+			return inLine[i].column < inLine[j].column
+		})
+
+		// The first row just has a line for each annotation, and then each
+		// subsequent row introduces the text for the rightmost annotation
+		// that hasn't been written yet, so that no text overlaps the lines
+		// for those still to come.
+		writeEvalTraceRow(&buf, inLine, len(inLine), "")
+		for i := len(inLine) - 1; i >= 0; i-- {
+			writeEvalTraceRow(&buf, inLine, i, inLine[i].text())
+		}
+	}
+	buf.WriteByte('\n')
+
+	_, err := w.wr.Write(buf.Bytes())
+	return err
+}
+
+func (w *evalTraceTextWriter) WriteTraces(traces []*EvalTrace) error {
+	for _, trace := range traces {
+		err := w.WriteTrace(trace)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type evalTraceAnnotation struct {
+	expr   Expression
+	values []string
+	column int
+}
+
+func (a *evalTraceAnnotation) text() string {
+	if len(a.values) > evalTraceMaxValues {
+		return strings.Join(a.values[:evalTraceMaxValues], ", ") + ", ..."
+	}
+	return strings.Join(a.values, ", ")
+}
+
+// writeEvalTraceRow writes a row of annotations with vertical lines for the
+// first n of the given annotations and then the given text in the column of
+// the next annotation, if any.
+func writeEvalTraceRow(buf *bytes.Buffer, annots []*evalTraceAnnotation, n int, text string) {
+	buf.WriteString("      ")
+	col := 0
+	for _, annot := range annots[:n] {
+		buf.WriteString(strings.Repeat(" ", annot.column-col))
+		buf.WriteByte('|')
+		col = annot.column + 1
+	}
+	if text != "" {
+		buf.WriteString(strings.Repeat(" ", annots[n].column-col))
+		buf.WriteString(text)
+	}
+	buf.WriteByte('\n')
+}
+
+// collectEvalTraceAnnotations populates the given map with the annotations
+// for the given trace and its descendents, keyed by the byte offset in src
+// where each is anchored.
+func collectEvalTraceAnnotations(trace *EvalTrace, src []byte, annots map[int]*evalTraceAnnotation) {
+	// A constant expression is self-explanatory, so we skip it.
+	if !evalTraceConstant(trace) {
+		if text, ok := evalTraceResultStr(trace); ok {
+			anchor := evalTraceAnchor(trace, src)
+			switch existing := annots[anchor]; {
+			case existing == nil:
+				annots[anchor] = &evalTraceAnnotation{
+					expr:   trace.Expr,
+					values: []string{text},
+				}
+			case existing.expr == trace.Expr:
+				existing.values = append(existing.values, text)
+			default:
+				// An outer expression anchored at the same position as this
+				// one takes priority, since it was visited first.
+			}
+		}
+	}
+
+	for _, child := range trace.Children {
+		collectEvalTraceAnnotations(child, src, annots)
+	}
+}
+
+// evalTraceConstant returns true if the traced expression has a result that
+// is evident from its source code, because it refers to no variables and
+// calls no functions.
+func evalTraceConstant(trace *EvalTrace) bool {
+	if len(trace.Expr.Variables()) > 0 {
+		return false
+	}
+	if _, diags := ExprCall(trace.Expr); !diags.HasErrors() {
+		return false
+	}
+	for _, child := range trace.Children {
+		if !evalTraceConstant(child) {
+			return false
+		}
+	}
+	return true
+}
+
+// evalTraceAnchor returns the byte offset of the first character of the
+// traced expression that does not belong to any of its sub-expressions,
+// which is typically its operator, or the start of the expression if there
+// is no such character.
+func evalTraceAnchor(trace *EvalTrace, src []byte) int {
+	rng := trace.Range()
+	end := rng.End.Byte
+	if end > len(src) {
+		end = len(src)
+	}
+
+Offsets:
+	for i := rng.Start.Byte; i < end; i++ {
+		for _, child := range trace.Children {
+			childRng := child.Range()
+			if i >= childRng.Start.Byte && i < childRng.End.Byte {
+				continue Offsets
+			}
+		}
+		switch src[i] {
+		case ' ', '\t', '\r', '\n', '(', ')':
+			continue
+		}
+		return i
+	}
+	return rng.Start.Byte
+}
+
+// evalTraceResultStr returns a concise description of the result of the
+// given trace, or false if the result is not worth showing because it
+// failed due to an error in one of its sub-expressions, which will be
+// shown instead.
+func evalTraceResultStr(trace *EvalTrace) (string, bool) {
+	if trace.Diagnostics.HasErrors() {
+	Diags:
+		for _, diag := range trace.Diagnostics {
+			if diag.Severity != DiagError {
+				continue
+			}
+			for _, child := range trace.Children {
+				for _, childDiag := range child.Diagnostics {
+					if childDiag == diag {
+						continue Diags
+					}
+				}
+			}
+			return "error: " + diag.Summary, true
+		}
+		return "", false
+	}
+
+	if trace.Sensitive {
+		return "(sensitive value)", true
+	}
+	return valueStr(trace.Value), true
+}
@@ -0,0 +1,191 @@
+package hclsyntax
+
+import (
+	"sort"
+
+	"github.com/apparentlymart/go-textseg/textseg"
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+// Comment represents a single comment from the source code, which may be
+// either a single-line comment introduced by # or //, or an inline comment
+// delimited by /* and */.
+type Comment struct {
+	// Bytes is the raw source of the comment, including its introducer
+	// and, for inline comments, its terminator. The newline that ends a
+	// single-line comment is not included.
+	Bytes []byte
+
+	SrcRange hcl.Range
+}
+
+// sourceComment is a comment token along with some extra information about
+// its position that is needed to decide where it should be attached.
+type sourceComment struct {
+	Comment *Comment
+
+	// WholeLine is true if the comment is the first token on its line.
+	WholeLine bool
+
+	// EndLine is the line where the comment's content ends, which for
+	// single-line comments is not the same as the line number at the end
+	// of the token, since the token includes the terminating newline.
+	EndLine int
+}
+
+// attachComments populates the comment fields of the given body and of all
+// of its descendent attributes and blocks, using the comment tokens from
+// the given token sequence, which should be the tokens that the body was
+// parsed from.
+//
+// Comments that appear on whole lines immediately before an attribute or
+// block, with no blank lines in between, are its lead comments. Comments
+// that appear after the end of an attribute or block on the same line are
+// its line comments. All other comments are inner comments of the innermost
+// body that contains them, so every comment in the token sequence is
+// attached to exactly one node.
+func attachComments(body *Body, tokens Tokens) {
+	attachBodyComments(body, sourceComments(tokens))
+}
+
+func sourceComments(tokens Tokens) []sourceComment {
+	var comments []sourceComment
+	for i, tok := range tokens {
+		if tok.Type != TokenComment {
+			continue
+		}
+
+		wholeLine := true
+		if i > 0 {
+			prev := tokens[i-1]
+			wholeLine = prev.Type == TokenNewline || tokenEndsWithNewline(prev) || prev.Range.End.Line < tok.Range.Start.Line
+		}
+
+		comment := newComment(tok)
+		comments = append(comments, sourceComment{
+			Comment:   comment,
+			WholeLine: wholeLine,
+			EndLine:   comment.SrcRange.End.Line,
+		})
+	}
+	return comments
+}
+
+func attachBodyComments(body *Body, comments []sourceComment) {
+	if body == nil || len(comments) == 0 {
+		return
+	}
+
+	var items []Node
+	for _, attr := range body.Attributes {
+		items = append(items, attr)
+	}
+	for _, block := range body.Blocks {
+		items = append(items, block)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Range().Start.Byte < items[j].Range().Start.Byte
+	})
+
+	// First we'll separate out the comments that belong to nested blocks,
+	// which we'll deal with recursively below.
+	nested := make(map[*Block][]sourceComment)
+	var own []sourceComment
+Comments:
+	for _, c := range comments {
+		for _, item := range items {
+			block, isBlock := item.(*Block)
+			if !isBlock {
+				continue
+			}
+			if c.Comment.SrcRange.Start.Byte >= block.OpenBraceRange.End.Byte && c.Comment.SrcRange.End.Byte <= block.CloseBraceRange.Start.Byte {
+				nested[block] = append(nested[block], c)
+				continue Comments
+			}
+		}
+		own = append(own, c)
+	}
+
+	attached := make([]bool, len(own))
+	for _, item := range items {
+		rng := item.Range()
+
+		var line []*Comment
+		for i, c := range own {
+			if attached[i] || c.Comment.SrcRange.Start.Byte < rng.End.Byte {
+				continue
+			}
+			if c.Comment.SrcRange.Start.Line != rng.End.Line {
+				break
+			}
+			line = append(line, c.Comment)
+			attached[i] = true
+		}
+
+		// Lead comments are found by walking backwards from the item,
+		// collecting whole-line comments on consecutive lines.
+		var lead []*Comment
+		wantLine := rng.Start.Line - 1
+		for i := len(own) - 1; i >= 0; i-- {
+			c := own[i]
+			if c.Comment.SrcRange.Start.Byte >= rng.Start.Byte {
+				continue
+			}
+			if attached[i] || !c.WholeLine || c.EndLine != wantLine {
+				break
+			}
+			lead = append([]*Comment{c.Comment}, lead...)
+			attached[i] = true
+			wantLine = c.Comment.SrcRange.Start.Line - 1
+		}
+
+		switch titem := item.(type) {
+		case *Attribute:
+			titem.LeadComments = lead
+			titem.LineComments = line
+		case *Block:
+			titem.LeadComments = lead
+			titem.LineComments = line
+		}
+	}
+
+	for i, c := range own {
+		if !attached[i] {
+			body.InnerComments = append(body.InnerComments, c.Comment)
+		}
+	}
+
+	for _, block := range body.Blocks {
+		attachBodyComments(block.Body, nested[block])
+	}
+}
+
+func newComment(tok Token) *Comment {
+	src := tok.Bytes
+	rng := tok.Range
+	if tokenEndsWithNewline(tok) {
+		src = src[:len(src)-1]
+		if len(src) > 0 && src[len(src)-1] == '\r' {
+			src = src[:len(src)-1]
+		}
+
+		// The token range ends at the start of the following line, so we
+		// need to recalculate where the comment text itself ends.
+		rng.End = rng.Start
+		rng.End.Byte += len(src)
+		for b := src; len(b) > 0; {
+			adv, _, _ := textseg.ScanGraphemeClusters(b, true)
+			rng.End.Column++
+			b = b[adv:]
+		}
+	}
+
+	return &Comment{
+		Bytes:    src,
+		SrcRange: rng,
+	}
+}
+
+func tokenEndsWithNewline(tok Token) bool {
+	return tok.Type == TokenComment && len(tok.Bytes) > 0 && tok.Bytes[len(tok.Bytes)-1] == '\n'
+}
@@ -0,0 +1,128 @@
+package hclsyntax
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+func TestParseConfigComments(t *testing.T) {
+	const src = `# File header
+
+// Doc comment for a,
+// on two lines.
+a = 1 # line comment for a
+b = {
+  # inside an expression
+  c = 2
+}
+
+/* Doc for block */
+block "label" { /* after brace */
+  # Doc for d
+  d = 3 // line comment for d
+
+  # Floating comment in block
+} # line comment for block
+
+# Trailing comment
+`
+
+	f, diags := ParseConfig([]byte(src), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	body := f.Body.(*Body)
+	block := body.Blocks[0]
+
+	commentsStr := func(comments []*Comment) []string {
+		var ret []string
+		for _, c := range comments {
+			ret = append(ret, fmt.Sprintf("%s %s", c.Bytes, c.SrcRange))
+		}
+		return ret
+	}
+
+	tests := []struct {
+		Name string
+		Got  []*Comment
+		Want []string
+	}{
+		{
+			"body inner",
+			body.InnerComments,
+			[]string{
+				"# File header test.hcl:1,1-14",
+				"# inside an expression test.hcl:7,3-25",
+				"# Trailing comment test.hcl:19,1-19",
+			},
+		},
+		{
+			"a lead",
+			body.Attributes["a"].LeadComments,
+			[]string{
+				"// Doc comment for a, test.hcl:3,1-22",
+				"// on two lines. test.hcl:4,1-17",
+			},
+		},
+		{
+			"a line",
+			body.Attributes["a"].LineComments,
+			[]string{
+				"# line comment for a test.hcl:5,7-27",
+			},
+		},
+		{
+			"b lead",
+			body.Attributes["b"].LeadComments,
+			nil,
+		},
+		{
+			"block lead",
+			block.LeadComments,
+			[]string{
+				"/* Doc for block */ test.hcl:11,1-20",
+			},
+		},
+		{
+			"block line",
+			block.LineComments,
+			[]string{
+				"# line comment for block test.hcl:17,3-27",
+			},
+		},
+		{
+			"block body inner",
+			block.Body.InnerComments,
+			[]string{
+				"/* after brace */ test.hcl:12,17-34",
+				"# Floating comment in block test.hcl:16,3-30",
+			},
+		},
+		{
+			"d lead",
+			block.Body.Attributes["d"].LeadComments,
+			[]string{
+				"# Doc for d test.hcl:13,3-14",
+			},
+		},
+		{
+			"d line",
+			block.Body.Attributes["d"].LineComments,
+			[]string{
+				"// line comment for d test.hcl:14,9-30",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			got := commentsStr(test.Got)
+			if !reflect.DeepEqual(got, test.Want) {
+				t.Errorf("wrong comments\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package hclsyntax
+
+import (
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// observeEval notifies any provenance tracker and evaluation tracer enabled
+// for the given context that evaluation of the given expression is
+// beginning. The caller must call the returned function with pointers to
+// the results once the expression has been evaluated, and so this is
+// typically used with named results as:
+//
+//	defer observeEval(e, ctx)(&ret, &retDiags)
+func observeEval(expr Expression, ctx *hcl.EvalContext) func(*cty.Value, *hcl.Diagnostics) {
+	prov := ctx.ProvenanceTracker()
+	tracer := ctx.EvalTracer()
+	if prov == nil && tracer == nil {
+		return func(*cty.Value, *hcl.Diagnostics) {}
+	}
+	prov.BeginExpr()
+	tracer.BeginExpr(expr)
+	return func(val *cty.Value, diags *hcl.Diagnostics) {
+		prov.EndExpr(expr)
+		tracer.EndExpr(expr, ctx, *val, *diags)
+	}
+}
@@ -0,0 +1,157 @@
+package hclsyntax
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+func TestEvalTraceTextWriter(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			`n * 2 > limit ? "big" : "small"`,
+			`  on test.hcl line 1:
+   1: n * 2 > limit ? "big" : "small"
+      | |   | |     |
+      | |   | |     "small"
+      | |   | 10
+      | |   false
+      | 6
+      3
+
+`,
+		},
+		{
+			`upper(a) == "A" && obj.name != a`,
+			`  on test.hcl line 1:
+   1: upper(a) == "A" && obj.name != a
+      |     |  |      |  |        |  |
+      |     |  |      |  |        |  "a"
+      |     |  |      |  |        true
+      |     |  |      |  "hello"
+      |     |  |      true
+      |     |  true
+      |     "a"
+      "A"
+
+`,
+		},
+		{
+			`"${a}-${obj.name}"`,
+			`  on test.hcl line 1:
+   1: "${a}-${obj.name}"
+      |  |    |
+      |  |    "hello"
+      |  "a"
+      "a-hello"
+
+`,
+		},
+		{
+			`[for x in list: upper(x)]`,
+			`  on test.hcl line 1:
+   1: [for x in list: upper(x)]
+      |         |     |     |
+      |         |     |     "x", "y"
+      |         |     "X", "Y"
+      |         list of string with 2 elements
+      tuple with 2 elements
+
+`,
+		},
+		{
+			`secret == "hunter2"`,
+			`  on test.hcl line 1:
+   1: secret == "hunter2"
+      |      |
+      |      (sensitive value)
+      (sensitive value)
+
+`,
+		},
+		{
+			`n + a`,
+			`  on test.hcl line 1:
+   1: n + a
+      | | |
+      | | "a"
+      | error: Invalid operand
+      3
+
+`,
+		},
+		{
+			"{\n  a = n + 1\n  b = [a, obj.name]\n}",
+			`  on test.hcl line 1:
+   1: {
+      |
+      object with 2 attributes
+   2:   a = n + 1
+            | |
+            | 4
+            3
+   3:   b = [a, obj.name]
+            ||  |
+            ||  "hello"
+            |"a"
+            tuple with 2 elements
+   4: }
+
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			tracer := hcl.NewEvalTracer()
+			ctx := &hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"a":      cty.StringVal("a"),
+					"n":      cty.NumberIntVal(3),
+					"limit":  cty.NumberIntVal(10),
+					"secret": cty.StringVal("hunter2"),
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"name": cty.StringVal("hello"),
+					}),
+					"list": cty.ListVal([]cty.Value{
+						cty.StringVal("x"),
+						cty.StringVal("y"),
+					}),
+				},
+				Functions: map[string]function.Function{
+					"upper": stdlib.UpperFunc,
+				},
+				Sensitive: []hcl.Traversal{
+					{hcl.TraverseRoot{Name: "secret"}},
+				},
+				Tracer: tracer,
+			}
+
+			src := []byte(test.input)
+			expr, diags := ParseExpression(src, "test.hcl", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected parse errors: %s", diags.Error())
+			}
+			expr.Value(ctx)
+
+			var buf bytes.Buffer
+			files := map[string]*hcl.File{
+				"test.hcl": {Bytes: src},
+			}
+			wr := hcl.NewEvalTraceTextWriter(&buf, files)
+			if err := wr.WriteTraces(tracer.Traces()); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("wrong result\ngot:\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/hashicorp/hcl2/ext/customdecode"
 	"github.com/hashicorp/hcl2/hcl"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
@@ -36,7 +37,10 @@ func (e *LiteralValueExpr) walkChildNodes(w internalWalkFunc) {
 	// Literal values have no child nodes
 }
 
-func (e *LiteralValueExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *LiteralValueExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
+	ctx.ProvenanceTracker().AddRange(e.SrcRange)
 	return e.Val, nil
 }
 
@@ -93,6 +97,34 @@ func (e *LiteralValueExpr) AsTraversal() hcl.Traversal {
 	}
 }
 
+// BadExpr is a placeholder for an expression that could not be parsed. It is
+// produced only by ParseConfigTolerant, in place of the portion of the source
+// that the parser skipped over while recovering from a syntax error.
+//
+// Since the syntax error was already reported by the parser, evaluating a
+// BadExpr produces cty.DynamicVal without any further diagnostics.
+type BadExpr struct {
+	SrcRange hcl.Range
+}
+
+func (e *BadExpr) walkChildNodes(w internalWalkFunc) {
+	// Bad expressions have no child nodes
+}
+
+func (e *BadExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
+	return cty.DynamicVal, nil
+}
+
+func (e *BadExpr) Range() hcl.Range {
+	return e.SrcRange
+}
+
+func (e *BadExpr) StartRange() hcl.Range {
+	return e.SrcRange
+}
+
 // ScopeTraversalExpr is an Expression that retrieves a value from the scope
 // using a traversal.
 type ScopeTraversalExpr struct {
@@ -104,7 +136,10 @@ func (e *ScopeTraversalExpr) walkChildNodes(w internalWalkFunc) {
 	// Scope traversals have no child nodes
 }
 
-func (e *ScopeTraversalExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *ScopeTraversalExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
+	ctx.ProvenanceTracker().AddTraversal(ctx, e.Traversal)
 	val, diags := e.Traversal.TraverseAbs(ctx)
 	setDiagEvalContext(diags, e, ctx)
 	return val, diags
@@ -135,7 +170,9 @@ func (e *RelativeTraversalExpr) walkChildNodes(w internalWalkFunc) {
 	w(e.Source)
 }
 
-func (e *RelativeTraversalExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *RelativeTraversalExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	src, diags := e.Source.Value(ctx)
 	ret, travDiags := e.Traversal.TraverseRel(src)
 	setDiagEvalContext(travDiags, e, ctx)
@@ -186,7 +223,9 @@ func (e *FunctionCallExpr) walkChildNodes(w internalWalkFunc) {
 	}
 }
 
-func (e *FunctionCallExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *FunctionCallExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	var diags hcl.Diagnostics
 
 	var f function.Function
@@ -350,6 +389,19 @@ func (e *FunctionCallExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnosti
 			param = varParam
 		}
 
+		// A parameter whose type has a custom decoder receives its argument
+		// from that decoder instead, without any type conversion. This
+		// allows functions to take the argument expressions themselves.
+		if decodeFn := customdecode.CustomExpressionDecoderForType(param.Type); decodeFn != nil {
+			val, argDiags := decodeFn(argExpr, ctx)
+			diags = append(diags, argDiags...)
+			if val == cty.NilVal {
+				val = cty.UnknownVal(param.Type)
+			}
+			argVals[i] = val
+			continue
+		}
+
 		val, argDiags := argExpr.Value(ctx)
 		if len(argDiags) > 0 {
 			diags = append(diags, argDiags...)
@@ -395,15 +447,19 @@ func (e *FunctionCallExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnosti
 			}
 			argExpr := e.Args[i]
 
+			// Function error messages often include the offending value,
+			// so we can't include the message if the value is sensitive.
+			detail := fmt.Sprintf("Invalid value for %q parameter: %s.", param.Name, err)
+			if hcl.ExprSensitive(argExpr, ctx) {
+				detail = fmt.Sprintf("Invalid value for %q parameter. The error message is not shown because the given value is sensitive.", param.Name)
+			}
+
 			// TODO: we should also unpick a PathError here and show the
 			// path to the deep value where the error was detected.
 			diags = append(diags, &hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Invalid function argument",
-				Detail: fmt.Sprintf(
-					"Invalid value for %q parameter: %s.",
-					param.Name, err,
-				),
+				Severity:    hcl.DiagError,
+				Summary:     "Invalid function argument",
+				Detail:      detail,
 				Subject:     argExpr.StartRange().Ptr(),
 				Context:     e.Range().Ptr(),
 				Expression:  argExpr,
@@ -411,13 +467,15 @@ func (e *FunctionCallExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnosti
 			})
 
 		default:
+			detail := fmt.Sprintf("Call to function %q failed: %s.", e.Name, err)
+			if hcl.ExprSensitive(e, ctx) {
+				detail = fmt.Sprintf("Call to function %q failed. The error message is not shown because the arguments include sensitive values.", e.Name)
+			}
+
 			diags = append(diags, &hcl.Diagnostic{
-				Severity: hcl.DiagError,
-				Summary:  "Error in function call",
-				Detail: fmt.Sprintf(
-					"Call to function %q failed: %s.",
-					e.Name, err,
-				),
+				Severity:    hcl.DiagError,
+				Summary:     "Error in function call",
+				Detail:      detail,
 				Subject:     e.StartRange().Ptr(),
 				Context:     e.Range().Ptr(),
 				Expression:  e,
@@ -468,9 +526,30 @@ func (e *ConditionalExpr) walkChildNodes(w internalWalkFunc) {
 	w(e.FalseResult)
 }
 
-func (e *ConditionalExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
-	trueResult, trueDiags := e.TrueResult.Value(ctx)
-	falseResult, falseDiags := e.FalseResult.Value(ctx)
+func (e *ConditionalExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
+	condResult, condDiags := e.Condition.Value(ctx)
+
+	// If the condition is already decided then we evaluate only the
+	// selected result, so that the other can't produce any errors. We still
+	// need the type of the other result in order to find the result type of
+	// the conditional, so we determine it statically instead, which may give
+	// a less precise result type than evaluating both would.
+	var trueResult, falseResult cty.Value
+	var trueDiags, falseDiags hcl.Diagnostics
+	selected, err := convert.Convert(condResult, cty.Bool)
+	switch {
+	case err != nil || condDiags.HasErrors() || selected.IsNull() || !selected.IsKnown():
+		trueResult, trueDiags = e.TrueResult.Value(ctx)
+		falseResult, falseDiags = e.FalseResult.Value(ctx)
+	case selected.True():
+		trueResult, trueDiags = e.TrueResult.Value(ctx)
+		falseResult = staticResult(e.FalseResult, ctx)
+	default:
+		trueResult = staticResult(e.TrueResult, ctx)
+		falseResult, falseDiags = e.FalseResult.Value(ctx)
+	}
 	var diags hcl.Diagnostics
 
 	resultType := cty.DynamicPseudoType
@@ -523,7 +602,6 @@ func (e *ConditionalExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostic
 		}
 	}
 
-	condResult, condDiags := e.Condition.Value(ctx)
 	diags = append(diags, condDiags...)
 	if condResult.IsNull() {
 		diags = append(diags, &hcl.Diagnostic{
@@ -540,7 +618,7 @@ func (e *ConditionalExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostic
 	if !condResult.IsKnown() {
 		return cty.UnknownVal(resultType), diags
 	}
-	condResult, err := convert.Convert(condResult, cty.Bool)
+	condResult, err = convert.Convert(condResult, cty.Bool)
 	if err != nil {
 		diags = append(diags, &hcl.Diagnostic{
 			Severity:    hcl.DiagError,
@@ -624,7 +702,9 @@ func (e *IndexExpr) walkChildNodes(w internalWalkFunc) {
 	w(e.Key)
 }
 
-func (e *IndexExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *IndexExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	var diags hcl.Diagnostics
 	coll, collDiags := e.Collection.Value(ctx)
 	key, keyDiags := e.Key.Value(ctx)
@@ -658,7 +738,9 @@ func (e *TupleConsExpr) walkChildNodes(w internalWalkFunc) {
 	}
 }
 
-func (e *TupleConsExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *TupleConsExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	var vals []cty.Value
 	var diags hcl.Diagnostics
 
@@ -708,7 +790,9 @@ func (e *ObjectConsExpr) walkChildNodes(w internalWalkFunc) {
 	}
 }
 
-func (e *ObjectConsExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *ObjectConsExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	var vals map[string]cty.Value
 	var diags hcl.Diagnostics
 
@@ -824,7 +908,9 @@ func (e *ObjectConsKeyExpr) walkChildNodes(w internalWalkFunc) {
 	}
 }
 
-func (e *ObjectConsKeyExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *ObjectConsKeyExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	// Because we accept a naked identifier as a literal key rather than a
 	// reference, it's confusing to accept a traversal containing periods
 	// here since we can't tell if the user intends to create a key with
@@ -846,6 +932,7 @@ func (e *ObjectConsKeyExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnost
 	}
 
 	if ln := e.literalName(); ln != "" {
+		ctx.ProvenanceTracker().AddRange(e.Range())
 		return cty.StringVal(ln), nil
 	}
 	return e.Wrapped.Value(ctx)
@@ -896,7 +983,9 @@ type ForExpr struct {
 	CloseRange hcl.Range
 }
 
-func (e *ForExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *ForExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	var diags hcl.Diagnostics
 
 	collVal, collDiags := e.CollExpr.Value(ctx)
@@ -936,17 +1025,16 @@ func (e *ForExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 		return cty.DynamicVal, diags
 	}
 
+	// The iterator symbols derive from the collection, so they are sensitive
+	// if any part of the collection is.
+	collSensitive := hcl.ExprSensitive(e.CollExpr, ctx)
+
 	// Before we start we'll do an early check to see if any CondExpr we've
 	// been given is of the wrong type. This isn't 100% reliable (it may
 	// be DynamicVal until real values are given) but it should catch some
 	// straightforward cases and prevent a barrage of repeated errors.
 	if e.CondExpr != nil {
-		childCtx := ctx.NewChild()
-		childCtx.Variables = map[string]cty.Value{}
-		if e.KeyVar != "" {
-			childCtx.Variables[e.KeyVar] = cty.DynamicVal
-		}
-		childCtx.Variables[e.ValVar] = cty.DynamicVal
+		childCtx := e.iteratorContext(ctx, cty.DynamicVal, cty.DynamicVal, collSensitive)
 
 		result, condDiags := e.CondExpr.Value(childCtx)
 		diags = append(diags, condDiags...)
@@ -995,12 +1083,7 @@ func (e *ForExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 		known := true
 		for it.Next() {
 			k, v := it.Element()
-			childCtx := ctx.NewChild()
-			childCtx.Variables = map[string]cty.Value{}
-			if e.KeyVar != "" {
-				childCtx.Variables[e.KeyVar] = k
-			}
-			childCtx.Variables[e.ValVar] = v
+			childCtx := e.iteratorContext(ctx, k, v, collSensitive)
 
 			if e.CondExpr != nil {
 				includeRaw, condDiags := e.CondExpr.Value(childCtx)
@@ -1095,13 +1178,20 @@ func (e *ForExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 			} else {
 				k := key.AsString()
 				if _, exists := vals[k]; exists {
+					// The key is derived from a sensitive value if either
+					// the collection or the key expression is sensitive, in
+					// which case we can't show it.
+					detail := fmt.Sprintf(
+						"Two different items produced the key %q in this 'for' expression. If duplicates are expected, use the ellipsis (...) after the value expression to enable grouping by key.",
+						k,
+					)
+					if collSensitive || hcl.ExprSensitive(e.KeyExpr, childCtx) {
+						detail = "Two different items produced the same key in this 'for' expression. The key is not shown because it is sensitive. If duplicates are expected, use the ellipsis (...) after the value expression to enable grouping by key."
+					}
 					diags = append(diags, &hcl.Diagnostic{
-						Severity: hcl.DiagError,
-						Summary:  "Duplicate object key",
-						Detail: fmt.Sprintf(
-							"Two different items produced the key %q in this 'for' expression. If duplicates are expected, use the ellipsis (...) after the value expression to enable grouping by key.",
-							k,
-						),
+						Severity:    hcl.DiagError,
+						Summary:     "Duplicate object key",
+						Detail:      detail,
 						Subject:     e.KeyExpr.Range().Ptr(),
 						Context:     &e.SrcRange,
 						Expression:  e.KeyExpr,
@@ -1135,12 +1225,7 @@ func (e *ForExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 		known := true
 		for it.Next() {
 			k, v := it.Element()
-			childCtx := ctx.NewChild()
-			childCtx.Variables = map[string]cty.Value{}
-			if e.KeyVar != "" {
-				childCtx.Variables[e.KeyVar] = k
-			}
-			childCtx.Variables[e.ValVar] = v
+			childCtx := e.iteratorContext(ctx, k, v, collSensitive)
 
 			if e.CondExpr != nil {
 				includeRaw, condDiags := e.CondExpr.Value(childCtx)
@@ -1204,6 +1289,36 @@ func (e *ForExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 	}
 }
 
+// iteratorContext returns a child of the given context in which the
+// iterator symbols are defined with the given key and value, marking them
+// as sensitive if requested.
+func (e *ForExpr) iteratorContext(ctx *hcl.EvalContext, k, v cty.Value, sensitive bool) *hcl.EvalContext {
+	childCtx := ctx.NewChild()
+	childCtx.Variables = map[string]cty.Value{}
+	if e.KeyVar != "" {
+		childCtx.Variables[e.KeyVar] = k
+	}
+	childCtx.Variables[e.ValVar] = v
+
+	if sensitive {
+		for name := range childCtx.Variables {
+			childCtx.Sensitive = append(childCtx.Sensitive, hcl.Traversal{
+				hcl.TraverseRoot{Name: name},
+			})
+		}
+	}
+
+	// The iterator symbols derive from the collection, so they have the
+	// same provenance.
+	if t := ctx.ProvenanceTracker(); t != nil {
+		collProv := t.Provenance(e.CollExpr)
+		for name := range childCtx.Variables {
+			t.SetLocalProvenance(childCtx, name, collProv)
+		}
+	}
+	return childCtx
+}
+
 func (e *ForExpr) walkChildNodes(w internalWalkFunc) {
 	w(e.CollExpr)
 
@@ -1241,6 +1356,66 @@ func (e *ForExpr) StartRange() hcl.Range {
 	return e.OpenRange
 }
 
+// LetExpr is an expression that evaluates its ValueExpr once and makes the
+// result available under the given Name while evaluating its BodyExpr,
+// whose result is the result of the whole expression.
+//
+// A "let" expression with multiple bindings is represented as a nested
+// LetExpr for each binding, so each binding is visible to the value
+// expressions of the bindings that follow it.
+type LetExpr struct {
+	Name      string
+	ValueExpr Expression
+	BodyExpr  Expression
+
+	SrcRange  hcl.Range
+	NameRange hcl.Range
+}
+
+func (e *LetExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
+	var diags hcl.Diagnostics
+
+	val, valDiags := e.ValueExpr.Value(ctx)
+	diags = append(diags, valDiags...)
+
+	childCtx := ctx.NewChild()
+	childCtx.Variables = map[string]cty.Value{
+		e.Name: val,
+	}
+	if hcl.ExprSensitive(e.ValueExpr, ctx) {
+		childCtx.Sensitive = []hcl.Traversal{
+			{hcl.TraverseRoot{Name: e.Name}},
+		}
+	}
+	if t := ctx.ProvenanceTracker(); t != nil {
+		t.SetLocalProvenance(childCtx, e.Name, t.Provenance(e.ValueExpr))
+	}
+
+	result, resultDiags := e.BodyExpr.Value(childCtx)
+	diags = append(diags, resultDiags...)
+	return result, diags
+}
+
+func (e *LetExpr) walkChildNodes(w internalWalkFunc) {
+	w(e.ValueExpr)
+	w(ChildScope{
+		LocalNames: map[string]struct{}{
+			e.Name: struct{}{},
+		},
+		Expr: e.BodyExpr,
+	})
+}
+
+func (e *LetExpr) Range() hcl.Range {
+	return e.SrcRange
+}
+
+func (e *LetExpr) StartRange() hcl.Range {
+	return e.NameRange
+}
+
 type SplatExpr struct {
 	Source Expression
 	Each   Expression
@@ -1250,7 +1425,9 @@ type SplatExpr struct {
 	MarkerRange hcl.Range
 }
 
-func (e *SplatExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *SplatExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	sourceVal, diags := e.Source.Value(ctx)
 	if diags.HasErrors() {
 		// We'll evaluate our "Each" expression here just to see if it
@@ -1412,7 +1589,9 @@ type AnonSymbolExpr struct {
 	valuesLock sync.RWMutex
 }
 
-func (e *AnonSymbolExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *AnonSymbolExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	if ctx == nil {
 		return cty.DynamicVal, nil
 	}
@@ -79,8 +79,61 @@ var (
 		Impl: stdlib.NegateFunc,
 		Type: cty.Number,
 	}
+
+	OpNullCoalesce = &Operation{
+		Impl: nullCoalesceFunc,
+		Type: cty.DynamicPseudoType,
+	}
 )
 
+// nullCoalesceFunc is the implementation of the null-coalescing operator,
+// which returns its left operand unless it is null, in which case it returns
+// its right operand.
+var nullCoalesceFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "a",
+			Type:             cty.DynamicPseudoType,
+			AllowNull:        true,
+			AllowUnknown:     true,
+			AllowDynamicType: true,
+		},
+		{
+			Name:             "b",
+			Type:             cty.DynamicPseudoType,
+			AllowNull:        true,
+			AllowUnknown:     true,
+			AllowDynamicType: true,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		switch {
+		case !args[0].IsKnown():
+			// We don't know which operand will be selected, so we can only
+			// predict the result type if both operands agree.
+			ty, _ := convert.UnifyUnsafe([]cty.Type{args[0].Type(), args[1].Type()})
+			if ty == cty.NilType {
+				return cty.DynamicPseudoType, nil
+			}
+			return ty, nil
+		case args[0].IsNull():
+			return args[1].Type(), nil
+		default:
+			return args[0].Type(), nil
+		}
+	},
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		switch {
+		case !args[0].IsKnown():
+			return cty.UnknownVal(retType), nil
+		case args[0].IsNull():
+			return args[1], nil
+		default:
+			return args[0], nil
+		}
+	},
+})
+
 var binaryOps []map[TokenType]*Operation
 
 func init() {
@@ -92,6 +145,9 @@ func init() {
 	// the *lowest* precedence first. Operators within the same group
 	// have left-to-right associativity.
 	binaryOps = []map[TokenType]*Operation{
+		{
+			TokenDoubleQuestion: OpNullCoalesce,
+		},
 		{
 			TokenOr: OpLogicalOr,
 		},
@@ -133,7 +189,9 @@ func (e *BinaryOpExpr) walkChildNodes(w internalWalkFunc) {
 	w(e.RHS)
 }
 
-func (e *BinaryOpExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *BinaryOpExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	impl := e.Op.Impl // assumed to be a function taking exactly two arguments
 	params := impl.Params()
 	lhsParam := params[0]
@@ -142,6 +200,26 @@ func (e *BinaryOpExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics)
 	var diags hcl.Diagnostics
 
 	givenLHSVal, lhsDiags := e.LHS.Value(ctx)
+
+	// The logical operators short-circuit: if the left operand alone
+	// decides the result then the right operand is not evaluated at all,
+	// which allows it to rely on a condition tested by the left operand.
+	// The null-coalescing operator does the same when its left operand
+	// isn't null.
+	if e.Op == OpNullCoalesce && !lhsDiags.HasErrors() {
+		if givenLHSVal.IsKnown() && !givenLHSVal.IsNull() {
+			return givenLHSVal, lhsDiags
+		}
+	}
+	if (e.Op == OpLogicalAnd || e.Op == OpLogicalOr) && !lhsDiags.HasErrors() {
+		lhsVal, err := convert.Convert(givenLHSVal, cty.Bool)
+		if err == nil && lhsVal.IsKnown() && !lhsVal.IsNull() {
+			if (e.Op == OpLogicalAnd && lhsVal.False()) || (e.Op == OpLogicalOr && lhsVal.True()) {
+				return lhsVal, lhsDiags
+			}
+		}
+	}
+
 	givenRHSVal, rhsDiags := e.RHS.Value(ctx)
 	diags = append(diags, lhsDiags...)
 	diags = append(diags, rhsDiags...)
@@ -215,7 +293,9 @@ func (e *UnaryOpExpr) walkChildNodes(w internalWalkFunc) {
 	w(e.Val)
 }
 
-func (e *UnaryOpExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *UnaryOpExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	impl := e.Op.Impl // assumed to be a function taking exactly one argument
 	params := impl.Params()
 	param := params[0]
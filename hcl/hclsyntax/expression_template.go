@@ -21,7 +21,9 @@ func (e *TemplateExpr) walkChildNodes(w internalWalkFunc) {
 	}
 }
 
-func (e *TemplateExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *TemplateExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	buf := &bytes.Buffer{}
 	var diags hcl.Diagnostics
 	isKnown := true
@@ -121,7 +123,9 @@ func (e *TemplateJoinExpr) walkChildNodes(w internalWalkFunc) {
 	w(e.Tuple)
 }
 
-func (e *TemplateJoinExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *TemplateJoinExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	tuple, diags := e.Tuple.Value(ctx)
 
 	if tuple.IsNull() {
@@ -207,7 +211,9 @@ func (e *TemplateWrapExpr) walkChildNodes(w internalWalkFunc) {
 	w(e.Wrapped)
 }
 
-func (e *TemplateWrapExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+func (e *TemplateWrapExpr) Value(ctx *hcl.EvalContext) (ret cty.Value, retDiags hcl.Diagnostics) {
+	defer observeEval(e, ctx)(&ret, &retDiags)
+
 	return e.Wrapped.Value(ctx)
 }
 
@@ -1,10 +1,15 @@
 package hclsyntax
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/hcl2/ext/customdecode"
 	"github.com/hashicorp/hcl2/hcl"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 	"github.com/zclconf/go-cty/cty/function"
 	"github.com/zclconf/go-cty/cty/function/stdlib"
 )
@@ -694,6 +699,101 @@ upper(
 			cty.DynamicVal,
 			1, // can't iterate over a string
 		},
+		{
+			`let x = 2 in x * 3`,
+			nil,
+			cty.NumberIntVal(6),
+			0,
+		},
+		{
+			`let a = 1, b = a + 1 in a + b`,
+			nil,
+			cty.NumberIntVal(3),
+			0,
+		},
+		{
+			`let x = 1 in let x = x + 1 in x`,
+			nil,
+			cty.NumberIntVal(2),
+			0,
+		},
+		{
+			`1 + let x = 2 in x * 3`,
+			nil,
+			cty.NumberIntVal(7),
+			0,
+		},
+		{
+			`let m = lookup(m, "k", {}) in "${m.a}-${m.b}"`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"m": cty.MapVal(map[string]cty.Value{
+						"k": cty.ObjectVal(map[string]cty.Value{
+							"a": cty.StringVal("x"),
+							"b": cty.StringVal("y"),
+						}),
+					}),
+				},
+				Functions: map[string]function.Function{
+					"lookup": function.New(&function.Spec{
+						Params: []function.Parameter{
+							{Name: "m", Type: cty.DynamicPseudoType},
+							{Name: "k", Type: cty.String},
+							{Name: "default", Type: cty.DynamicPseudoType},
+						},
+						Type: function.StaticReturnType(cty.DynamicPseudoType),
+						Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+							return args[0].Index(args[1]), nil
+						},
+					}),
+				},
+			},
+			cty.StringVal("x-y"),
+			0,
+		},
+		{
+			`[let x = "inner" in x, x, y]`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"x": cty.StringVal("outer"),
+					"y": cty.StringVal("y"),
+				},
+			},
+			cty.TupleVal([]cty.Value{
+				cty.StringVal("inner"),
+				cty.StringVal("outer"),
+				cty.StringVal("y"),
+			}),
+			0,
+		},
+		{
+			`let + 1`, // "let" is not reserved when it isn't introducing a binding
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"let": cty.NumberIntVal(1),
+				},
+			},
+			cty.NumberIntVal(2),
+			0,
+		},
+		{
+			`let x = nope in x`,
+			nil,
+			cty.DynamicVal,
+			1, // variables not allowed
+		},
+		{
+			`let x = 1 x`,
+			nil,
+			cty.DynamicVal,
+			1, // missing "in" keyword
+		},
+		{
+			`let x = 1, in x`,
+			nil,
+			cty.DynamicVal,
+			1, // missing name after comma
+		},
 		{
 			`[for v in null: v]`,
 			nil,
@@ -1299,6 +1399,58 @@ EOT
 			cty.TupleVal([]cty.Value{cty.StringVal("Foo\n\nBar\n\nBaz\n")}),
 			0,
 		},
+		{
+			`
+<<EOT
+Foo
+%{ for x in bars ~}
+  ${x}
+%{ endfor ~}
+Baz
+EOT
+`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"bars": cty.ListVal([]cty.Value{
+						cty.StringVal("Bar"),
+						cty.StringVal("Bar"),
+					}),
+				},
+			},
+			cty.StringVal("Foo\nBar\nBar\nBaz\n"),
+			0,
+		},
+		{
+			`
+<<EOT
+Foo
+
+  %{~ if true ~}
+
+  Bar
+  %{~ endif }
+Baz
+EOT
+`,
+			nil,
+			cty.StringVal("FooBar\nBaz\n"),
+			0,
+		},
+		{
+			`[
+  <<-EOT
+    Foo
+    %{~ if true ~}
+      Bar
+    %{ endif ~}
+    Baz
+  EOT
+]
+`,
+			nil,
+			cty.TupleVal([]cty.Value{cty.StringVal("FooBar\nBaz\n")}),
+			0,
+		},
 
 		{
 			`unk["baz"]`,
@@ -1476,6 +1628,371 @@ EOT
 			cty.DynamicVal,
 			0,
 		},
+		{
+			`x != null ? x.a : "default"`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"x": cty.NullVal(cty.Object(map[string]cty.Type{"a": cty.String})),
+				},
+			},
+			cty.StringVal("default"),
+			0,
+		},
+		{
+			`x == null ? "default" : x.a`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"x": cty.NullVal(cty.Object(map[string]cty.Type{"a": cty.String})),
+				},
+			},
+			cty.StringVal("default"),
+			0,
+		},
+		{
+			`true ? 1 : [1]`, // the results must still have consistent types
+			nil,
+			cty.DynamicVal,
+			1,
+		},
+		{
+			`unk ? x.a : 1`, // both results are evaluated for an unknown condition
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"unk": cty.UnknownVal(cty.Bool),
+					"x":   cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("A")}),
+				},
+			},
+			cty.UnknownVal(cty.String),
+			0,
+		},
+		{
+			`unk ? x.a : [1]`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"unk": cty.UnknownVal(cty.Bool),
+					"x":   cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("A")}),
+				},
+			},
+			cty.DynamicVal,
+			1, // Inconsistent conditional result types
+		},
+		{
+			`x != null && x.enabled`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"x": cty.NullVal(cty.Object(map[string]cty.Type{"enabled": cty.Bool})),
+				},
+			},
+			cty.False,
+			0,
+		},
+		{
+			`x != null && x.enabled`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"x": cty.ObjectVal(map[string]cty.Value{"enabled": cty.True}),
+				},
+			},
+			cty.True,
+			0,
+		},
+		{
+			`x == null || x.enabled`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"x": cty.NullVal(cty.Object(map[string]cty.Type{"enabled": cty.Bool})),
+				},
+			},
+			cty.True,
+			0,
+		},
+		{
+			`x == null || x.enabled`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"x": cty.ObjectVal(map[string]cty.Value{"enabled": cty.False}),
+				},
+			},
+			cty.False,
+			0,
+		},
+		{
+			`true && x.nope`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"x": cty.EmptyObjectVal,
+				},
+			},
+			cty.UnknownVal(cty.Bool),
+			1, // Unsupported attribute
+		},
+		{
+			`unk && x.nope`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"unk": cty.UnknownVal(cty.Bool),
+					"x":   cty.EmptyObjectVal,
+				},
+			},
+			cty.UnknownVal(cty.Bool),
+			1, // Unsupported attribute
+		},
+		{
+			`"true" || x.nope`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"x": cty.EmptyObjectVal,
+				},
+			},
+			cty.True,
+			0,
+		},
+		{
+			`null ?? 1`,
+			nil,
+			cty.NumberIntVal(1),
+			0,
+		},
+		{
+			`"a" ?? 1`,
+			nil,
+			cty.StringVal("a"),
+			0,
+		},
+		{
+			`null ?? null ?? 2`,
+			nil,
+			cty.NumberIntVal(2),
+			0,
+		},
+		{
+			`1 + 1 ?? 5`,
+			nil,
+			cty.NumberIntVal(2),
+			0,
+		},
+		{
+			`false || true ?? false`,
+			nil,
+			cty.True,
+			0,
+		},
+		{
+			`obj.n ?? "default"`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.StringVal("default"),
+			0,
+		},
+		{
+			`unk ?? "default"`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.UnknownVal(cty.String),
+			0,
+		},
+		{
+			`obj?.a`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.StringVal("hello"),
+			0,
+		},
+		{
+			`obj?.missing`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.NullVal(cty.DynamicPseudoType),
+			0,
+		},
+		{
+			`obj?.missing.b`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.NullVal(cty.DynamicPseudoType),
+			0,
+		},
+		{
+			`obj.n?.b`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.NullVal(cty.DynamicPseudoType),
+			0,
+		},
+		{
+			`nil?.a`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.NullVal(cty.DynamicPseudoType),
+			0,
+		},
+		{
+			`m?.a`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.StringVal("x"),
+			0,
+		},
+		{
+			`m?.b`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.NullVal(cty.DynamicPseudoType),
+			0,
+		},
+		{
+			`obj?.missing ?? "default"`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.StringVal("default"),
+			0,
+		},
+		{
+			`obj.missing`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.DynamicVal,
+			1,
+		},
+		{
+			`nil.a`,
+			&hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"obj": cty.ObjectVal(map[string]cty.Value{
+						"a": cty.StringVal("hello"),
+						"n": cty.NullVal(cty.Object(map[string]cty.Type{
+							"b": cty.String,
+						})),
+					}),
+					"m":   cty.MapVal(map[string]cty.Value{"a": cty.StringVal("x")}),
+					"nil": cty.NullVal(cty.DynamicPseudoType),
+					"unk": cty.UnknownVal(cty.String),
+				},
+			},
+			cty.DynamicVal,
+			1,
+		},
 	}
 
 	for _, test := range tests {
@@ -1504,6 +2021,173 @@ EOT
 
 }
 
+func TestExpressionShortCircuit(t *testing.T) {
+	// The results of these expressions should be decided without calling
+	// the "boom" function.
+	tests := []struct {
+		input string
+		want  cty.Value
+	}{
+		{`false && boom()`, cty.False},
+		{`true || boom()`, cty.True},
+		{`true ? "a" : boom()`, cty.StringVal("a")},
+		{`false ? boom() : "b"`, cty.StringVal("b")},
+		{`!(false && boom())`, cty.True},
+		{`"a" ?? boom()`, cty.StringVal("a")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			calls := 0
+			ctx := &hcl.EvalContext{
+				Functions: map[string]function.Function{
+					"boom": function.New(&function.Spec{
+						Type: function.StaticReturnType(cty.String),
+						Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+							calls++
+							return cty.StringVal("boom"), nil
+						},
+					}),
+				},
+			}
+
+			expr, diags := ParseExpression([]byte(test.input), "", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected parse errors: %s", diags.Error())
+			}
+			got, diags := expr.Value(ctx)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+
+			if !got.RawEquals(test.want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+			if calls != 0 {
+				t.Errorf("boom was called %d times; want 0", calls)
+			}
+		})
+	}
+}
+
+func TestConditionalExprResultType(t *testing.T) {
+	// When the condition is already decided, the result that isn't selected
+	// is never evaluated, and so if its type can't be determined statically
+	// then the selected result is returned without conversion.
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"l": cty.ListVal([]cty.Value{cty.StringVal("c")}),
+		},
+	}
+	tests := []struct {
+		input string
+		want  cty.Value
+	}{
+		{
+			`true ? ["a", "b"] : [for s in l: s]`,
+			cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+		},
+		{
+			`false ? ["a", "b"] : [for s in l: s]`,
+			cty.ListVal([]cty.Value{cty.StringVal("c")}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			expr, diags := ParseExpression([]byte(test.input), "", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected parse errors: %s", diags.Error())
+			}
+			got, diags := expr.Value(ctx)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+
+			if !got.RawEquals(test.want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConditionalExprUnselectedResult(t *testing.T) {
+	// The result that isn't selected must not be evaluated at all, even
+	// when its type can't be determined statically, so it must not call
+	// any functions or contribute to the provenance or traces of the
+	// result.
+	calls := 0
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"v": cty.StringVal("secret"),
+		},
+		Functions: map[string]function.Function{
+			"boom": function.New(&function.Spec{
+				Params: []function.Parameter{
+					{
+						Name: "val",
+						Type: cty.String,
+					},
+				},
+				Type: function.StaticReturnType(cty.DynamicPseudoType),
+				Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+					calls++
+					return args[0], nil
+				},
+			}),
+		},
+		Provenance: hcl.NewProvenanceTracker(),
+		Tracer:     hcl.NewEvalTracer(),
+	}
+
+	for _, input := range []string{`true ? "a" : boom(v)`, `false ? boom(v) : "a"`} {
+		t.Run(input, func(t *testing.T) {
+			calls = 0
+			ctx.Tracer.Reset()
+			expr, diags := ParseExpression([]byte(input), "", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected parse errors: %s", diags.Error())
+			}
+			got, diags := expr.Value(ctx)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+			if want := cty.StringVal("a"); !got.RawEquals(want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+			}
+
+			if calls != 0 {
+				t.Errorf("function in unselected result was called %d times", calls)
+			}
+
+			prov := ctx.Provenance.Provenance(expr)
+			for _, traversal := range prov.Traversals {
+				if traversal.RootName() == "v" {
+					t.Errorf("provenance includes %s", traversal.SourceRange())
+				}
+			}
+			for _, rng := range prov.Ranges {
+				if src := input[rng.Start.Byte:rng.End.Byte]; src == "v" || src == "boom(v)" {
+					t.Errorf("provenance includes %s", src)
+				}
+			}
+
+			var checkTrace func(trace *hcl.EvalTrace)
+			checkTrace = func(trace *hcl.EvalTrace) {
+				if _, ok := trace.Expr.(*FunctionCallExpr); ok {
+					t.Errorf("trace includes %s", trace.Expr.Range())
+				}
+				for _, child := range trace.Children {
+					checkTrace(child)
+				}
+			}
+			for _, trace := range ctx.Tracer.Traces() {
+				checkTrace(trace)
+			}
+		})
+	}
+}
+
 func TestFunctionCallExprValue(t *testing.T) {
 	funcs := map[string]function.Function{
 		"length":     stdlib.StrlenFunc,
@@ -1682,6 +2366,163 @@ func TestFunctionCallExprValue(t *testing.T) {
 	}
 }
 
+func TestFunctionCallExprCustomDecode(t *testing.T) {
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"a": cty.StringVal("hello"),
+		},
+		Functions: map[string]function.Function{
+			// varcount returns the number of variables its argument refers
+			// to, without evaluating it.
+			"varcount": function.New(&function.Spec{
+				Params: []function.Parameter{
+					{
+						Name: "expr",
+						Type: customdecode.ExpressionType,
+					},
+				},
+				Type: function.StaticReturnType(cty.Number),
+				Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+					expr := customdecode.ExpressionFromVal(args[0])
+					return cty.NumberIntVal(int64(len(expr.Variables()))), nil
+				},
+			}),
+			// orempty evaluates its argument, returning an empty string if
+			// evaluation fails.
+			"orempty": function.New(&function.Spec{
+				Params: []function.Parameter{
+					{
+						Name: "expr",
+						Type: customdecode.ExpressionClosureType,
+					},
+				},
+				Type: function.StaticReturnType(cty.String),
+				Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+					v, diags := customdecode.ExpressionClosureFromVal(args[0]).Value()
+					if diags.HasErrors() {
+						return cty.StringVal(""), nil
+					}
+					return convert.Convert(v, cty.String)
+				},
+			}),
+		},
+	}
+
+	tests := []struct {
+		input     string
+		want      cty.Value
+		diagCount int
+	}{
+		{`varcount(a)`, cty.NumberIntVal(1), 0},
+		{`varcount(nope.foo + a)`, cty.NumberIntVal(2), 0},
+		{`varcount(a, a)`, cty.DynamicVal, 1}, // too many arguments
+		{`orempty(a)`, cty.StringVal("hello"), 0},
+		{`orempty(nope)`, cty.StringVal(""), 0},
+		{`orempty(a.nope)`, cty.StringVal(""), 0},
+		{`"${orempty(a)} world"`, cty.StringVal("hello world"), 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			expr, diags := ParseExpression([]byte(test.input), "", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected parse errors: %s", diags.Error())
+			}
+			got, diags := expr.Value(ctx)
+			if len(diags) != test.diagCount {
+				t.Errorf("wrong number of diagnostics %d; want %d", len(diags), test.diagCount)
+				for _, diag := range diags {
+					t.Logf("- %s", diag.Error())
+				}
+			}
+			if !got.RawEquals(test.want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestExpressionSensitive(t *testing.T) {
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"secret":  cty.StringVal("hunter2"),
+			"secrets": cty.ListVal([]cty.Value{cty.StringVal("hunter2")}),
+			"dups":    cty.ListVal([]cty.Value{cty.StringVal("hunter2"), cty.StringVal("hunter2")}),
+			"public":  cty.StringVal("hello"),
+		},
+		Sensitive: []hcl.Traversal{
+			{hcl.TraverseRoot{Name: "secret"}},
+			{hcl.TraverseRoot{Name: "secrets"}},
+			{hcl.TraverseRoot{Name: "dups"}},
+		},
+		Functions: map[string]function.Function{
+			// These functions include their argument in their errors.
+			"failarg": function.New(&function.Spec{
+				Params: []function.Parameter{{Name: "v", Type: cty.String}},
+				Type:   function.StaticReturnType(cty.String),
+				Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+					return cty.DynamicVal, function.NewArgErrorf(0, "bad value %q", args[0].AsString())
+				},
+			}),
+			"fail": function.New(&function.Spec{
+				Params: []function.Parameter{{Name: "v", Type: cty.String}},
+				Type:   function.StaticReturnType(cty.String),
+				Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+					return cty.DynamicVal, fmt.Errorf("bad value %q", args[0].AsString())
+				},
+			}),
+		},
+	}
+
+	tests := []struct {
+		input         string
+		wantSensitive bool
+		wantErrs      bool
+	}{
+		{`public`, false, false},
+		{`"${public}!"`, false, false},
+		{`"${secret}!"`, true, false},
+		{`[for s in secrets: "${s}!"]`, true, false},
+		{`secret + 1`, true, true},
+		{`[for s in secrets: s + 1]`, true, true},
+		{`{for i, s in secrets: s => i + s}`, true, true},
+		{`{for s in dups: s => 1}`, true, true},
+		{`{for s in ["a", "b"]: secret => s}`, true, true},
+		{`let s = secret in s + 1`, true, true},
+		{`let s = public in s + 1`, false, true},
+		{`failarg(secret)`, true, true},
+		{`fail(secret)`, true, true},
+		{`fail("${public}${secret}")`, true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			expr, diags := ParseExpression([]byte(test.input), "", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected parse errors: %s", diags.Error())
+			}
+
+			if got := hcl.ExprSensitive(expr, ctx); got != test.wantSensitive {
+				t.Errorf("wrong ExprSensitive result %#v; want %#v", got, test.wantSensitive)
+			}
+
+			_, diags = expr.Value(ctx)
+			if got := diags.HasErrors(); got != test.wantErrs {
+				t.Fatalf("wrong error result %#v; want %#v\n%s", got, test.wantErrs, diags.Error())
+			}
+
+			var buf bytes.Buffer
+			wr := hcl.NewDiagnosticTextWriter(&buf, nil, 0, false)
+			if err := wr.WriteDiagnostics(diags); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); strings.Contains(got, "hunter2") {
+				t.Errorf("diagnostics include sensitive value:\n%s", got)
+			}
+		})
+	}
+}
+
 func TestExpressionAsTraversal(t *testing.T) {
 	expr, _ := ParseExpression([]byte("a.b[0][\"c\"]"), "", hcl.Pos{})
 	traversal, diags := hcl.AbsTraversalForExpr(expr)
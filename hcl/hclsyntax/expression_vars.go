@@ -11,6 +11,10 @@ func (e *AnonSymbolExpr) Variables() []hcl.Traversal {
 	return Variables(e)
 }
 
+func (e *BadExpr) Variables() []hcl.Traversal {
+	return Variables(e)
+}
+
 func (e *BinaryOpExpr) Variables() []hcl.Traversal {
 	return Variables(e)
 }
@@ -31,6 +35,10 @@ func (e *IndexExpr) Variables() []hcl.Traversal {
 	return Variables(e)
 }
 
+func (e *LetExpr) Variables() []hcl.Traversal {
+	return Variables(e)
+}
+
 func (e *LiteralValueExpr) Variables() []hcl.Traversal {
 	return Variables(e)
 }
@@ -12,6 +12,7 @@ var ifKeyword = Keyword([]byte{'i', 'f'})
 var elseKeyword = Keyword([]byte{'e', 'l', 's', 'e'})
 var endifKeyword = Keyword([]byte{'e', 'n', 'd', 'i', 'f'})
 var endforKeyword = Keyword([]byte{'e', 'n', 'd', 'f', 'o', 'r'})
+var letKeyword = Keyword([]byte{'l', 'e', 't'})
 
 func (kw Keyword) TokenMatches(token Token) bool {
 	if token.Type != TokenIdent {
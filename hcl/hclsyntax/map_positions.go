@@ -0,0 +1,113 @@
+package hclsyntax
+
+import (
+	"reflect"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// MapExpressionPositions returns a deep copy of the given expression with
+// each of the source positions within it replaced by the result of calling
+// the given function with the original position.
+//
+// This is intended for callers that parse an expression from text that was
+// extracted from some other source, such as a string in another language,
+// so that the positions in the result can refer to that original source.
+func MapExpressionPositions(expr Expression, fn func(hcl.Pos) hcl.Pos) Expression {
+	if expr == nil {
+		return nil
+	}
+	return newPosMapper(fn).Map(expr).(Expression)
+}
+
+// posMapper produces deep copies of AST nodes with each of their source
+// positions converted by a function.
+type posMapper struct {
+	fn func(hcl.Pos) hcl.Pos
+
+	// seen tracks pointers already copied, so that nodes referenced from
+	// more than one place in the tree (such as the AnonSymbolExpr in a
+	// SplatExpr) remain shared in the copy.
+	seen map[uintptr]reflect.Value
+}
+
+var (
+	posType   = reflect.TypeOf(hcl.Pos{})
+	valueType = reflect.TypeOf(cty.Value{})
+	typeType  = reflect.TypeOf(cty.Type{})
+)
+
+func newPosMapper(fn func(hcl.Pos) hcl.Pos) *posMapper {
+	return &posMapper{
+		fn:   fn,
+		seen: make(map[uintptr]reflect.Value),
+	}
+}
+
+func (m *posMapper) Map(v interface{}) interface{} {
+	return m.mapValue(reflect.ValueOf(v)).Interface()
+}
+
+func (m *posMapper) mapValue(v reflect.Value) reflect.Value {
+	switch v.Type() {
+	case posType:
+		return reflect.ValueOf(m.fn(v.Interface().(hcl.Pos)))
+	case valueType, typeType:
+		// cty values are immutable and contain no source positions
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		if existing, ok := m.seen[v.Pointer()]; ok {
+			return existing
+		}
+		ret := reflect.New(v.Type().Elem())
+		m.seen[v.Pointer()] = ret
+		ret.Elem().Set(m.mapValue(v.Elem()))
+		return ret
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		ret := reflect.New(v.Type()).Elem()
+		ret.Set(m.mapValue(v.Elem()))
+		return ret
+	case reflect.Struct:
+		ret := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				// Unexported fields are only used for transient state
+				// that is not present in a freshly-parsed AST.
+				continue
+			}
+			ret.Field(i).Set(m.mapValue(v.Field(i)))
+		}
+		return ret
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		ret := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			ret.Index(i).Set(m.mapValue(v.Index(i)))
+		}
+		return ret
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		ret := reflect.MakeMapWithSize(v.Type(), v.Len())
+		it := v.MapRange()
+		for it.Next() {
+			ret.SetMapIndex(it.Key(), m.mapValue(it.Value()))
+		}
+		return ret
+	default:
+		return v
+	}
+}
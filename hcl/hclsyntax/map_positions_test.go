@@ -0,0 +1,40 @@
+package hclsyntax
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+func TestMapExpressionPositions(t *testing.T) {
+	expr, diags := ParseExpression([]byte(`a[*].b + upper("c")`), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", diags.Error())
+	}
+
+	got := MapExpressionPositions(expr, func(pos hcl.Pos) hcl.Pos {
+		pos.Line += 2
+		pos.Byte += 10
+		return pos
+	})
+
+	want := expr.Range()
+	want.Start.Line += 2
+	want.Start.Byte += 10
+	want.End.Line += 2
+	want.End.Byte += 10
+	if got.Range() != want {
+		t.Errorf("wrong range\ngot:  %#v\nwant: %#v", got.Range(), want)
+	}
+	if got.Range() == expr.Range() {
+		t.Errorf("original expression was modified")
+	}
+
+	// The splat expression's symbol is referenced from two places, and the
+	// copy must preserve that sharing.
+	splat := got.(*BinaryOpExpr).LHS.(*SplatExpr)
+	each := splat.Each.(*RelativeTraversalExpr)
+	if each.Source != Expression(splat.Item) {
+		t.Errorf("splat item is not shared with its each expression")
+	}
+}
@@ -0,0 +1,189 @@
+package hclsyntax
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// numberLitError describes a problem with the source of a number literal.
+type numberLitError struct {
+	// Offset is the byte offset of the problematic character within the
+	// literal, or the length of the literal if the problem is that it
+	// ends prematurely.
+	Offset int
+
+	Detail string
+}
+
+// parseNumberLit interprets the source of a number literal token, which is
+// either a decimal number, with optional fractional part and exponent, or an
+// integer in hexadecimal, octal or binary notation, introduced by the
+// prefixes 0x, 0o and 0b respectively. In all cases the digits may be
+// separated by single underscores for readability.
+func parseNumberLit(src []byte) (cty.Value, *numberLitError) {
+	base := 10
+	if len(src) >= 2 && src[0] == '0' {
+		switch src[1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+	}
+
+	if base != 10 {
+		digits, i, err := scanNumberLitDigits(src, 2, base)
+		if err != nil {
+			return cty.UnknownVal(cty.Number), err
+		}
+		if i < len(src) {
+			return cty.UnknownVal(cty.Number), numberLitUnexpected(src, i, base)
+		}
+		n, ok := new(big.Int).SetString(digits, base)
+		if !ok {
+			// Should never happen, because we validated the digits above.
+			return cty.UnknownVal(cty.Number), &numberLitError{
+				Offset: 0,
+				Detail: "Failed to recognize the value of this number literal.",
+			}
+		}
+		return cty.NumberVal(new(big.Float).SetInt(n)), nil
+	}
+
+	digits, i, err := scanNumberLitDigits(src, 0, 10)
+	if err != nil {
+		return cty.UnknownVal(cty.Number), err
+	}
+	clean := digits
+	if i < len(src) && src[i] == '.' {
+		digits, i, err = scanNumberLitDigits(src, i+1, 10)
+		if err != nil {
+			return cty.UnknownVal(cty.Number), err
+		}
+		clean += "." + digits
+	}
+	if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		clean += "e"
+		i++
+		if i < len(src) && (src[i] == '+' || src[i] == '-') {
+			clean += string(src[i])
+			i++
+		}
+		digits, i, err = scanNumberLitDigits(src, i, 10)
+		if err != nil {
+			return cty.UnknownVal(cty.Number), err
+		}
+		clean += digits
+	}
+	if i < len(src) {
+		return cty.UnknownVal(cty.Number), numberLitUnexpected(src, i, 10)
+	}
+
+	// The cty.ParseNumberVal is always the same behavior as converting a
+	// string to a number, ensuring we always interpret decimal numbers in
+	// the same way.
+	numVal, parseErr := cty.ParseNumberVal(clean)
+	if parseErr != nil {
+		return cty.UnknownVal(cty.Number), &numberLitError{
+			Offset: 0,
+			Detail: "Failed to recognize the value of this number literal.",
+		}
+	}
+	return numVal, nil
+}
+
+// scanNumberLitDigits reads a non-empty sequence of digits in the given base
+// from src starting at offset i, with optional single underscores between
+// them. It returns the digits with the underscores removed and the offset
+// of the first byte after the sequence.
+func scanNumberLitDigits(src []byte, i int, base int) (string, int, *numberLitError) {
+	start := i
+	var digits []byte
+	for i < len(src) {
+		c := src[i]
+		if c == '_' {
+			if i == start || i+1 >= len(src) || !numberLitDigit(src[i+1], base) {
+				return "", i, &numberLitError{
+					Offset: i,
+					Detail: "An underscore in a number literal must be between two digits.",
+				}
+			}
+			i++
+			continue
+		}
+		if !numberLitDigit(c, base) {
+			break
+		}
+		digits = append(digits, c)
+		i++
+	}
+	if len(digits) == 0 {
+		if i < len(src) && numberLitDigit(src[i], 16) {
+			// A digit that's just not valid for this base, which we'll
+			// report more specifically.
+			return "", i, numberLitUnexpected(src, i, base)
+		}
+		return "", i, &numberLitError{
+			Offset: i,
+			Detail: "Expected a digit here.",
+		}
+	}
+	return string(digits), i, nil
+}
+
+// numberLitUnexpected returns an error describing the unexpected character
+// at offset i in src, which was found where a digit in the given base or
+// the end of the literal was expected.
+func numberLitUnexpected(src []byte, i int, base int) *numberLitError {
+	c := src[i]
+	switch {
+	case c == '.' && base != 10:
+		return &numberLitError{
+			Offset: i,
+			Detail: fmt.Sprintf("A number in %s notation must be a whole number.", numberLitBaseName(base)),
+		}
+	case numberLitDigit(c, 16) && base != 10:
+		return &numberLitError{
+			Offset: i,
+			Detail: fmt.Sprintf("The digit %q is not valid in a number in %s notation.", c, numberLitBaseName(base)),
+		}
+	case c < 0x80:
+		return &numberLitError{
+			Offset: i,
+			Detail: fmt.Sprintf("The character %q is not valid in a number in %s notation.", c, numberLitBaseName(base)),
+		}
+	default:
+		return &numberLitError{
+			Offset: i,
+			Detail: fmt.Sprintf("This character is not valid in a number in %s notation.", numberLitBaseName(base)),
+		}
+	}
+}
+
+func numberLitDigit(c byte, base int) bool {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c-'0') < base
+	case c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
+		return base == 16
+	default:
+		return false
+	}
+}
+
+func numberLitBaseName(base int) string {
+	switch base {
+	case 16:
+		return "hexadecimal"
+	case 8:
+		return "octal"
+	case 2:
+		return "binary"
+	default:
+		return "decimal"
+	}
+}
@@ -0,0 +1,130 @@
+package hclsyntax
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestNumberLit(t *testing.T) {
+	tests := []struct {
+		input string
+		want  cty.Value
+	}{
+		{`0`, cty.NumberIntVal(0)},
+		{`42`, cty.NumberIntVal(42)},
+		{`007`, cty.NumberIntVal(7)},
+		{`1.5`, cty.NumberFloatVal(1.5)},
+		{`1e3`, cty.NumberIntVal(1000)},
+		{`1.5E-1`, cty.MustParseNumberVal("0.15")},
+		{`1_000_000`, cty.NumberIntVal(1000000)},
+		{`1_000.5`, cty.NumberFloatVal(1000.5)},
+		{`1.000_5`, cty.MustParseNumberVal("1.0005")},
+		{`1_0e1_0`, cty.NumberIntVal(100000000000)},
+		{`1_0e+2`, cty.NumberIntVal(1000)},
+		{`1_0e-1`, cty.NumberIntVal(1)},
+		{`0xff`, cty.NumberIntVal(255)},
+		{`0XFF`, cty.NumberIntVal(255)},
+		{`0xdead_beef`, cty.NumberIntVal(0xdeadbeef)},
+		{`0x1e5`, cty.NumberIntVal(0x1e5)},
+		{`0o755`, cty.NumberIntVal(0755)},
+		{`0O10`, cty.NumberIntVal(8)},
+		{`0b1010`, cty.NumberIntVal(10)},
+		{`0b1111_0000`, cty.NumberIntVal(0xf0)},
+		{`0xffffffffffffffffffff`, cty.MustParseNumberVal("1208925819614629174706175")},
+		{`0xff-1`, cty.NumberIntVal(254)},
+		{`[for x in [1]: 1if true]`, cty.TupleVal([]cty.Value{cty.NumberIntVal(1)})},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			expr, diags := ParseExpression([]byte(test.input), "", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+			got, diags := expr.Value(nil)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+			if !got.RawEquals(test.want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+			if rng := expr.Range(); rng.End.Byte != len(test.input) {
+				t.Errorf("wrong range %s; want the whole input", rng)
+			}
+		})
+	}
+}
+
+func TestNumberLitInvalid(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantDetail string
+		wantStart  int // byte offset of the reported problem
+		wantEnd    int
+	}{
+		{
+			`0o78`,
+			`The digit '8' is not valid in a number in octal notation.`,
+			3, 4,
+		},
+		{
+			`0b102`,
+			`The digit '2' is not valid in a number in binary notation.`,
+			4, 5,
+		},
+		{
+			`0b2`,
+			`The digit '2' is not valid in a number in binary notation.`,
+			2, 3,
+		},
+		{
+			`0x1.5`,
+			`A number in hexadecimal notation must be a whole number.`,
+			3, 4,
+		},
+		{
+			`0x`,
+			`Expected a digit here.`,
+			0, 2,
+		},
+		{
+			`1__000`,
+			`An underscore in a number literal must be between two digits.`,
+			1, 2,
+		},
+		{
+			`1000_`,
+			`An underscore in a number literal must be between two digits.`,
+			4, 5,
+		},
+		{
+			`0x_ff`,
+			`An underscore in a number literal must be between two digits.`,
+			2, 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, diags := ParseExpression([]byte(test.input), "", hcl.Pos{Line: 1, Column: 1})
+			if len(diags) != 1 {
+				t.Fatalf("wrong number of diagnostics %d; want 1\n%s", len(diags), diags.Error())
+			}
+			diag := diags[0]
+			if got, want := diag.Summary, "Invalid number literal"; got != want {
+				t.Errorf("wrong summary %q; want %q", got, want)
+			}
+			if got, want := diag.Detail, test.wantDetail; got != want {
+				t.Errorf("wrong detail\ngot:  %s\nwant: %s", got, want)
+			}
+			if got, want := diag.Subject.Start.Byte, test.wantStart; got != want {
+				t.Errorf("wrong subject start byte %d; want %d", got, want)
+			}
+			if got, want := diag.Subject.End.Byte, test.wantEnd; got != want {
+				t.Errorf("wrong subject end byte %d; want %d", got, want)
+			}
+		})
+	}
+}
@@ -19,11 +19,24 @@ type parser struct {
 	// in recovery mode, assuming that the recovery heuristics have failed
 	// in this case and left the peeker in a wrong place.
 	recovery bool
+
+	// set to true to enable error-tolerant parsing, as used by
+	// ParseConfigTolerant. In this mode the parser tries harder to resume
+	// at the next body item after an error, and represents the regions it
+	// skips over as BadExpr and BadAttribute nodes rather than discarding
+	// them.
+	tolerant bool
+
+	// set in error-tolerant mode when an item ended without the required
+	// newline, so that the remainder of the line is parsed as further body
+	// items without reporting additional errors for it.
+	midLine bool
 }
 
 func (p *parser) ParseBody(end TokenType) (*Body, hcl.Diagnostics) {
 	attrs := Attributes{}
 	blocks := Blocks{}
+	var bads []*BadAttribute
 	var diags hcl.Diagnostics
 
 	startRange := p.PrevRange()
@@ -35,9 +48,22 @@ Token:
 		if next.Type == end {
 			endRange = p.NextRange()
 			p.Read()
+			p.midLine = false
 			break Token
 		}
 
+		if p.tolerant {
+			// In error-tolerant mode we've always re-synchronized at the
+			// start of a body item by the time we get here, so we can
+			// report errors in subsequent items as normal, unless we're
+			// still on the line where an error was already reported.
+			if p.midLine {
+				p.midLine = false
+			} else {
+				p.recovery = false
+			}
+		}
+
 		switch next.Type {
 		case TokenNewline:
 			p.Read()
@@ -48,6 +74,8 @@ Token:
 			switch titem := item.(type) {
 			case *Block:
 				blocks = append(blocks, titem)
+			case *BadAttribute:
+				bads = append(bads, titem)
 			case *Attribute:
 				if existing, exists := attrs[titem.Name]; exists {
 					diags = append(diags, &hcl.Diagnostic{
@@ -92,6 +120,17 @@ Token:
 					})
 				}
 			}
+			if p.tolerant && bad.Type != TokenEOF {
+				// Rather than abandoning the rest of the body, skip only
+				// the remainder of the current line and then try to
+				// continue with the next item.
+				last := p.recoverInBody(end)
+				bads = append(bads, &BadAttribute{
+					SrcRange: hcl.RangeBetween(bad.Range, last),
+				})
+				continue
+			}
+
 			endRange = p.PrevRange() // arbitrary, but somewhere inside the body means better diagnostics
 
 			p.recover(end) // attempt to recover to the token after the end of this body
@@ -100,8 +139,9 @@ Token:
 	}
 
 	return &Body{
-		Attributes: attrs,
-		Blocks:     blocks,
+		Attributes:    attrs,
+		Blocks:        blocks,
+		BadAttributes: bads,
 
 		SrcRange: hcl.RangeBetween(startRange, endRange),
 		EndRange: hcl.Range{
@@ -134,18 +174,24 @@ func (p *parser) ParseBodyItem() (Node, hcl.Diagnostics) {
 	case TokenOQuote, TokenOBrace, TokenIdent:
 		return p.finishParsingBodyBlock(ident)
 	default:
-		p.recoverAfterBodyItem()
-		return nil, hcl.Diagnostics{
-			{
+		var diags hcl.Diagnostics
+		if !p.tolerant || !p.recovery {
+			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Argument or block definition required",
 				Detail:   "An argument or block definition is required here. To set an argument, use the equals sign \"=\" to introduce the argument value.",
 				Subject:  &ident.Range,
-			},
+			})
 		}
+		if p.tolerant {
+			last := p.recoverInBody(TokenCBrace)
+			return &BadAttribute{
+				SrcRange: hcl.RangeBetween(ident.Range, last),
+			}, diags
+		}
+		p.recoverAfterBodyItem()
+		return nil, diags
 	}
-
-	return nil, nil
 }
 
 // parseSingleAttrBody is a weird variant of ParseBody that deals with the
@@ -222,13 +268,21 @@ func (p *parser) finishParsingBodyAttribute(ident Token, singleLine bool) (Node,
 
 	var endRange hcl.Range
 
+	exprStart := p.NextIndex
 	expr, diags := p.ParseExpression()
 	if p.recovery && diags.HasErrors() {
 		// recovery within expressions tends to be tricky, so we've probably
 		// landed somewhere weird. We'll try to reset to the start of a body
 		// item so parsing can continue.
 		endRange = p.PrevRange()
-		p.recoverAfterBodyItem()
+		if p.tolerant {
+			expr = p.recoverAfterBadAttrExpr(exprStart, expr)
+			if bad, isBad := expr.(*BadExpr); isBad {
+				endRange = bad.SrcRange
+			}
+		} else {
+			p.recoverAfterBodyItem()
+		}
 	} else {
 		endRange = p.PrevRange()
 		if !singleLine {
@@ -252,7 +306,20 @@ func (p *parser) finishParsingBodyAttribute(ident Token, singleLine bool) (Node,
 					})
 				}
 				endRange = p.PrevRange()
-				p.recoverAfterBodyItem()
+				if p.tolerant {
+					// Rather than skipping the remainder of the line, we'll
+					// leave it to be parsed as further body items so that
+					// any well-formed items there are not lost. A stray
+					// comma is skipped, since it was presumably intended
+					// as a separator.
+					p.recovery = true
+					p.midLine = true
+					if end.Type == TokenComma {
+						p.Read()
+					}
+				} else {
+					p.recoverAfterBodyItem()
+				}
 			} else {
 				endRange = p.PrevRange()
 				p.Read() // eat newline
@@ -781,6 +848,34 @@ Traversal:
 				p.setRecovery()
 			}
 
+		case TokenQuestionDot:
+			// Safe-navigation attribute access, which produces null if
+			// the attribute is absent.
+			marker := p.Read()
+			attrTok := p.Peek()
+			if attrTok.Type != TokenIdent {
+				if !p.recovery {
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid attribute name",
+						Detail:   "An attribute name is required after \"?.\".",
+						Subject:  &attrTok.Range,
+					})
+				}
+				p.setRecovery()
+				break Traversal
+			}
+
+			attrTok = p.Read() // eat token
+			rng := hcl.RangeBetween(marker.Range, attrTok.Range)
+			step := hcl.TraverseAttr{
+				Name:     string(attrTok.Bytes),
+				SrcRange: rng,
+				Optional: true,
+			}
+
+			ret = makeRelativeTraversal(ret, step, rng)
+
 		case TokenOBrack:
 			// Indexing of a collection.
 			// This may or may not be a hcl.Traverser, depending on whether
@@ -950,6 +1045,10 @@ func (p *parser) parseExpressionTerm() (Expression, hcl.Diagnostics) {
 		}, diags
 
 	case TokenIdent:
+		if p.peekLetExpr() {
+			return p.parseLetExpr()
+		}
+
 		tok := p.Read() // eat identifier token
 
 		if p.Peek().Type == TokenOParen {
@@ -1058,28 +1157,32 @@ func (p *parser) parseExpressionTerm() (Expression, hcl.Diagnostics) {
 
 		// Return a placeholder so that the AST is still structurally sound
 		// even in the presence of parse errors.
-		return &LiteralValueExpr{
-			Val:      cty.DynamicVal,
-			SrcRange: start.Range,
-		}, diags
+		return p.placeholderExpr(start.Range), diags
 	}
 }
 
 func (p *parser) numberLitValue(tok Token) (cty.Value, hcl.Diagnostics) {
-	// The cty.ParseNumberVal is always the same behavior as converting a
-	// string to a number, ensuring we always interpret decimal numbers in
-	// the same way.
-	numVal, err := cty.ParseNumberVal(string(tok.Bytes))
+	numVal, err := parseNumberLit(tok.Bytes)
 	if err != nil {
-		ret := cty.UnknownVal(cty.Number)
-		return ret, hcl.Diagnostics{
+		// A number literal never spans multiple lines and everything before
+		// the problem is ASCII, so we can find the problematic character
+		// just by counting bytes.
+		subject := tok.Range
+		if err.Offset < len(tok.Bytes) {
+			_, size := utf8.DecodeRune(tok.Bytes[err.Offset:])
+			subject.Start.Byte += err.Offset
+			subject.Start.Column += err.Offset
+			subject.End = subject.Start
+			subject.End.Byte += size
+			subject.End.Column++
+		}
+		return numVal, hcl.Diagnostics{
 			{
 				Severity: hcl.DiagError,
 				Summary:  "Invalid number literal",
-				// FIXME: not a very good error message, but convert only
-				// gives us "a number is required", so not much help either.
-				Detail:  "Failed to recognize the value of this number literal.",
-				Subject: &tok.Range,
+				Detail:   err.Detail,
+				Subject:  &subject,
+				Context:  tok.Range.Ptr(),
 			},
 		}
 	}
@@ -1401,6 +1504,141 @@ func (p *parser) parseObjectCons() (Expression, hcl.Diagnostics) {
 	}, diags
 }
 
+// peekLetExpr returns true if the next tokens are the "let" keyword followed
+// by a variable name and an equals sign, which introduce a let expression.
+//
+// Since "let" is not a reserved word, it may also be used as a variable name
+// in any position where it isn't followed by a binding.
+func (p *parser) peekLetExpr() bool {
+	if !letKeyword.TokenMatches(p.Peek()) {
+		return false
+	}
+
+	saved := p.NextIndex
+	defer func() {
+		p.NextIndex = saved
+	}()
+
+	p.Read() // skip "let" keyword
+	if p.Read().Type != TokenIdent {
+		return false
+	}
+	return p.Read().Type == TokenEqual
+}
+
+func (p *parser) parseLetExpr() (Expression, hcl.Diagnostics) {
+	introducer := p.Read()
+	if !letKeyword.TokenMatches(introducer) {
+		// Should never happen if callers are behaving
+		panic("parseLetExpr called without peeker pointing to 'let' identifier")
+	}
+
+	type letBinding struct {
+		Name      string
+		NameRange hcl.Range
+		Expr      Expression
+	}
+	var bindings []letBinding
+	var diags hcl.Diagnostics
+
+	// Newlines are ignored within the bindings, but the body follows the
+	// newline rules of the context the expression appears in.
+	p.PushIncludeNewlines(false)
+	for {
+		name := p.Peek()
+		if name.Type != TokenIdent {
+			if !p.recovery {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid 'let' expression",
+					Detail:   "Let expression requires a variable name after 'let' and after each comma.",
+					Subject:  name.Range.Ptr(),
+					Context:  hcl.RangeBetween(introducer.Range, name.Range).Ptr(),
+				})
+			}
+			p.setRecovery()
+			p.PopIncludeNewlines()
+			return p.placeholderExpr(hcl.RangeBetween(introducer.Range, name.Range)), diags
+		}
+		p.Read() // eat name
+
+		if equals := p.Peek(); equals.Type != TokenEqual {
+			if !p.recovery {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid 'let' expression",
+					Detail:   "Let expression requires an equals sign after each variable name.",
+					Subject:  equals.Range.Ptr(),
+					Context:  hcl.RangeBetween(introducer.Range, equals.Range).Ptr(),
+				})
+			}
+			p.setRecovery()
+			p.PopIncludeNewlines()
+			return p.placeholderExpr(hcl.RangeBetween(introducer.Range, equals.Range)), diags
+		}
+		p.Read() // eat equals sign
+
+		expr, exprDiags := p.ParseExpression()
+		diags = append(diags, exprDiags...)
+		if p.recovery && exprDiags.HasErrors() {
+			p.PopIncludeNewlines()
+			return p.placeholderExpr(hcl.RangeBetween(introducer.Range, expr.Range())), diags
+		}
+
+		bindings = append(bindings, letBinding{
+			Name:      string(name.Bytes),
+			NameRange: name.Range,
+			Expr:      expr,
+		})
+
+		if p.Peek().Type != TokenComma {
+			break
+		}
+		p.Read() // eat comma
+	}
+
+	if !inKeyword.TokenMatches(p.Peek()) {
+		if !p.recovery {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid 'let' expression",
+				Detail:   "Let expression requires the 'in' keyword after its bindings.",
+				Subject:  p.Peek().Range.Ptr(),
+				Context:  hcl.RangeBetween(introducer.Range, p.Peek().Range).Ptr(),
+			})
+		}
+		p.setRecovery()
+		p.PopIncludeNewlines()
+		return p.placeholderExpr(hcl.RangeBetween(introducer.Range, p.PrevRange())), diags
+	}
+	p.Read() // eat 'in' keyword
+	p.PopIncludeNewlines()
+
+	body, bodyDiags := p.ParseExpression()
+	diags = append(diags, bodyDiags...)
+
+	// Each binding is in scope for all of the bindings that follow it, so
+	// we represent them as nested expressions from the inside out.
+	ret := body
+	for i := len(bindings) - 1; i >= 0; i-- {
+		binding := bindings[i]
+		startRange := binding.NameRange
+		if i == 0 {
+			startRange = introducer.Range
+		}
+		ret = &LetExpr{
+			Name:      binding.Name,
+			ValueExpr: binding.Expr,
+			BodyExpr:  ret,
+
+			SrcRange:  hcl.RangeBetween(startRange, body.Range()),
+			NameRange: binding.NameRange,
+		}
+	}
+
+	return ret, diags
+}
+
 func (p *parser) finishParsingForExpr(open Token) (Expression, hcl.Diagnostics) {
 	p.PushIncludeNewlines(false)
 	defer p.PopIncludeNewlines()
@@ -1438,10 +1676,7 @@ func (p *parser) finishParsingForExpr(open Token) (Expression, hcl.Diagnostics)
 			})
 		}
 		close := p.recover(closeType)
-		return &LiteralValueExpr{
-			Val:      cty.DynamicVal,
-			SrcRange: hcl.RangeBetween(open.Range, close.Range),
-		}, diags
+		return p.placeholderExpr(hcl.RangeBetween(open.Range, close.Range)), diags
 	}
 
 	valName = string(p.Read().Bytes)
@@ -1462,10 +1697,7 @@ func (p *parser) finishParsingForExpr(open Token) (Expression, hcl.Diagnostics)
 				})
 			}
 			close := p.recover(closeType)
-			return &LiteralValueExpr{
-				Val:      cty.DynamicVal,
-				SrcRange: hcl.RangeBetween(open.Range, close.Range),
-			}, diags
+			return p.placeholderExpr(hcl.RangeBetween(open.Range, close.Range)), diags
 		}
 
 		valName = string(p.Read().Bytes)
@@ -1482,10 +1714,7 @@ func (p *parser) finishParsingForExpr(open Token) (Expression, hcl.Diagnostics)
 			})
 		}
 		close := p.recover(closeType)
-		return &LiteralValueExpr{
-			Val:      cty.DynamicVal,
-			SrcRange: hcl.RangeBetween(open.Range, close.Range),
-		}, diags
+		return p.placeholderExpr(hcl.RangeBetween(open.Range, close.Range)), diags
 	}
 	p.Read() // eat 'in' keyword
 
@@ -1493,10 +1722,7 @@ func (p *parser) finishParsingForExpr(open Token) (Expression, hcl.Diagnostics)
 	diags = append(diags, collDiags...)
 	if p.recovery && collDiags.HasErrors() {
 		close := p.recover(closeType)
-		return &LiteralValueExpr{
-			Val:      cty.DynamicVal,
-			SrcRange: hcl.RangeBetween(open.Range, close.Range),
-		}, diags
+		return p.placeholderExpr(hcl.RangeBetween(open.Range, close.Range)), diags
 	}
 
 	if p.Peek().Type != TokenColon {
@@ -1510,10 +1736,7 @@ func (p *parser) finishParsingForExpr(open Token) (Expression, hcl.Diagnostics)
 			})
 		}
 		close := p.recover(closeType)
-		return &LiteralValueExpr{
-			Val:      cty.DynamicVal,
-			SrcRange: hcl.RangeBetween(open.Range, close.Range),
-		}, diags
+		return p.placeholderExpr(hcl.RangeBetween(open.Range, close.Range)), diags
 	}
 	p.Read() // eat colon
 
@@ -1531,10 +1754,7 @@ func (p *parser) finishParsingForExpr(open Token) (Expression, hcl.Diagnostics)
 	diags = append(diags, valDiags...)
 	if p.recovery && (keyDiags.HasErrors() || valDiags.HasErrors()) {
 		close := p.recover(closeType)
-		return &LiteralValueExpr{
-			Val:      cty.DynamicVal,
-			SrcRange: hcl.RangeBetween(open.Range, close.Range),
-		}, diags
+		return p.placeholderExpr(hcl.RangeBetween(open.Range, close.Range)), diags
 	}
 
 	group := false
@@ -1552,10 +1772,7 @@ func (p *parser) finishParsingForExpr(open Token) (Expression, hcl.Diagnostics)
 		diags = append(diags, condDiags...)
 		if p.recovery && condDiags.HasErrors() {
 			close := p.recover(p.oppositeBracket(open.Type))
-			return &LiteralValueExpr{
-				Val:      cty.DynamicVal,
-				SrcRange: hcl.RangeBetween(open.Range, close.Range),
-			}, diags
+			return p.placeholderExpr(hcl.RangeBetween(open.Range, close.Range)), diags
 		}
 	}
 
@@ -2036,6 +2253,140 @@ func (p *parser) oppositeBracket(ty TokenType) TokenType {
 	}
 }
 
+// recoverInBody is a variant of recoverAfterBodyItem used in error-tolerant
+// mode. It skips forward to the start of the next line at the current
+// nesting level, but stops early (without consuming it) if it encounters an
+// unbalanced "end" token that would close the body being parsed, so that
+// the caller can still find the end of its body.
+//
+// The result is the range of the last token that was skipped, excluding
+// the terminating newline, or the range of the previous token if nothing
+// was skipped.
+func (p *parser) recoverInBody(end TokenType) hcl.Range {
+	p.recovery = true
+	var open []TokenType
+	last := p.PrevRange()
+
+Token:
+	for {
+		tok := p.Peek()
+
+		switch tok.Type {
+
+		case TokenNewline:
+			if len(open) == 0 {
+				p.Read()
+				break Token
+			}
+
+		case TokenEOF:
+			break Token
+
+		case TokenOBrace, TokenOBrack, TokenOParen, TokenOQuote, TokenOHeredoc, TokenTemplateInterp, TokenTemplateControl:
+			open = append(open, tok.Type)
+
+		case TokenCBrace, TokenCBrack, TokenCParen, TokenCQuote, TokenCHeredoc:
+			if len(open) == 0 && tok.Type == end {
+				break Token
+			}
+			opener := p.oppositeBracket(tok.Type)
+			for len(open) > 0 && open[len(open)-1] != opener {
+				open = open[:len(open)-1]
+			}
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+
+		case TokenTemplateSeqEnd:
+			for len(open) > 0 && open[len(open)-1] != TokenTemplateInterp && open[len(open)-1] != TokenTemplateControl {
+				open = open[:len(open)-1]
+			}
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+
+		}
+
+		last = p.Read().Range
+	}
+
+	return last
+}
+
+// recoverAfterBadAttrExpr is used in error-tolerant mode after the
+// expression of an attribute failed to parse, given the index of the first
+// token of that expression and the partial expression that was returned.
+//
+// Unbalanced brackets in an incomplete expression can cause the expression
+// parser to consume the remainder of the file, so if the recovery overshoots
+// the end of the enclosing body then we instead rewind to the end of the
+// line where the expression began and replace the expression with a BadExpr
+// covering what remains of that line. Otherwise the partial expression is
+// returned as-is, since it may still be useful for analysis.
+func (p *parser) recoverAfterBadAttrExpr(exprStart int, expr Expression) Expression {
+	p.recoverInBody(TokenCBrace)
+
+	atEOF := p.Peek().Type == TokenEOF
+	firstLine := p.Tokens[exprStart].Range.Start.Line
+	overshot := false
+	nest := 0
+	for _, tok := range p.Tokens[exprStart:p.NextIndex] {
+		switch tok.Type {
+		case TokenOBrace:
+			nest++
+		case TokenCBrace:
+			nest--
+		}
+		// Running into the end of the file is only suspicious if we had
+		// to consume multiple lines to get there.
+		if nest < 0 || (atEOF && tok.Range.Start.Line != firstLine) {
+			overshot = true
+			break
+		}
+	}
+	if !overshot {
+		return expr
+	}
+
+	// Rewind to the end of the first line of the expression, treating
+	// everything after it as potential body items.
+	lineEnd := exprStart
+	for lineEnd < len(p.Tokens)-1 {
+		ty := p.Tokens[lineEnd].Type
+		if ty == TokenNewline || ty == TokenEOF {
+			break
+		}
+		if tokenEndsWithNewline(p.Tokens[lineEnd]) {
+			break
+		}
+		lineEnd++
+	}
+	if lineEnd == exprStart {
+		// There are no tokens on the line at all, so we'll just mark the
+		// empty position where the expression was expected.
+		rng := p.Tokens[exprStart].Range
+		rng.End = rng.Start
+		p.NextIndex = exprStart
+		p.Read() // eat the newline
+		return &BadExpr{SrcRange: rng}
+	}
+
+	rng := hcl.RangeBetween(p.Tokens[exprStart].Range, p.Tokens[lineEnd-1].Range)
+	p.NextIndex = lineEnd
+	p.Read() // eat the newline
+	return &BadExpr{SrcRange: rng}
+}
+
+// placeholderExpr returns an expression to stand in for a construct that
+// failed to parse, so that the AST is still structurally sound even in the
+// presence of parse errors.
+func (p *parser) placeholderExpr(rng hcl.Range) Expression {
+	if p.tolerant {
+		return &BadExpr{SrcRange: rng}
+	}
+	return errPlaceholderExpr(rng)
+}
+
 func errPlaceholderExpr(rng hcl.Range) Expression {
 	return &LiteralValueExpr{
 		Val:      cty.DynamicVal,
@@ -388,6 +388,13 @@ Token:
 
 			if ltrim {
 				str = strings.TrimLeftFunc(str, unicode.IsSpace)
+
+				// A literal in a heredoc is split into one token per line,
+				// so if this token was entirely whitespace then we must
+				// continue trimming into the next one.
+				if str == "" {
+					ltrimNext = true
+				}
 			}
 
 			parts = append(parts, &templateLiteralToken{
@@ -398,13 +405,10 @@ Token:
 
 		case TokenTemplateInterp:
 			// if the opener is ${~ then we want to eat any trailing whitespace
-			// in the preceding literal token, assuming it is indeed a literal
-			// token.
-			if canTrimPrev && len(next.Bytes) == 3 && next.Bytes[2] == '~' && len(parts) > 0 {
-				prevExpr := parts[len(parts)-1]
-				if lexpr, ok := prevExpr.(*templateLiteralToken); ok {
-					lexpr.Val = strings.TrimRightFunc(lexpr.Val, unicode.IsSpace)
-				}
+			// in the preceding literal tokens, assuming they are indeed literal
+			// tokens.
+			if canTrimPrev && len(next.Bytes) == 3 && next.Bytes[2] == '~' {
+				trimTemplateLiteralsRight(parts)
 			}
 
 			p.PushIncludeNewlines(false)
@@ -440,13 +444,10 @@ Token:
 
 		case TokenTemplateControl:
 			// if the opener is %{~ then we want to eat any trailing whitespace
-			// in the preceding literal token, assuming it is indeed a literal
-			// token.
-			if canTrimPrev && len(next.Bytes) == 3 && next.Bytes[2] == '~' && len(parts) > 0 {
-				prevExpr := parts[len(parts)-1]
-				if lexpr, ok := prevExpr.(*templateLiteralToken); ok {
-					lexpr.Val = strings.TrimRightFunc(lexpr.Val, unicode.IsSpace)
-				}
+			// in the preceding literal tokens, assuming they are indeed literal
+			// tokens.
+			if canTrimPrev && len(next.Bytes) == 3 && next.Bytes[2] == '~' {
+				trimTemplateLiteralsRight(parts)
 			}
 			p.PushIncludeNewlines(false)
 
@@ -653,6 +654,23 @@ Token:
 	return ret, diags
 }
 
+// trimTemplateLiteralsRight removes trailing whitespace from the literal
+// token at the end of the given parts. Since a literal in a heredoc is split
+// into one token per line, trimming continues into the preceding literal
+// tokens for as long as the trimmed tokens are left empty.
+func trimTemplateLiteralsRight(parts []templateToken) {
+	for i := len(parts) - 1; i >= 0; i-- {
+		lit, ok := parts[i].(*templateLiteralToken)
+		if !ok {
+			return
+		}
+		lit.Val = strings.TrimRightFunc(lit.Val, unicode.IsSpace)
+		if lit.Val != "" {
+			return
+		}
+	}
+}
+
 // flushHeredocTemplateParts modifies in-place the line-leading literal strings
 // to apply the flush heredoc processing rule: find the line with the smallest
 // number of whitespace characters as prefix and then trim that number of
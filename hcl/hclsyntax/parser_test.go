@@ -1900,6 +1900,15 @@ block "valid" {}
 							Start: hcl.Pos{Line: 1, Column: 3, Byte: 2},
 							End:   hcl.Pos{Line: 1, Column: 4, Byte: 3},
 						},
+						LineComments: []*Comment{
+							{
+								Bytes: []byte("# line comment"),
+								SrcRange: hcl.Range{
+									Start: hcl.Pos{Line: 1, Column: 7, Byte: 6},
+									End:   hcl.Pos{Line: 1, Column: 21, Byte: 20},
+								},
+							},
+						},
 					},
 				},
 				Blocks: Blocks{},
@@ -0,0 +1,178 @@
+package hclsyntax
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+func TestExpressionProvenance(t *testing.T) {
+	tests := []struct {
+		input          string
+		wantRanges     []string // source text of each range
+		wantTraversals []string // source text of each traversal
+	}{
+		{
+			`1`,
+			[]string{`1`},
+			nil,
+		},
+		{
+			`n + 1`,
+			[]string{`n`, `1`},
+			[]string{`n`},
+		},
+		{
+			`n + n`,
+			[]string{`n`, `n`},
+			[]string{`n`, `n`},
+		},
+		{
+			`"${a}-${obj.name}"`,
+			[]string{`a`, `-`, `obj.name`},
+			[]string{`a`, `obj.name`},
+		},
+		{
+			`upper(a)`,
+			[]string{`a`},
+			[]string{`a`},
+		},
+		{
+			`true ? a : "b"`,
+			[]string{`true`, `a`},
+			[]string{`a`},
+		},
+		{
+			`false && a`,
+			[]string{`false`},
+			nil,
+		},
+		{
+			`a ?? obj`,
+			[]string{`a`},
+			[]string{`a`},
+		},
+		{
+			`{k = a}`,
+			[]string{`k`, `a`},
+			[]string{`a`},
+		},
+		{
+			`list[0]`,
+			[]string{`list[0]`},
+			[]string{`list[0]`},
+		},
+		{
+			`[for x in list: x.name]`,
+			[]string{`list`},
+			[]string{`list`},
+		},
+		{
+			`{for i, x in list: i => x.name if x.name != a}`,
+			[]string{`list`, `a`},
+			[]string{`list`, `a`},
+		},
+		{
+			`list[*].name`,
+			[]string{`list`},
+			[]string{`list`},
+		},
+		{
+			`let y = obj.name in [y, a]`,
+			[]string{`obj.name`, `a`},
+			[]string{`obj.name`, `a`},
+		},
+	}
+
+	obj := cty.ObjectVal(map[string]cty.Value{
+		"name": cty.StringVal("hello"),
+	})
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			tracker := hcl.NewProvenanceTracker()
+			ctx := &hcl.EvalContext{
+				Variables: map[string]cty.Value{
+					"a":    cty.StringVal("a"),
+					"n":    cty.NumberIntVal(1),
+					"obj":  obj,
+					"list": cty.ListVal([]cty.Value{obj, obj}),
+				},
+				Functions: map[string]function.Function{
+					"upper": stdlib.UpperFunc,
+				},
+				Provenance: tracker,
+			}
+
+			expr, diags := ParseExpression([]byte(test.input), "", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected parse errors: %s", diags.Error())
+			}
+			_, diags = expr.Value(ctx)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+
+			prov := tracker.Provenance(expr)
+			var gotRanges, gotTraversals []string
+			for _, rng := range prov.Ranges {
+				gotRanges = append(gotRanges, string(rng.SliceBytes([]byte(test.input))))
+			}
+			for _, traversal := range prov.Traversals {
+				gotTraversals = append(gotTraversals, string(traversal.SourceRange().SliceBytes([]byte(test.input))))
+			}
+			if !reflect.DeepEqual(gotRanges, test.wantRanges) {
+				t.Errorf("wrong ranges\ngot:  %q\nwant: %q", gotRanges, test.wantRanges)
+			}
+			if !reflect.DeepEqual(gotTraversals, test.wantTraversals) {
+				t.Errorf("wrong traversals\ngot:  %q\nwant: %q", gotTraversals, test.wantTraversals)
+			}
+		})
+	}
+}
+
+func TestExpressionProvenanceVariable(t *testing.T) {
+	tracker := hcl.NewProvenanceTracker()
+	ctx := &hcl.EvalContext{
+		Variables:  map[string]cty.Value{},
+		Provenance: tracker,
+	}
+
+	// The value of "answer" comes from one file...
+	first, diags := ParseExpression([]byte(`42`), "first.hcl", hcl.Pos{Line: 3, Column: 11, Byte: 30})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", diags.Error())
+	}
+	val, diags := first.Value(ctx)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	ctx.Variables["answer"] = val
+	tracker.SetVariableProvenance(ctx, "answer", tracker.Provenance(first))
+
+	// ...and is then used in another.
+	second, diags := ParseExpression([]byte(`answer`), "second.hcl", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", diags.Error())
+	}
+	if _, diags := second.Value(ctx); diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	got := tracker.Provenance(second)
+	want := hcl.Provenance{
+		Ranges: []hcl.Range{
+			first.Range(),
+			second.Range(),
+		},
+		Traversals: []hcl.Traversal{
+			second.(*ScopeTraversalExpr).Traversal,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong provenance\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
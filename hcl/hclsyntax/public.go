@@ -20,6 +20,7 @@ func ParseConfig(src []byte, filename string, start hcl.Pos) (*hcl.File, hcl.Dia
 	parser := &parser{peeker: peeker}
 	body, parseDiags := parser.ParseBody(TokenEOF)
 	diags = append(diags, parseDiags...)
+	attachComments(body, tokens)
 
 	// Panic if the parser uses incorrect stack discipline with the peeker's
 	// newlines stack, since otherwise it will produce confusing downstream
@@ -36,6 +37,81 @@ func ParseConfig(src []byte, filename string, start hcl.Pos) (*hcl.File, hcl.Dia
 	}, diags
 }
 
+// ParseConfigTolerant is a variant of ParseConfig intended for use in
+// interactive tools such as text editors, where the source is often
+// temporarily invalid while the user is typing.
+//
+// The normal parser abandons the remainder of a body after certain syntax
+// errors. In error-tolerant mode the parser instead skips only the affected
+// line and resumes at the next attribute or block, so that all of the
+// well-formed items around an error are still present in the result. The
+// regions that were skipped are represented in the AST as BadAttribute items
+// in Body.BadAttributes and as BadExpr expressions, each with a source range.
+//
+// The returned diagnostics are not suppressed, so the result must not be used
+// for any real evaluation if they contain errors.
+func ParseConfigTolerant(src []byte, filename string, start hcl.Pos) (*hcl.File, hcl.Diagnostics) {
+	tokens, diags := lexConfigTolerant(src, filename, start)
+	peeker := newPeeker(tokens, false)
+	parser := &parser{peeker: peeker, tolerant: true}
+	body, parseDiags := parser.ParseBody(TokenEOF)
+	diags = append(diags, parseDiags...)
+	attachComments(body, tokens)
+
+	// Panic if the parser uses incorrect stack discipline with the peeker's
+	// newlines stack, since otherwise it will produce confusing downstream
+	// errors.
+	peeker.AssertEmptyIncludeNewlinesStack()
+
+	return &hcl.File{
+		Body:  body,
+		Bytes: src,
+
+		Nav: navigation{
+			root: body,
+		},
+	}, diags
+}
+
+// lexConfigTolerant is a variant of LexConfig for ParseConfigTolerant.
+//
+// An unterminated quoted string would otherwise cause the scanner to treat
+// all of the lines that follow it as part of the string, so that the parser
+// would never see them. Since a quoted string may not contain a literal
+// newline, we instead end the line at the first such newline, reporting it
+// as usual, and scan the remainder of the source again from the start of the
+// next line.
+func lexConfigTolerant(src []byte, filename string, start hcl.Pos) (Tokens, hcl.Diagnostics) {
+	var tokens Tokens
+	var cuts []int
+	for {
+		more := scanTokens(src, filename, start, scanNormal)
+		cut := -1
+		for i, tok := range more {
+			if tok.Type == TokenQuotedNewline {
+				cut = i
+				break
+			}
+		}
+		if cut < 0 {
+			tokens = append(tokens, more...)
+			break
+		}
+
+		cuts = append(cuts, len(tokens)+cut)
+		tokens = append(tokens, more[:cut+1]...)
+		end := more[cut].Range.End
+		src = src[end.Byte-start.Byte:]
+		start = end
+	}
+
+	diags := checkInvalidTokens(tokens)
+	for _, i := range cuts {
+		tokens[i].Type = TokenNewline
+	}
+	return tokens, diags
+}
+
 // ParseExpression parses the given buffer as a standalone HCL expression,
 // returning it as an instance of Expression.
 func ParseExpression(src []byte, filename string, start hcl.Pos) (Expression, hcl.Diagnostics) {
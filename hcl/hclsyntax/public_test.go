@@ -1,7 +1,11 @@
 package hclsyntax
 
 import (
+	"reflect"
+	"sort"
 	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
 )
 
 func TestValidIdentifier(t *testing.T) {
@@ -44,3 +48,144 @@ func TestValidIdentifier(t *testing.T) {
 		})
 	}
 }
+
+func TestParseConfigTolerant(t *testing.T) {
+	type item struct {
+		Kind  string // "attr", "bad" or "block"
+		Name  string
+		Range string
+	}
+	tests := []struct {
+		Input     string
+		DiagCount int
+		Want      []item
+	}{
+		{
+			"a = 1\nb = 2\n",
+			0,
+			[]item{
+				{"attr", "a", "t:1,1-6"},
+				{"attr", "b", "t:2,1-6"},
+			},
+		},
+		{
+			"a = 1\n: bad\nb = 2\n",
+			1,
+			[]item{
+				{"attr", "a", "t:1,1-6"},
+				{"attr", "b", "t:3,1-6"},
+				{"bad", "", "t:2,1-6"},
+			},
+		},
+		{
+			"a = foo(\nb = 2\nblock {\n  c = 3\n}\n",
+			1,
+			[]item{
+				{"attr", "a", "t:1,1-9"},
+				{"attr", "a=bad", "t:1,5-9"},
+				{"attr", "b", "t:2,1-6"},
+				{"block", "block", "t:3,1-5,2"},
+				{"attr", "block.c", "t:4,3-8"},
+			},
+		},
+		{
+			"block {\n  foo = bar baz\n  b = 2\n}\nc = 3\n",
+			1,
+			[]item{
+				{"attr", "c", "t:5,1-6"},
+				{"block", "block", "t:1,1-4,2"},
+				{"attr", "block.b", "t:3,3-8"},
+				{"attr", "block.foo", "t:2,3-12"},
+				{"bad", "block.", "t:2,13-16"},
+			},
+		},
+		{
+			"block {\n  foo bar\n  \"baz\" = 1\n  b = 2\n}\n",
+			2,
+			[]item{
+				{"block", "block", "t:1,1-5,2"},
+				{"attr", "block.b", "t:4,3-8"},
+				{"bad", "block.", "t:3,3-12"},
+				{"block", "block.foo", "t:2,3-6"},
+			},
+		},
+		{
+			"a = [1, 2\nb = {\n}\n",
+			1,
+			[]item{
+				{"attr", "a", "t:1,1-10"},
+				{"attr", "a=bad", "t:1,5-10"},
+				{"attr", "b", "t:2,1-3,2"},
+			},
+		},
+		{
+			"a = \"${foo\"\nb = 2",
+			2,
+			[]item{
+				{"attr", "a", "t:1,1-12"},
+				{"attr", "a=bad", "t:1,5-12"},
+				{"attr", "b", "t:2,1-6"},
+			},
+		},
+		{
+			"a = 1 b = 2\nc = 3",
+			1,
+			[]item{
+				{"attr", "a", "t:1,1-6"},
+				{"attr", "b", "t:1,7-12"},
+				{"attr", "c", "t:2,1-6"},
+			},
+		},
+		{
+			"a = 1, b = 2 : x\nc = 3",
+			1,
+			[]item{
+				{"attr", "a", "t:1,1-6"},
+				{"attr", "b", "t:1,8-13"},
+				{"attr", "c", "t:2,1-6"},
+				{"bad", "", "t:1,14-17"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			f, diags := ParseConfigTolerant([]byte(test.Input), "t", hcl.Pos{Line: 1, Column: 1})
+			if len(diags) != test.DiagCount {
+				t.Errorf("wrong number of diagnostics %d; want %d", len(diags), test.DiagCount)
+				for _, diag := range diags {
+					t.Logf("- %s", diag)
+				}
+			}
+
+			var got []item
+			var collect func(prefix string, body *Body)
+			collect = func(prefix string, body *Body) {
+				names := make([]string, 0, len(body.Attributes))
+				for name := range body.Attributes {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					attr := body.Attributes[name]
+					got = append(got, item{"attr", prefix + name, attr.SrcRange.String()})
+					if bad, isBad := attr.Expr.(*BadExpr); isBad {
+						got = append(got, item{"attr", prefix + name + "=bad", bad.SrcRange.String()})
+					}
+				}
+				for _, bad := range body.BadAttributes {
+					got = append(got, item{"bad", prefix, bad.SrcRange.String()})
+				}
+				for _, block := range body.Blocks {
+					got = append(got, item{"block", prefix + block.Type, block.Range().String()})
+					collect(prefix+block.Type+".", block.Body)
+				}
+			}
+			collect("", f.Body.(*Body))
+
+			if !reflect.DeepEqual(got, test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package hclsyntax
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+// ReparseConfig produces an updated version of a file previously returned by
+// ParseConfig (or by an earlier call to ReparseConfig) after replacing the
+// bytes in the given edit range with the given replacement bytes.
+//
+// Only the Byte fields of the edit range are used. The previous file must
+// have been parsed with a start position of line 1, column 1, byte 0, and
+// its parse must have produced no error diagnostics, since otherwise the
+// previous AST may be incomplete in ways that would be carried over into the
+// result.
+//
+// Rather than lexing and parsing the whole new source buffer, ReparseConfig
+// re-scans and re-parses only the top-level attributes and blocks affected
+// by the edit, and shifts the source ranges of any unaffected items that
+// follow it. The result is always equivalent to what ParseConfig would
+// produce for the new source buffer: if the affected region cannot be parsed
+// in isolation without changing the meaning of the surrounding items, such as
+// when the edit opens a multi-line comment, or if the region contains syntax
+// errors, the whole buffer is parsed as normal instead.
+func ReparseConfig(prev *hcl.File, edit hcl.Range, replacement []byte) (*hcl.File, hcl.Diagnostics) {
+	oldSrc := prev.Bytes
+	editStart, editEnd := edit.Start.Byte, edit.End.Byte
+	if editStart < 0 || editEnd < editStart || editEnd > len(oldSrc) {
+		panic("ReparseConfig edit range is outside of the previous source buffer")
+	}
+
+	src := make([]byte, 0, len(oldSrc)-(editEnd-editStart)+len(replacement))
+	src = append(src, oldSrc[:editStart]...)
+	src = append(src, replacement...)
+	src = append(src, oldSrc[editEnd:]...)
+
+	oldBody, ok := prev.Body.(*Body)
+	if !ok {
+		panic("ReparseConfig requires a file produced by ParseConfig")
+	}
+	filename := oldBody.SrcRange.Filename
+	fileStart := hcl.Pos{Line: 1, Column: 1, Byte: 0}
+
+	fullParse := func() (*hcl.File, hcl.Diagnostics) {
+		return ParseConfig(src, filename, fileStart)
+	}
+
+	items := reparseItems(oldBody)
+	if len(items) == 0 {
+		return fullParse()
+	}
+
+	// The affected items are those whose ranges overlap or touch the edited
+	// range, since an edit immediately adjacent to an item can change how
+	// its first or last token is scanned. If the edit falls entirely
+	// between two items then the range of affected items is empty, with
+	// lo == hi+1.
+	lo, hi := len(items), -1
+	for i, item := range items {
+		rng := item.Range()
+		if rng.Start.Byte <= editEnd && rng.End.Byte >= editStart {
+			if i < lo {
+				lo = i
+			}
+			hi = i
+		}
+	}
+	if hi < 0 {
+		lo = sort.Search(len(items), func(i int) bool {
+			return items[i].Range().Start.Byte > editEnd
+		})
+		hi = lo - 1
+	}
+
+	delta := len(replacement) - (editEnd - editStart)
+
+	for {
+		// The region to re-parse runs from the end of the last unaffected
+		// item before the edit to the start of the first unaffected item
+		// after it, or to the start or end of the file if there is no
+		// such item.
+		var regionStart hcl.Pos
+		if lo == 0 {
+			regionStart = fileStart
+		} else {
+			regionStart = items[lo-1].Range().End
+		}
+		oldRegionEnd := len(oldSrc)
+		if hi < len(items)-1 {
+			oldRegionEnd = items[hi+1].Range().Start.Byte
+		}
+		newRegionEnd := oldRegionEnd + delta
+
+		// If the next item begins on the same line as the edit then the
+		// edit may change its column positions, so we'll include it in the
+		// region too.
+		if hi < len(items)-1 && !bytes.ContainsAny(oldSrc[editEnd:oldRegionEnd], "\n") {
+			hi++
+			continue
+		}
+
+		tokens := scanTokens(src[regionStart.Byte:newRegionEnd], filename, regionStart, scanNormal)
+		if !reparseRegionIsolated(tokens, lo > 0, hi < len(items)-1) {
+			if lo == 0 && hi == len(items)-1 {
+				return fullParse()
+			}
+			if lo > 0 {
+				lo--
+			}
+			if hi < len(items)-1 {
+				hi++
+			}
+			continue
+		}
+
+		diags := checkInvalidTokens(tokens)
+		peeker := newPeeker(tokens, false)
+		parser := &parser{peeker: peeker}
+		regionBody, parseDiags := parser.ParseBody(TokenEOF)
+		diags = append(diags, parseDiags...)
+		peeker.AssertEmptyIncludeNewlinesStack()
+		if diags.HasErrors() {
+			// Error recovery can behave differently when the parser is able
+			// to see the whole file, so we'll defer to a full parse to make
+			// sure the result (and its diagnostics) are consistent.
+			return fullParse()
+		}
+
+		body := &Body{
+			Attributes: Attributes{},
+			Blocks:     Blocks{},
+		}
+		shifter := newRangeShifter(delta, reparseLineDelta(oldSrc[editStart:editEnd], replacement))
+
+		// The region may contain comments that belong to the unaffected
+		// items either side of it, so we'll collect the region items and
+		// these neighbors into a separate body to attach comments to.
+		local := &Body{
+			Attributes: Attributes{},
+			Blocks:     Blocks{},
+		}
+		for name, attr := range regionBody.Attributes {
+			local.Attributes[name] = attr
+		}
+		local.Blocks = append(local.Blocks, regionBody.Blocks...)
+		var prevItem, nextItem Node
+		var prevLead, nextLine []*Comment
+
+		for i, item := range items {
+			if i == lo {
+				// Items from the re-parsed region are inserted in place
+				// of the affected items, preserving source order.
+				body.Blocks = append(body.Blocks, regionBody.Blocks...)
+				for name, attr := range regionBody.Attributes {
+					body.Attributes[name] = attr
+				}
+			}
+			if i >= lo && i <= hi {
+				continue
+			}
+			if i > hi {
+				item = shifter.Shift(item).(Node)
+			}
+			if i == lo-1 || i == hi+1 {
+				// We'll be changing the comments of these, so we must
+				// copy them to avoid modifying the previous file. The
+				// comments that follow the previous item and that precede
+				// the next item are within the region, so we'll find
+				// them again below.
+				switch titem := item.(type) {
+				case *Attribute:
+					copy := *titem
+					item = &copy
+					local.Attributes[copy.Name] = &copy
+					if i == lo-1 {
+						prevLead, copy.LineComments = copy.LeadComments, nil
+					} else {
+						nextLine, copy.LeadComments = copy.LineComments, nil
+					}
+				case *Block:
+					copy := *titem
+					item = &copy
+					local.Blocks = append(local.Blocks, &copy)
+					if i == lo-1 {
+						prevLead, copy.LineComments = copy.LeadComments, nil
+					} else {
+						nextLine, copy.LeadComments = copy.LineComments, nil
+					}
+				}
+				if i == lo-1 {
+					prevItem = item
+				} else {
+					nextItem = item
+				}
+			}
+			switch titem := item.(type) {
+			case *Attribute:
+				body.Attributes[titem.Name] = titem
+			case *Block:
+				body.Blocks = append(body.Blocks, titem)
+			}
+		}
+		if lo == len(items) {
+			body.Blocks = append(body.Blocks, regionBody.Blocks...)
+			for name, attr := range regionBody.Attributes {
+				body.Attributes[name] = attr
+			}
+		}
+
+		// If any attribute name was defined both inside and outside of the
+		// region then a full parse would report it as a redefinition.
+		if len(body.Attributes) != len(reparseAttrNames(items, lo, hi))+len(regionBody.Attributes) {
+			return fullParse()
+		}
+
+		attachBodyComments(local, sourceComments(tokens))
+		switch titem := prevItem.(type) {
+		case *Attribute:
+			titem.LeadComments = prevLead
+		case *Block:
+			titem.LeadComments = prevLead
+		}
+		switch titem := nextItem.(type) {
+		case *Attribute:
+			titem.LineComments = nextLine
+		case *Block:
+			titem.LineComments = nextLine
+		}
+		for _, c := range oldBody.InnerComments {
+			if c.SrcRange.Start.Byte < regionStart.Byte {
+				body.InnerComments = append(body.InnerComments, c)
+			}
+		}
+		body.InnerComments = append(body.InnerComments, local.InnerComments...)
+		for _, c := range oldBody.InnerComments {
+			if c.SrcRange.Start.Byte >= oldRegionEnd {
+				body.InnerComments = append(body.InnerComments, shifter.Shift(c).(*Comment))
+			}
+		}
+
+		if lo == 0 {
+			body.SrcRange.Start = regionBody.SrcRange.Start
+		} else {
+			body.SrcRange.Start = oldBody.SrcRange.Start
+		}
+		if hi == len(items)-1 {
+			body.SrcRange.End = regionBody.SrcRange.End
+			body.EndRange = regionBody.EndRange
+		} else {
+			body.SrcRange.End = shifter.ShiftPos(oldBody.SrcRange.End)
+			body.EndRange = shifter.Shift(oldBody.EndRange).(hcl.Range)
+		}
+		body.SrcRange.Filename = filename
+
+		return &hcl.File{
+			Body:  body,
+			Bytes: src,
+
+			Nav: navigation{
+				root: body,
+			},
+		}, diags
+	}
+}
+
+// reparseItems returns all of the top-level attributes and blocks in the
+// given body, in source order.
+func reparseItems(body *Body) []Node {
+	items := make([]Node, 0, len(body.Attributes)+len(body.Blocks))
+	for _, attr := range body.Attributes {
+		items = append(items, attr)
+	}
+	for _, block := range body.Blocks {
+		items = append(items, block)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Range().Start.Byte < items[j].Range().Start.Byte
+	})
+	return items
+}
+
+func reparseAttrNames(items []Node, lo, hi int) map[string]struct{} {
+	ret := make(map[string]struct{})
+	for i, item := range items {
+		if i >= lo && i <= hi {
+			continue
+		}
+		if attr, ok := item.(*Attribute); ok {
+			ret[attr.Name] = struct{}{}
+		}
+	}
+	return ret
+}
+
+// reparseRegionIsolated checks whether the given tokens, scanned from a
+// region of a file, can be parsed separately from the rest of the file
+// without changing the result.
+//
+// If the region follows an earlier item then it must begin with a newline,
+// so that the earlier item is still properly terminated. If the region is
+// followed by a later item then it must end with a newline outside of any
+// bracketed construct, so that the scanner would be in its initial state
+// when it reaches the later item.
+func reparseRegionIsolated(tokens Tokens, after, before bool) bool {
+	for _, tok := range tokens {
+		if tok.Type == TokenEOF {
+			// The region is empty, so it cannot be separating items.
+			return !(after && before)
+		}
+		if after && !reparseTokenEndsLine(tok) {
+			return false
+		}
+		break
+	}
+
+	if !before {
+		return true
+	}
+
+	nest := 0
+	var last Token
+	for _, tok := range tokens {
+		switch tok.Type {
+		case TokenOBrace, TokenOBrack, TokenOParen, TokenOQuote, TokenOHeredoc, TokenTemplateInterp, TokenTemplateControl:
+			nest++
+		case TokenCBrace, TokenCBrack, TokenCParen, TokenCQuote, TokenCHeredoc, TokenTemplateSeqEnd:
+			nest--
+		case TokenEOF:
+			continue
+		}
+		last = tok
+	}
+	return nest == 0 && reparseTokenEndsLine(last)
+}
+
+func reparseTokenEndsLine(tok Token) bool {
+	return tok.Type == TokenNewline || tokenEndsWithNewline(tok)
+}
+
+// reparseLineDelta returns the change in line count when replacing the
+// given old bytes with the given new bytes.
+func reparseLineDelta(old, new []byte) int {
+	return bytes.Count(new, []byte{'\n'}) - bytes.Count(old, []byte{'\n'})
+}
+
+// rangeShifter produces deep copies of AST nodes with all of their source
+// positions moved by a fixed number of bytes and lines.
+//
+// Only nodes that begin at the start of a line are shifted, so the column
+// positions are always unchanged.
+type rangeShifter struct {
+	bytes  int
+	lines  int
+	mapper *posMapper
+}
+
+func newRangeShifter(bytes, lines int) *rangeShifter {
+	s := &rangeShifter{
+		bytes: bytes,
+		lines: lines,
+	}
+	s.mapper = newPosMapper(s.ShiftPos)
+	return s
+}
+
+func (s *rangeShifter) ShiftPos(pos hcl.Pos) hcl.Pos {
+	pos.Byte += s.bytes
+	pos.Line += s.lines
+	return pos
+}
+
+func (s *rangeShifter) Shift(v interface{}) interface{} {
+	if s.bytes == 0 && s.lines == 0 {
+		// Nothing moved, so we can safely re-use the existing objects.
+		return v
+	}
+	return s.mapper.Map(v)
+}
@@ -0,0 +1,123 @@
+package hclsyntax
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+func TestReparseConfig(t *testing.T) {
+	const src = `a = 1
+b = "hello ${name}"
+
+block "label" {
+  c = [for x in list: x.y]
+  d = foo.*.bar
+}
+
+# A comment
+e = <<EOT
+heredoc ${x}
+EOT
+other {
+  f = {
+    g = 1
+  }
+}
+h = true
+`
+
+	tests := []struct {
+		Start, End  int
+		Replacement string
+	}{
+		{0, 0, ""},
+		{4, 5, "2"},
+		{4, 5, "200"},
+		{5, 5, "0\nz = 1"},
+		{6, 6, "new = 2\n"},
+		{0, 0, "first = 0\n"},
+		{11, 11, "big "},
+		{23, 23, "\n\n"},
+		{40, 41, "zz"},
+		{40, 41, "\n"},
+		{75, 76, ""},
+		{76, 88, ""},
+		{80, 80, "/*"},
+		{80, 80, "/* */"},
+		{88, 88, "x = <<EOT\n"},
+		{100, 105, "replaced"},
+		{123, 123, "\n  extra = 1"},
+		{len(src), len(src), "i = 2\n"},
+		{len(src) - 1, len(src), ""},
+		{0, len(src), "a = 1\n"},
+		{5, 5, " # now a line comment"},
+		{5, 5, "\n# now a lead comment"},
+		{100, 100, "\n"}, // separates a lead comment from its attribute
+		{91, 92, "Changed"},
+		{6, 6, "a = 2\n"}, // duplicate attribute, so must produce an error
+		{4, 4, "("},       // unbalanced paren, so must produce an error
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d-%d %q", test.Start, test.End, test.Replacement), func(t *testing.T) {
+			prev, diags := ParseConfig([]byte(src), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors in initial parse: %s", diags.Error())
+			}
+
+			edit := hcl.Range{
+				Start: hcl.Pos{Byte: test.Start},
+				End:   hcl.Pos{Byte: test.End},
+			}
+			got, gotDiags := ReparseConfig(prev, edit, []byte(test.Replacement))
+
+			wantSrc := src[:test.Start] + test.Replacement + src[test.End:]
+			if string(got.Bytes) != wantSrc {
+				t.Fatalf("wrong new source\ngot:  %q\nwant: %q", got.Bytes, wantSrc)
+			}
+			want, wantDiags := ParseConfig([]byte(wantSrc), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+
+			if len(gotDiags) != len(wantDiags) {
+				t.Errorf("wrong number of diagnostics %d; want %d", len(gotDiags), len(wantDiags))
+			}
+			for _, problem := range deep.Equal(got.Body, want.Body) {
+				t.Error(problem)
+			}
+		})
+	}
+}
+
+func TestReparseConfigSequence(t *testing.T) {
+	// Simulates a user typing a new attribute one character at a time into
+	// the middle of a file, re-parsing after each keystroke.
+	const src = "a = 1\n\nblock {\n  b = 2\n}\n"
+	const typed = "c = [1, \"two\", { three = 3 }]\n"
+
+	f, diags := ParseConfig([]byte(src), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors in initial parse: %s", diags.Error())
+	}
+
+	pos := 6
+	for i := 0; i < len(typed); i++ {
+		edit := hcl.Range{
+			Start: hcl.Pos{Byte: pos},
+			End:   hcl.Pos{Byte: pos},
+		}
+		f, diags = ReparseConfig(f, edit, []byte(typed[i:i+1]))
+		pos++
+		if diags.HasErrors() {
+			// Partially-typed input is fine, but we need a full parse to
+			// continue incrementally, just as a real caller would.
+			f, _ = ParseConfig(f.Bytes, "test.hcl", hcl.Pos{Line: 1, Column: 1})
+		}
+
+		want, _ := ParseConfig(f.Bytes, "test.hcl", hcl.Pos{Line: 1, Column: 1})
+		for _, problem := range deep.Equal(f.Body, want.Body) {
+			t.Errorf("after typing %q: %s", typed[:i+1], problem)
+		}
+	}
+}
@@ -10,7 +10,7 @@ import (
 
 // This file is generated from scan_tokens.rl. DO NOT EDIT.
 
-//line scan_tokens.go:15
+//line scan_tokens.go:14
 var _hcltok_actions []byte = []byte{
 	0, 1, 0, 1, 1, 1, 3, 1, 4,
 	1, 7, 1, 8, 1, 9, 1, 10,
@@ -25,18 +25,19 @@ var _hcltok_actions []byte = []byte{
 	1, 49, 1, 50, 1, 51, 1, 52,
 	1, 53, 1, 56, 1, 57, 1, 58,
 	1, 59, 1, 60, 1, 61, 1, 62,
-	1, 63, 1, 64, 1, 65, 1, 66,
-	1, 67, 1, 68, 1, 69, 1, 70,
-	1, 71, 1, 72, 1, 73, 1, 74,
-	1, 75, 1, 76, 1, 77, 1, 78,
-	1, 79, 1, 80, 1, 81, 1, 82,
-	1, 83, 1, 84, 1, 85, 2, 0,
+	1, 63, 1, 64, 1, 65, 1, 68,
+	1, 69, 1, 70, 1, 71, 1, 72,
+	1, 73, 1, 74, 1, 75, 1, 76,
+	1, 77, 1, 79, 1, 80, 1, 81,
+	1, 82, 1, 83, 1, 84, 1, 86,
+	1, 87, 1, 88, 1, 89, 2, 0,
 	14, 2, 0, 25, 2, 0, 29, 2,
 	0, 37, 2, 0, 41, 2, 1, 2,
 	2, 4, 5, 2, 4, 6, 2, 4,
 	21, 2, 4, 22, 2, 4, 33, 2,
 	4, 34, 2, 4, 45, 2, 4, 46,
-	2, 4, 54, 2, 4, 55,
+	2, 4, 54, 2, 4, 55, 1, 66,
+	1, 67, 1, 78, 1, 85,
 }
 
 var _hcltok_key_offsets []int16 = []int16{
@@ -222,22 +223,24 @@ var _hcltok_key_offsets []int16 = []int16{
 	9153, 9171, 9172, 9182, 9183, 9192, 9200, 9202,
 	9205, 9207, 9209, 9211, 9216, 9229, 9233, 9248,
 	9277, 9288, 9290, 9294, 9298, 9303, 9307, 9309,
-	9316, 9320, 9328, 9332, 9407, 9409, 9410, 9411,
-	9412, 9413, 9414, 9416, 9421, 9423, 9425, 9426,
-	9470, 9471, 9472, 9474, 9479, 9483, 9483, 9485,
-	9487, 9498, 9508, 9516, 9517, 9519, 9520, 9524,
-	9528, 9538, 9542, 9549, 9560, 9567, 9571, 9577,
-	9588, 9620, 9669, 9684, 9699, 9704, 9706, 9711,
-	9743, 9751, 9753, 9775, 9797, 9799, 9815, 9831,
-	9833, 9835, 9835, 9836, 9837, 9838, 9840, 9841,
-	9853, 9855, 9857, 9859, 9873, 9887, 9889, 9892,
-	9895, 9897, 9898, 9899, 9901, 9903, 9905, 9919,
-	9933, 9935, 9938, 9941, 9943, 9944, 9945, 9947,
-	9949, 9951, 10000, 10044, 10046, 10051, 10055, 10055,
-	10057, 10059, 10070, 10080, 10088, 10089, 10091, 10092,
-	10096, 10100, 10110, 10114, 10121, 10132, 10139, 10143,
-	10149, 10160, 10192, 10241, 10256, 10271, 10276, 10278,
-	10283, 10315, 10323, 10325, 10347, 10369,
+	9316, 9320, 9328, 9332, 9337, 9341, 9343, 9345,
+	9347, 9424, 9426, 9427, 9428, 9429, 9430, 9433,
+	9435, 9441, 9443, 9445, 9446, 9490, 9491, 9492,
+	9494, 9499, 9503, 9503, 9505, 9507, 9518, 9528,
+	9536, 9537, 9539, 9540, 9544, 9548, 9558, 9562,
+	9569, 9580, 9587, 9591, 9597, 9608, 9640, 9689,
+	9704, 9719, 9724, 9726, 9731, 9763, 9771, 9773,
+	9795, 9817, 9819, 9835, 9851, 9853, 9855, 9855,
+	9856, 9857, 9858, 9860, 9861, 9873, 9875, 9877,
+	9879, 9893, 9907, 9909, 9912, 9915, 9917, 9918,
+	9919, 9921, 9923, 9925, 9939, 9953, 9955, 9958,
+	9961, 9963, 9964, 9965, 9967, 9969, 9971, 10020,
+	10064, 10066, 10071, 10075, 10075, 10077, 10079, 10090,
+	10100, 10108, 10109, 10111, 10112, 10116, 10120, 10130,
+	10134, 10141, 10152, 10159, 10163, 10169, 10180, 10212,
+	10261, 10276, 10291, 10296, 10298, 10303, 10335, 10343,
+	10345, 10367, 10389, 10391, 10393, 10398, 10411, 10418,
+	10426, 10429,
 }
 
 var _hcltok_trans_keys []byte = []byte{
@@ -1407,96 +1410,27 @@ var _hcltok_trans_keys []byte = []byte{
 	191, 192, 255, 158, 159, 186, 128, 185,
 	187, 191, 192, 255, 162, 191, 192, 255,
 	160, 168, 128, 159, 161, 167, 169, 191,
-	158, 191, 192, 255, 9, 10, 13, 32,
-	33, 34, 35, 38, 46, 47, 60, 61,
-	62, 64, 92, 95, 123, 124, 125, 126,
-	127, 194, 195, 198, 199, 203, 204, 205,
-	206, 207, 210, 212, 213, 214, 215, 216,
-	217, 219, 220, 221, 222, 223, 224, 225,
-	226, 227, 228, 233, 234, 237, 238, 239,
-	240, 0, 36, 37, 45, 48, 57, 58,
-	63, 65, 90, 91, 96, 97, 122, 192,
-	193, 196, 218, 229, 236, 241, 247, 9,
-	32, 10, 61, 10, 38, 46, 42, 47,
-	46, 69, 101, 48, 57, 60, 61, 61,
-	62, 61, 45, 95, 194, 195, 198, 199,
-	203, 204, 205, 206, 207, 210, 212, 213,
-	214, 215, 216, 217, 219, 220, 221, 222,
-	223, 224, 225, 226, 227, 228, 233, 234,
-	237, 239, 240, 243, 48, 57, 65, 90,
-	97, 122, 196, 218, 229, 236, 124, 125,
-	128, 191, 170, 181, 186, 128, 191, 151,
-	183, 128, 255, 192, 255, 0, 127, 173,
-	130, 133, 146, 159, 165, 171, 175, 191,
-	192, 255, 181, 190, 128, 175, 176, 183,
-	184, 185, 186, 191, 134, 139, 141, 162,
-	128, 135, 136, 255, 182, 130, 137, 176,
-	151, 152, 154, 160, 136, 191, 192, 255,
-	128, 143, 144, 170, 171, 175, 176, 178,
-	179, 191, 128, 159, 160, 191, 176, 128,
-	138, 139, 173, 174, 255, 148, 150, 164,
-	167, 173, 176, 185, 189, 190, 192, 255,
-	144, 128, 145, 146, 175, 176, 191, 128,
-	140, 141, 255, 166, 176, 178, 191, 192,
-	255, 186, 128, 137, 138, 170, 171, 179,
-	180, 181, 182, 191, 160, 161, 162, 164,
-	165, 166, 167, 168, 169, 170, 171, 172,
-	173, 174, 175, 176, 177, 178, 179, 180,
-	181, 182, 183, 184, 185, 186, 187, 188,
-	189, 190, 128, 191, 128, 129, 130, 131,
-	137, 138, 139, 140, 141, 142, 143, 144,
-	153, 154, 155, 156, 157, 158, 159, 160,
-	161, 162, 163, 164, 165, 166, 167, 168,
-	169, 170, 171, 172, 173, 174, 175, 176,
-	177, 178, 179, 180, 182, 183, 184, 188,
-	189, 190, 191, 132, 187, 129, 130, 132,
-	133, 134, 176, 177, 178, 179, 180, 181,
-	182, 183, 128, 191, 128, 129, 130, 131,
-	132, 133, 134, 135, 144, 136, 143, 145,
-	191, 192, 255, 182, 183, 184, 128, 191,
-	128, 191, 191, 128, 190, 192, 255, 128,
-	146, 147, 148, 152, 153, 154, 155, 156,
-	158, 159, 160, 161, 162, 163, 164, 165,
-	166, 167, 168, 169, 170, 171, 172, 173,
-	174, 175, 176, 129, 191, 192, 255, 158,
-	159, 128, 157, 160, 191, 192, 255, 128,
-	191, 164, 169, 171, 172, 173, 174, 175,
-	180, 181, 182, 183, 184, 185, 187, 188,
-	189, 190, 191, 128, 163, 165, 186, 144,
-	145, 146, 147, 148, 150, 151, 152, 155,
-	157, 158, 160, 170, 171, 172, 175, 128,
-	159, 161, 169, 173, 191, 128, 191, 10,
-	13, 34, 36, 37, 92, 128, 191, 192,
-	223, 224, 239, 240, 247, 248, 255, 10,
-	13, 34, 92, 36, 37, 128, 191, 192,
-	223, 224, 239, 240, 247, 248, 255, 10,
-	13, 36, 123, 123, 126, 126, 37, 123,
-	126, 10, 13, 128, 191, 192, 223, 224,
-	239, 240, 247, 248, 255, 128, 191, 128,
-	191, 128, 191, 10, 13, 36, 37, 128,
-	191, 192, 223, 224, 239, 240, 247, 248,
-	255, 10, 13, 36, 37, 128, 191, 192,
-	223, 224, 239, 240, 247, 248, 255, 10,
-	13, 10, 13, 123, 10, 13, 126, 10,
-	13, 126, 126, 128, 191, 128, 191, 128,
-	191, 10, 13, 36, 37, 128, 191, 192,
-	223, 224, 239, 240, 247, 248, 255, 10,
-	13, 36, 37, 128, 191, 192, 223, 224,
-	239, 240, 247, 248, 255, 10, 13, 10,
-	13, 123, 10, 13, 126, 10, 13, 126,
-	126, 128, 191, 128, 191, 128, 191, 95,
+	158, 191, 192, 255, 46, 69, 101, 48,
+	57, 43, 45, 48, 57, 48, 57, 48,
+	57, 48, 57, 9, 10, 13, 32, 33,
+	34, 35, 38, 46, 47, 48, 60, 61,
+	62, 63, 64, 92, 95, 123, 124, 125,
+	126, 127, 194, 195, 198, 199, 203, 204,
+	205, 206, 207, 210, 212, 213, 214, 215,
+	216, 217, 219, 220, 221, 222, 223, 224,
+	225, 226, 227, 228, 233, 234, 237, 238,
+	239, 240, 0, 36, 37, 45, 49, 57,
+	58, 62, 65, 90, 91, 96, 97, 122,
+	192, 193, 196, 218, 229, 236, 241, 247,
+	9, 32, 10, 61, 10, 38, 46, 48,
+	57, 42, 47, 46, 69, 95, 101, 48,
+	57, 60, 61, 61, 62, 61, 45, 95,
 	194, 195, 198, 199, 203, 204, 205, 206,
 	207, 210, 212, 213, 214, 215, 216, 217,
 	219, 220, 221, 222, 223, 224, 225, 226,
-	227, 228, 233, 234, 237, 238, 239, 240,
-	65, 90, 97, 122, 128, 191, 192, 193,
-	196, 218, 229, 236, 241, 247, 248, 255,
-	45, 95, 194, 195, 198, 199, 203, 204,
-	205, 206, 207, 210, 212, 213, 214, 215,
-	216, 217, 219, 220, 221, 222, 223, 224,
-	225, 226, 227, 228, 233, 234, 237, 239,
-	240, 243, 48, 57, 65, 90, 97, 122,
-	196, 218, 229, 236, 128, 191, 170, 181,
+	227, 228, 233, 234, 237, 239, 240, 243,
+	48, 57, 65, 90, 97, 122, 196, 218,
+	229, 236, 124, 125, 128, 191, 170, 181,
 	186, 128, 191, 151, 183, 128, 255, 192,
 	255, 0, 127, 173, 130, 133, 146, 159,
 	165, 171, 175, 191, 192, 255, 181, 190,
@@ -1537,7 +1471,84 @@ var _hcltok_trans_keys []byte = []byte{
 	163, 165, 186, 144, 145, 146, 147, 148,
 	150, 151, 152, 155, 157, 158, 160, 170,
 	171, 172, 175, 128, 159, 161, 169, 173,
-	191, 128, 191,
+	191, 128, 191, 10, 13, 34, 36, 37,
+	92, 128, 191, 192, 223, 224, 239, 240,
+	247, 248, 255, 10, 13, 34, 92, 36,
+	37, 128, 191, 192, 223, 224, 239, 240,
+	247, 248, 255, 10, 13, 36, 123, 123,
+	126, 126, 37, 123, 126, 10, 13, 128,
+	191, 192, 223, 224, 239, 240, 247, 248,
+	255, 128, 191, 128, 191, 128, 191, 10,
+	13, 36, 37, 128, 191, 192, 223, 224,
+	239, 240, 247, 248, 255, 10, 13, 36,
+	37, 128, 191, 192, 223, 224, 239, 240,
+	247, 248, 255, 10, 13, 10, 13, 123,
+	10, 13, 126, 10, 13, 126, 126, 128,
+	191, 128, 191, 128, 191, 10, 13, 36,
+	37, 128, 191, 192, 223, 224, 239, 240,
+	247, 248, 255, 10, 13, 36, 37, 128,
+	191, 192, 223, 224, 239, 240, 247, 248,
+	255, 10, 13, 10, 13, 123, 10, 13,
+	126, 10, 13, 126, 126, 128, 191, 128,
+	191, 128, 191, 95, 194, 195, 198, 199,
+	203, 204, 205, 206, 207, 210, 212, 213,
+	214, 215, 216, 217, 219, 220, 221, 222,
+	223, 224, 225, 226, 227, 228, 233, 234,
+	237, 238, 239, 240, 65, 90, 97, 122,
+	128, 191, 192, 193, 196, 218, 229, 236,
+	241, 247, 248, 255, 45, 95, 194, 195,
+	198, 199, 203, 204, 205, 206, 207, 210,
+	212, 213, 214, 215, 216, 217, 219, 220,
+	221, 222, 223, 224, 225, 226, 227, 228,
+	233, 234, 237, 239, 240, 243, 48, 57,
+	65, 90, 97, 122, 196, 218, 229, 236,
+	128, 191, 170, 181, 186, 128, 191, 151,
+	183, 128, 255, 192, 255, 0, 127, 173,
+	130, 133, 146, 159, 165, 171, 175, 191,
+	192, 255, 181, 190, 128, 175, 176, 183,
+	184, 185, 186, 191, 134, 139, 141, 162,
+	128, 135, 136, 255, 182, 130, 137, 176,
+	151, 152, 154, 160, 136, 191, 192, 255,
+	128, 143, 144, 170, 171, 175, 176, 178,
+	179, 191, 128, 159, 160, 191, 176, 128,
+	138, 139, 173, 174, 255, 148, 150, 164,
+	167, 173, 176, 185, 189, 190, 192, 255,
+	144, 128, 145, 146, 175, 176, 191, 128,
+	140, 141, 255, 166, 176, 178, 191, 192,
+	255, 186, 128, 137, 138, 170, 171, 179,
+	180, 181, 182, 191, 160, 161, 162, 164,
+	165, 166, 167, 168, 169, 170, 171, 172,
+	173, 174, 175, 176, 177, 178, 179, 180,
+	181, 182, 183, 184, 185, 186, 187, 188,
+	189, 190, 128, 191, 128, 129, 130, 131,
+	137, 138, 139, 140, 141, 142, 143, 144,
+	153, 154, 155, 156, 157, 158, 159, 160,
+	161, 162, 163, 164, 165, 166, 167, 168,
+	169, 170, 171, 172, 173, 174, 175, 176,
+	177, 178, 179, 180, 182, 183, 184, 188,
+	189, 190, 191, 132, 187, 129, 130, 132,
+	133, 134, 176, 177, 178, 179, 180, 181,
+	182, 183, 128, 191, 128, 129, 130, 131,
+	132, 133, 134, 135, 144, 136, 143, 145,
+	191, 192, 255, 182, 183, 184, 128, 191,
+	128, 191, 191, 128, 190, 192, 255, 128,
+	146, 147, 148, 152, 153, 154, 155, 156,
+	158, 159, 160, 161, 162, 163, 164, 165,
+	166, 167, 168, 169, 170, 171, 172, 173,
+	174, 175, 176, 129, 191, 192, 255, 158,
+	159, 128, 157, 160, 191, 192, 255, 128,
+	191, 164, 169, 171, 172, 173, 174, 175,
+	180, 181, 182, 183, 184, 185, 187, 188,
+	189, 190, 191, 128, 163, 165, 186, 144,
+	145, 146, 147, 148, 150, 151, 152, 155,
+	157, 158, 160, 170, 171, 172, 175, 128,
+	159, 161, 169, 173, 191, 128, 191, 46,
+	63, 46, 69, 101, 48, 57, 46, 48,
+	66, 69, 79, 88, 95, 98, 101, 111,
+	120, 49, 57, 95, 48, 57, 65, 70,
+	97, 102, 46, 95, 48, 57, 65, 70,
+	97, 102, 95, 48, 57, 46, 95, 48,
+	57,
 }
 
 var _hcltok_single_lengths []byte = []byte{
@@ -1723,22 +1734,24 @@ var _hcltok_single_lengths []byte = []byte{
 	12, 1, 4, 1, 5, 2, 0, 3,
 	2, 2, 2, 1, 7, 0, 7, 17,
 	3, 0, 2, 0, 3, 0, 0, 1,
-	0, 2, 0, 53, 2, 1, 1, 1,
-	1, 1, 2, 3, 2, 2, 1, 34,
-	1, 1, 0, 3, 2, 0, 0, 0,
-	1, 2, 4, 1, 0, 1, 0, 0,
-	0, 0, 1, 1, 1, 0, 0, 1,
-	30, 47, 13, 9, 3, 0, 1, 28,
-	2, 0, 18, 16, 0, 6, 4, 2,
-	2, 0, 1, 1, 1, 2, 1, 2,
+	0, 2, 0, 3, 2, 0, 0, 0,
+	55, 2, 1, 1, 1, 1, 1, 2,
+	4, 2, 2, 1, 34, 1, 1, 0,
+	3, 2, 0, 0, 0, 1, 2, 4,
+	1, 0, 1, 0, 0, 0, 0, 1,
+	1, 1, 0, 0, 1, 30, 47, 13,
+	9, 3, 0, 1, 28, 2, 0, 18,
+	16, 0, 6, 4, 2, 2, 0, 1,
+	1, 1, 2, 1, 2, 0, 0, 0,
+	4, 2, 2, 3, 3, 2, 1, 1,
 	0, 0, 0, 4, 2, 2, 3, 3,
-	2, 1, 1, 0, 0, 0, 4, 2,
-	2, 3, 3, 2, 1, 1, 0, 0,
-	0, 33, 34, 0, 3, 2, 0, 0,
-	0, 1, 2, 4, 1, 0, 1, 0,
-	0, 0, 0, 1, 1, 1, 0, 0,
-	1, 30, 47, 13, 9, 3, 0, 1,
-	28, 2, 0, 18, 16, 0,
+	2, 1, 1, 0, 0, 0, 33, 34,
+	0, 3, 2, 0, 0, 0, 1, 2,
+	4, 1, 0, 1, 0, 0, 0, 0,
+	1, 1, 1, 0, 0, 1, 30, 47,
+	13, 9, 3, 0, 1, 28, 2, 0,
+	18, 16, 0, 2, 3, 11, 1, 2,
+	1, 2,
 }
 
 var _hcltok_range_lengths []byte = []byte{
@@ -1924,22 +1937,24 @@ var _hcltok_range_lengths []byte = []byte{
 	3, 0, 3, 0, 2, 3, 1, 0,
 	0, 0, 0, 2, 3, 2, 4, 6,
 	4, 1, 1, 2, 1, 2, 1, 3,
-	2, 3, 2, 11, 0, 0, 0, 0,
-	0, 0, 0, 1, 0, 0, 0, 5,
-	0, 0, 1, 1, 1, 0, 1, 1,
-	5, 4, 2, 0, 1, 0, 2, 2,
-	5, 2, 3, 5, 3, 2, 3, 5,
-	1, 1, 1, 3, 1, 1, 2, 2,
-	3, 1, 2, 3, 1, 5, 6, 0,
-	0, 0, 0, 0, 0, 0, 0, 5,
+	2, 3, 2, 1, 1, 1, 1, 1,
+	11, 0, 0, 0, 0, 0, 1, 0,
+	1, 0, 0, 0, 5, 0, 0, 1,
+	1, 1, 0, 1, 1, 5, 4, 2,
+	0, 1, 0, 2, 2, 5, 2, 3,
+	5, 3, 2, 3, 5, 1, 1, 1,
+	3, 1, 1, 2, 2, 3, 1, 2,
+	3, 1, 5, 6, 0, 0, 0, 0,
+	0, 0, 0, 0, 5, 1, 1, 1,
+	5, 6, 0, 0, 0, 0, 0, 0,
 	1, 1, 1, 5, 6, 0, 0, 0,
-	0, 0, 0, 1, 1, 1, 5, 6,
-	0, 0, 0, 0, 0, 0, 1, 1,
-	1, 8, 5, 1, 1, 1, 0, 1,
-	1, 5, 4, 2, 0, 1, 0, 2,
-	2, 5, 2, 3, 5, 3, 2, 3,
-	5, 1, 1, 1, 3, 1, 1, 2,
-	2, 3, 1, 2, 3, 1,
+	0, 0, 0, 1, 1, 1, 8, 5,
+	1, 1, 1, 0, 1, 1, 5, 4,
+	2, 0, 1, 0, 2, 2, 5, 2,
+	3, 5, 3, 2, 3, 5, 1, 1,
+	1, 3, 1, 1, 2, 2, 3, 1,
+	2, 3, 1, 0, 1, 1, 3, 3,
+	1, 1,
 }
 
 var _hcltok_index_offsets []int16 = []int16{
@@ -2125,22 +2140,24 @@ var _hcltok_index_offsets []int16 = []int16{
 	7187, 7203, 7205, 7213, 7215, 7223, 7229, 7231,
 	7235, 7238, 7241, 7244, 7248, 7259, 7262, 7274,
 	7298, 7306, 7308, 7312, 7315, 7320, 7323, 7325,
-	7330, 7333, 7339, 7342, 7407, 7410, 7412, 7414,
-	7416, 7418, 7420, 7423, 7428, 7431, 7434, 7436,
-	7476, 7478, 7480, 7482, 7487, 7491, 7492, 7494,
-	7496, 7503, 7510, 7517, 7519, 7521, 7523, 7526,
-	7529, 7535, 7538, 7543, 7550, 7555, 7558, 7562,
-	7569, 7601, 7650, 7665, 7678, 7683, 7685, 7689,
-	7720, 7726, 7728, 7749, 7769, 7771, 7783, 7794,
-	7797, 7800, 7801, 7803, 7805, 7807, 7810, 7812,
-	7820, 7822, 7824, 7826, 7836, 7845, 7848, 7852,
-	7856, 7859, 7861, 7863, 7865, 7867, 7869, 7879,
-	7888, 7891, 7895, 7899, 7902, 7904, 7906, 7908,
-	7910, 7912, 7954, 7994, 7996, 8001, 8005, 8006,
-	8008, 8010, 8017, 8024, 8031, 8033, 8035, 8037,
-	8040, 8043, 8049, 8052, 8057, 8064, 8069, 8072,
-	8076, 8083, 8115, 8164, 8179, 8192, 8197, 8199,
-	8203, 8234, 8240, 8242, 8263, 8283,
+	7330, 7333, 7339, 7342, 7347, 7351, 7353, 7355,
+	7357, 7424, 7427, 7429, 7431, 7433, 7435, 7438,
+	7441, 7447, 7450, 7453, 7455, 7495, 7497, 7499,
+	7501, 7506, 7510, 7511, 7513, 7515, 7522, 7529,
+	7536, 7538, 7540, 7542, 7545, 7548, 7554, 7557,
+	7562, 7569, 7574, 7577, 7581, 7588, 7620, 7669,
+	7684, 7697, 7702, 7704, 7708, 7739, 7745, 7747,
+	7768, 7788, 7790, 7802, 7813, 7816, 7819, 7820,
+	7822, 7824, 7826, 7829, 7831, 7839, 7841, 7843,
+	7845, 7855, 7864, 7867, 7871, 7875, 7878, 7880,
+	7882, 7884, 7886, 7888, 7898, 7907, 7910, 7914,
+	7918, 7921, 7923, 7925, 7927, 7929, 7931, 7973,
+	8013, 8015, 8020, 8024, 8025, 8027, 8029, 8036,
+	8043, 8050, 8052, 8054, 8056, 8059, 8062, 8068,
+	8071, 8076, 8083, 8088, 8091, 8095, 8102, 8134,
+	8183, 8198, 8211, 8216, 8218, 8222, 8253, 8259,
+	8261, 8282, 8302, 8304, 8307, 8312, 8325, 8330,
+	8336, 8339,
 }
 
 var _hcltok_indicies []int16 = []int16{
@@ -3061,134 +3078,141 @@ var _hcltok_indicies []int16 = []int16{
 	1046, 1045, 795, 1138, 1050, 1139, 1059, 801,
 	1046, 1045, 795, 1046, 795, 1140, 1059, 1047,
 	1045, 801, 1046, 1045, 795, 1050, 1141, 1047,
-	1059, 1047, 1045, 1046, 1045, 795, 1142, 1143,
-	1144, 1142, 1145, 1146, 1147, 1149, 1150, 1151,
-	1152, 1153, 1154, 670, 670, 419, 1155, 1156,
-	1157, 1158, 670, 1161, 1162, 1164, 1165, 1166,
-	1160, 1167, 1168, 1169, 1170, 1171, 1172, 1173,
-	1174, 1175, 1176, 1177, 1178, 1179, 1180, 1181,
-	1182, 1183, 1184, 1185, 1186, 1188, 1189, 1190,
-	1191, 1192, 1193, 670, 1148, 7, 1148, 419,
-	1148, 419, 1160, 1163, 1187, 1194, 1159, 1142,
-	1142, 1195, 1143, 1196, 1198, 1197, 4, 1147,
-	1200, 1197, 1201, 1197, 2, 1147, 1197, 6,
-	8, 8, 7, 1202, 1203, 1204, 1197, 1205,
-	1206, 1197, 1207, 1197, 419, 419, 1209, 1210,
-	489, 470, 1211, 470, 1212, 1213, 1214, 1215,
-	1216, 1217, 1218, 1219, 1220, 1221, 1222, 544,
-	1223, 520, 1224, 1225, 1226, 1227, 1228, 1229,
-	1230, 1231, 1232, 1233, 1234, 1235, 419, 419,
-	419, 425, 565, 1208, 1236, 1197, 1237, 1197,
-	670, 1238, 419, 419, 419, 670, 1238, 670,
-	670, 419, 1238, 419, 1238, 419, 1238, 419,
-	670, 670, 670, 670, 670, 1238, 419, 670,
-	670, 670, 419, 670, 419, 1238, 419, 670,
-	670, 670, 670, 419, 1238, 670, 419, 670,
-	419, 670, 419, 670, 670, 419, 670, 1238,
-	419, 670, 419, 670, 419, 670, 1238, 670,
-	419, 1238, 670, 419, 670, 419, 1238, 670,
-	670, 670, 670, 670, 1238, 419, 419, 670,
-	419, 670, 1238, 670, 419, 1238, 670, 670,
-	1238, 419, 419, 670, 419, 670, 419, 670,
-	1238, 1239, 1240, 1241, 1242, 1243, 1244, 1245,
-	1246, 1247, 1248, 1249, 715, 1250, 1251, 1252,
-	1253, 1254, 1255, 1256, 1257, 1258, 1259, 1260,
-	1261, 1260, 1262, 1263, 1264, 1265, 1266, 671,
-	1238, 1267, 1268, 1269, 1270, 1271, 1272, 1273,
-	1274, 1275, 1276, 1277, 1278, 1279, 1280, 1281,
-	1282, 1283, 1284, 1285, 725, 1286, 1287, 1288,
-	692, 1289, 1290, 1291, 1292, 1293, 1294, 671,
-	1295, 1296, 1297, 1298, 1299, 1300, 1301, 1302,
-	674, 1303, 671, 674, 1304, 1305, 1306, 1307,
-	683, 1238, 1308, 1309, 1310, 1311, 703, 1312,
-	1313, 683, 1314, 1315, 1316, 1317, 1318, 671,
-	1238, 1319, 1278, 1320, 1321, 1322, 683, 1323,
-	1324, 674, 671, 683, 425, 1238, 1288, 671,
-	674, 683, 425, 683, 425, 1325, 683, 1238,
-	425, 674, 1326, 1327, 674, 1328, 1329, 681,
-	1330, 1331, 1332, 1333, 1334, 1284, 1335, 1336,
-	1337, 1338, 1339, 1340, 1341, 1342, 1343, 1344,
-	1345, 1346, 1303, 1347, 674, 683, 425, 1238,
-	1348, 1349, 683, 671, 1238, 425, 671, 1238,
-	674, 1350, 731, 1351, 1352, 1353, 1354, 1355,
-	1356, 1357, 1358, 671, 1359, 1360, 1361, 1362,
-	1363, 1364, 671, 683, 1238, 1366, 1367, 1368,
-	1369, 1370, 1371, 1372, 1373, 1374, 1375, 1376,
-	1372, 1378, 1379, 1380, 1381, 1365, 1377, 1365,
-	1238, 1365, 1238, 1382, 1382, 1383, 1384, 1385,
-	1386, 1387, 1388, 1389, 1390, 1387, 767, 1391,
-	1391, 1391, 1392, 1391, 1391, 768, 769, 770,
-	1391, 767, 1382, 1382, 1393, 1396, 1397, 1395,
-	1398, 1399, 1398, 1400, 1391, 1402, 1401, 1396,
-	1403, 1395, 1405, 1404, 1394, 1394, 1394, 768,
-	769, 770, 1394, 767, 767, 1406, 773, 1406,
-	1407, 1406, 775, 1408, 1409, 1410, 1411, 1412,
-	1413, 1414, 1411, 776, 775, 1408, 1415, 1415,
-	777, 779, 1416, 1415, 776, 1418, 1419, 1417,
-	1418, 1419, 1420, 1417, 775, 1408, 1421, 1415,
-	775, 1408, 1415, 1423, 1422, 1425, 1424, 776,
-	1426, 777, 1426, 779, 1426, 785, 1427, 1428,
-	1429, 1430, 1431, 1432, 1433, 1430, 786, 785,
-	1427, 1434, 1434, 787, 789, 1435, 1434, 786,
-	1437, 1438, 1436, 1437, 1438, 1439, 1436, 785,
-	1427, 1440, 1434, 785, 1427, 1434, 1442, 1441,
-	1444, 1443, 786, 1445, 787, 1445, 789, 1445,
-	795, 1448, 1449, 1451, 1452, 1453, 1447, 1454,
-	1455, 1456, 1457, 1458, 1459, 1460, 1461, 1462,
-	1463, 1464, 1465, 1466, 1467, 1468, 1469, 1470,
-	1471, 1472, 1473, 1475, 1476, 1477, 1478, 1479,
-	1480, 795, 795, 1446, 1447, 1450, 1474, 1481,
-	1446, 1046, 795, 795, 1483, 1484, 865, 846,
-	1485, 846, 1486, 1487, 1488, 1489, 1490, 1491,
-	1492, 1493, 1494, 1495, 1496, 920, 1497, 896,
-	1498, 1499, 1500, 1501, 1502, 1503, 1504, 1505,
-	1506, 1507, 1508, 1509, 795, 795, 795, 801,
-	941, 1482, 1046, 1510, 795, 795, 795, 1046,
-	1510, 1046, 1046, 795, 1510, 795, 1510, 795,
-	1510, 795, 1046, 1046, 1046, 1046, 1046, 1510,
-	795, 1046, 1046, 1046, 795, 1046, 795, 1510,
-	795, 1046, 1046, 1046, 1046, 795, 1510, 1046,
-	795, 1046, 795, 1046, 795, 1046, 1046, 795,
-	1046, 1510, 795, 1046, 795, 1046, 795, 1046,
-	1510, 1046, 795, 1510, 1046, 795, 1046, 795,
-	1510, 1046, 1046, 1046, 1046, 1046, 1510, 795,
-	795, 1046, 795, 1046, 1510, 1046, 795, 1510,
-	1046, 1046, 1510, 795, 795, 1046, 795, 1046,
-	795, 1046, 1510, 1511, 1512, 1513, 1514, 1515,
-	1516, 1517, 1518, 1519, 1520, 1521, 1091, 1522,
-	1523, 1524, 1525, 1526, 1527, 1528, 1529, 1530,
-	1531, 1532, 1533, 1532, 1534, 1535, 1536, 1537,
-	1538, 1047, 1510, 1539, 1540, 1541, 1542, 1543,
-	1544, 1545, 1546, 1547, 1548, 1549, 1550, 1551,
-	1552, 1553, 1554, 1555, 1556, 1557, 1101, 1558,
-	1559, 1560, 1068, 1561, 1562, 1563, 1564, 1565,
-	1566, 1047, 1567, 1568, 1569, 1570, 1571, 1572,
-	1573, 1574, 1050, 1575, 1047, 1050, 1576, 1577,
-	1578, 1579, 1059, 1510, 1580, 1581, 1582, 1583,
-	1079, 1584, 1585, 1059, 1586, 1587, 1588, 1589,
-	1590, 1047, 1510, 1591, 1550, 1592, 1593, 1594,
-	1059, 1595, 1596, 1050, 1047, 1059, 801, 1510,
-	1560, 1047, 1050, 1059, 801, 1059, 801, 1597,
-	1059, 1510, 801, 1050, 1598, 1599, 1050, 1600,
-	1601, 1057, 1602, 1603, 1604, 1605, 1606, 1556,
-	1607, 1608, 1609, 1610, 1611, 1612, 1613, 1614,
-	1615, 1616, 1617, 1618, 1575, 1619, 1050, 1059,
-	801, 1510, 1620, 1621, 1059, 1047, 1510, 801,
-	1047, 1510, 1050, 1622, 1107, 1623, 1624, 1625,
-	1626, 1627, 1628, 1629, 1630, 1047, 1631, 1632,
-	1633, 1634, 1635, 1636, 1047, 1059, 1510, 1638,
-	1639, 1640, 1641, 1642, 1643, 1644, 1645, 1646,
-	1647, 1648, 1644, 1650, 1651, 1652, 1653, 1637,
-	1649, 1637, 1510, 1637, 1510,
+	1059, 1047, 1045, 1046, 1045, 795, 1660, 1661,
+	1661, 1659, 1658, 1662, 1662, 1659, 1658, 1659,
+	1658, 1665, 5, 1668, 5, 1142, 1143, 1144,
+	1142, 1145, 1146, 1147, 1149, 1150, 1151, 1663,
+	1152, 1153, 1154, 1654, 670, 670, 419, 1155,
+	1156, 1157, 1158, 670, 1161, 1162, 1164, 1165,
+	1166, 1160, 1167, 1168, 1169, 1170, 1171, 1172,
+	1173, 1174, 1175, 1176, 1177, 1178, 1179, 1180,
+	1181, 1182, 1183, 1184, 1185, 1186, 1188, 1189,
+	1190, 1191, 1192, 1193, 670, 1148, 7, 1148,
+	419, 1148, 419, 1160, 1163, 1187, 1194, 1159,
+	1142, 1142, 1195, 1143, 1196, 1198, 1197, 4,
+	1147, 1200, 1197, 1201, 1659, 1197, 2, 1147,
+	1197, 6, 8, 7, 8, 7, 1202, 1203,
+	1204, 1197, 1205, 1206, 1197, 1207, 1197, 419,
+	419, 1209, 1210, 489, 470, 1211, 470, 1212,
+	1213, 1214, 1215, 1216, 1217, 1218, 1219, 1220,
+	1221, 1222, 544, 1223, 520, 1224, 1225, 1226,
+	1227, 1228, 1229, 1230, 1231, 1232, 1233, 1234,
+	1235, 419, 419, 419, 425, 565, 1208, 1236,
+	1197, 1237, 1197, 670, 1238, 419, 419, 419,
+	670, 1238, 670, 670, 419, 1238, 419, 1238,
+	419, 1238, 419, 670, 670, 670, 670, 670,
+	1238, 419, 670, 670, 670, 419, 670, 419,
+	1238, 419, 670, 670, 670, 670, 419, 1238,
+	670, 419, 670, 419, 670, 419, 670, 670,
+	419, 670, 1238, 419, 670, 419, 670, 419,
+	670, 1238, 670, 419, 1238, 670, 419, 670,
+	419, 1238, 670, 670, 670, 670, 670, 1238,
+	419, 419, 670, 419, 670, 1238, 670, 419,
+	1238, 670, 670, 1238, 419, 419, 670, 419,
+	670, 419, 670, 1238, 1239, 1240, 1241, 1242,
+	1243, 1244, 1245, 1246, 1247, 1248, 1249, 715,
+	1250, 1251, 1252, 1253, 1254, 1255, 1256, 1257,
+	1258, 1259, 1260, 1261, 1260, 1262, 1263, 1264,
+	1265, 1266, 671, 1238, 1267, 1268, 1269, 1270,
+	1271, 1272, 1273, 1274, 1275, 1276, 1277, 1278,
+	1279, 1280, 1281, 1282, 1283, 1284, 1285, 725,
+	1286, 1287, 1288, 692, 1289, 1290, 1291, 1292,
+	1293, 1294, 671, 1295, 1296, 1297, 1298, 1299,
+	1300, 1301, 1302, 674, 1303, 671, 674, 1304,
+	1305, 1306, 1307, 683, 1238, 1308, 1309, 1310,
+	1311, 703, 1312, 1313, 683, 1314, 1315, 1316,
+	1317, 1318, 671, 1238, 1319, 1278, 1320, 1321,
+	1322, 683, 1323, 1324, 674, 671, 683, 425,
+	1238, 1288, 671, 674, 683, 425, 683, 425,
+	1325, 683, 1238, 425, 674, 1326, 1327, 674,
+	1328, 1329, 681, 1330, 1331, 1332, 1333, 1334,
+	1284, 1335, 1336, 1337, 1338, 1339, 1340, 1341,
+	1342, 1343, 1344, 1345, 1346, 1303, 1347, 674,
+	683, 425, 1238, 1348, 1349, 683, 671, 1238,
+	425, 671, 1238, 674, 1350, 731, 1351, 1352,
+	1353, 1354, 1355, 1356, 1357, 1358, 671, 1359,
+	1360, 1361, 1362, 1363, 1364, 671, 683, 1238,
+	1366, 1367, 1368, 1369, 1370, 1371, 1372, 1373,
+	1374, 1375, 1376, 1372, 1378, 1379, 1380, 1381,
+	1365, 1377, 1365, 1238, 1365, 1238, 1382, 1382,
+	1383, 1384, 1385, 1386, 1387, 1388, 1389, 1390,
+	1387, 767, 1391, 1391, 1391, 1392, 1391, 1391,
+	768, 769, 770, 1391, 767, 1382, 1382, 1393,
+	1396, 1397, 1395, 1398, 1399, 1398, 1400, 1391,
+	1402, 1401, 1396, 1403, 1395, 1405, 1404, 1394,
+	1394, 1394, 768, 769, 770, 1394, 767, 767,
+	1406, 773, 1406, 1407, 1406, 775, 1408, 1409,
+	1410, 1411, 1412, 1413, 1414, 1411, 776, 775,
+	1408, 1415, 1415, 777, 779, 1416, 1415, 776,
+	1418, 1419, 1417, 1418, 1419, 1420, 1417, 775,
+	1408, 1421, 1415, 775, 1408, 1415, 1423, 1422,
+	1425, 1424, 776, 1426, 777, 1426, 779, 1426,
+	785, 1427, 1428, 1429, 1430, 1431, 1432, 1433,
+	1430, 786, 785, 1427, 1434, 1434, 787, 789,
+	1435, 1434, 786, 1437, 1438, 1436, 1437, 1438,
+	1439, 1436, 785, 1427, 1440, 1434, 785, 1427,
+	1434, 1442, 1441, 1444, 1443, 786, 1445, 787,
+	1445, 789, 1445, 795, 1448, 1449, 1451, 1452,
+	1453, 1447, 1454, 1455, 1456, 1457, 1458, 1459,
+	1460, 1461, 1462, 1463, 1464, 1465, 1466, 1467,
+	1468, 1469, 1470, 1471, 1472, 1473, 1475, 1476,
+	1477, 1478, 1479, 1480, 795, 795, 1446, 1447,
+	1450, 1474, 1481, 1446, 1046, 795, 795, 1483,
+	1484, 865, 846, 1485, 846, 1486, 1487, 1488,
+	1489, 1490, 1491, 1492, 1493, 1494, 1495, 1496,
+	920, 1497, 896, 1498, 1499, 1500, 1501, 1502,
+	1503, 1504, 1505, 1506, 1507, 1508, 1509, 795,
+	795, 795, 801, 941, 1482, 1046, 1510, 795,
+	795, 795, 1046, 1510, 1046, 1046, 795, 1510,
+	795, 1510, 795, 1510, 795, 1046, 1046, 1046,
+	1046, 1046, 1510, 795, 1046, 1046, 1046, 795,
+	1046, 795, 1510, 795, 1046, 1046, 1046, 1046,
+	795, 1510, 1046, 795, 1046, 795, 1046, 795,
+	1046, 1046, 795, 1046, 1510, 795, 1046, 795,
+	1046, 795, 1046, 1510, 1046, 795, 1510, 1046,
+	795, 1046, 795, 1510, 1046, 1046, 1046, 1046,
+	1046, 1510, 795, 795, 1046, 795, 1046, 1510,
+	1046, 795, 1510, 1046, 1046, 1510, 795, 795,
+	1046, 795, 1046, 795, 1046, 1510, 1511, 1512,
+	1513, 1514, 1515, 1516, 1517, 1518, 1519, 1520,
+	1521, 1091, 1522, 1523, 1524, 1525, 1526, 1527,
+	1528, 1529, 1530, 1531, 1532, 1533, 1532, 1534,
+	1535, 1536, 1537, 1538, 1047, 1510, 1539, 1540,
+	1541, 1542, 1543, 1544, 1545, 1546, 1547, 1548,
+	1549, 1550, 1551, 1552, 1553, 1554, 1555, 1556,
+	1557, 1101, 1558, 1559, 1560, 1068, 1561, 1562,
+	1563, 1564, 1565, 1566, 1047, 1567, 1568, 1569,
+	1570, 1571, 1572, 1573, 1574, 1050, 1575, 1047,
+	1050, 1576, 1577, 1578, 1579, 1059, 1510, 1580,
+	1581, 1582, 1583, 1079, 1584, 1585, 1059, 1586,
+	1587, 1588, 1589, 1590, 1047, 1510, 1591, 1550,
+	1592, 1593, 1594, 1059, 1595, 1596, 1050, 1047,
+	1059, 801, 1510, 1560, 1047, 1050, 1059, 801,
+	1059, 801, 1597, 1059, 1510, 801, 1050, 1598,
+	1599, 1050, 1600, 1601, 1057, 1602, 1603, 1604,
+	1605, 1606, 1556, 1607, 1608, 1609, 1610, 1611,
+	1612, 1613, 1614, 1615, 1616, 1617, 1618, 1575,
+	1619, 1050, 1059, 801, 1510, 1620, 1621, 1059,
+	1047, 1510, 801, 1047, 1510, 1050, 1622, 1107,
+	1623, 1624, 1625, 1626, 1627, 1628, 1629, 1630,
+	1047, 1631, 1632, 1633, 1634, 1635, 1636, 1047,
+	1059, 1510, 1638, 1639, 1640, 1641, 1642, 1643,
+	1644, 1645, 1646, 1647, 1648, 1644, 1650, 1651,
+	1652, 1653, 1637, 1649, 1637, 1510, 1637, 1510,
+	1656, 1655, 1197, 1660, 1661, 1661, 1659, 1657,
+	6, 7, 1667, 8, 1667, 1664, 7, 1667,
+	8, 1667, 1664, 7, 1202, 1665, 1665, 1665,
+	1665, 1202, 1666, 1664, 1664, 1664, 1664, 1202,
+	1668, 1668, 1202, 1669, 1667, 1667, 1202,
 }
 
 var _hcltok_trans_targs []int16 = []int16{
-	1459, 1459, 2, 3, 1459, 1459, 4, 1467,
+	1464, 1464, 2, 3, 1464, 1464, 4, 1472,
 	5, 6, 8, 9, 286, 12, 13, 14,
 	15, 16, 287, 288, 19, 289, 21, 22,
 	290, 291, 292, 293, 294, 295, 296, 297,
 	298, 299, 328, 348, 353, 127, 128, 129,
-	356, 151, 371, 375, 1459, 10, 11, 17,
+	356, 151, 371, 375, 1464, 10, 11, 17,
 	18, 20, 23, 24, 25, 26, 27, 28,
 	29, 30, 31, 32, 64, 105, 120, 131,
 	154, 170, 283, 33, 34, 35, 36, 37,
@@ -3235,7 +3259,7 @@ var _hcltok_trans_targs []int16 = []int16{
 	385, 386, 387, 388, 389, 390, 391, 392,
 	393, 394, 395, 396, 397, 398, 399, 400,
 	401, 402, 403, 405, 406, 407, 408, 410,
-	412, 414, 1459, 1471, 1459, 437, 438, 439,
+	412, 414, 1464, 1476, 1464, 437, 438, 439,
 	440, 417, 441, 442, 443, 444, 445, 446,
 	447, 448, 449, 450, 451, 452, 453, 454,
 	455, 456, 457, 458, 459, 460, 461, 462,
@@ -3266,7 +3290,7 @@ var _hcltok_trans_targs []int16 = []int16{
 	655, 656, 657, 658, 659, 660, 661, 662,
 	663, 664, 665, 666, 667, 668, 669, 670,
 	671, 673, 674, 675, 676, 677, 678, 680,
-	682, 684, 686, 688, 689, 1459, 1459, 690,
+	682, 684, 686, 688, 689, 1464, 1464, 690,
 	827, 828, 759, 829, 830, 831, 832, 833,
 	834, 788, 835, 724, 836, 837, 838, 839,
 	840, 841, 842, 843, 744, 844, 845, 846,
@@ -3278,11 +3302,11 @@ var _hcltok_trans_targs []int16 = []int16{
 	888, 889, 890, 891, 892, 895, 896, 898,
 	899, 900, 902, 903, 904, 905, 906, 907,
 	908, 909, 910, 911, 912, 914, 915, 916,
-	917, 920, 922, 923, 925, 927, 1509, 1510,
-	929, 930, 931, 1509, 1509, 932, 1523, 1523,
-	1524, 935, 1523, 936, 1525, 1526, 1529, 1530,
-	1534, 1534, 1535, 941, 1534, 942, 1536, 1537,
-	1540, 1541, 1545, 1546, 1545, 968, 969, 970,
+	917, 920, 922, 923, 925, 927, 1514, 1515,
+	929, 930, 931, 1514, 1514, 932, 1528, 1528,
+	1529, 935, 1528, 936, 1530, 1531, 1534, 1535,
+	1539, 1539, 1540, 941, 1539, 942, 1541, 1542,
+	1545, 1546, 1550, 1551, 1550, 968, 969, 970,
 	971, 948, 972, 973, 974, 975, 976, 977,
 	978, 979, 980, 981, 982, 983, 984, 985,
 	986, 987, 988, 989, 990, 991, 992, 993,
@@ -3313,7 +3337,7 @@ var _hcltok_trans_targs []int16 = []int16{
 	1186, 1187, 1188, 1189, 1190, 1191, 1192, 1193,
 	1194, 1195, 1196, 1197, 1198, 1199, 1200, 1201,
 	1202, 1204, 1205, 1206, 1207, 1208, 1209, 1211,
-	1213, 1215, 1217, 1219, 1220, 1545, 1545, 1221,
+	1213, 1215, 1217, 1219, 1220, 1550, 1550, 1221,
 	1358, 1359, 1290, 1360, 1361, 1362, 1363, 1364,
 	1365, 1319, 1366, 1255, 1367, 1368, 1369, 1370,
 	1371, 1372, 1373, 1374, 1275, 1375, 1376, 1377,
@@ -3325,19 +3349,19 @@ var _hcltok_trans_targs []int16 = []int16{
 	1419, 1420, 1421, 1422, 1423, 1426, 1427, 1429,
 	1430, 1431, 1433, 1434, 1435, 1436, 1437, 1438,
 	1439, 1440, 1441, 1442, 1443, 1445, 1446, 1447,
-	1448, 1451, 1453, 1454, 1456, 1458, 1460, 1459,
-	1461, 1462, 1459, 1463, 1459, 1464, 1465, 1466,
-	1468, 1469, 1470, 1459, 1472, 1459, 1473, 1459,
-	1474, 1475, 1476, 1477, 1478, 1479, 1480, 1481,
-	1482, 1483, 1484, 1485, 1486, 1487, 1488, 1489,
-	1490, 1491, 1492, 1493, 1494, 1495, 1496, 1497,
-	1498, 1499, 1500, 1501, 1502, 1503, 1504, 1505,
-	1506, 1507, 1508, 1459, 1459, 1459, 1459, 1459,
-	1459, 1, 1459, 7, 1459, 1459, 1459, 1459,
-	1459, 415, 416, 420, 421, 422, 423, 424,
+	1448, 1451, 1453, 1454, 1456, 1458, 1465, 1464,
+	1466, 1467, 1464, 1468, 1464, 1469, 1470, 1471,
+	1473, 1474, 1475, 1464, 1477, 1464, 1478, 1464,
+	1479, 1480, 1481, 1482, 1483, 1484, 1485, 1486,
+	1487, 1488, 1489, 1490, 1491, 1492, 1493, 1494,
+	1495, 1496, 1497, 1498, 1499, 1500, 1501, 1502,
+	1503, 1504, 1505, 1506, 1507, 1508, 1509, 1510,
+	1511, 1512, 1513, 1464, 1464, 1464, 1464, 1464,
+	1464, 1, 1464, 7, 1464, 1464, 1464, 1464,
+	1464, 415, 416, 420, 421, 422, 423, 424,
 	425, 426, 427, 428, 429, 430, 431, 433,
 	435, 436, 468, 509, 524, 531, 533, 535,
-	555, 558, 574, 687, 1459, 1459, 1459, 691,
+	555, 558, 574, 687, 1464, 1464, 1464, 691,
 	692, 693, 694, 695, 696, 697, 698, 699,
 	700, 701, 703, 704, 705, 706, 707, 708,
 	709, 710, 711, 712, 713, 714, 715, 716,
@@ -3355,23 +3379,23 @@ var _hcltok_trans_targs []int16 = []int16{
 	812, 813, 814, 815, 816, 817, 818, 819,
 	820, 821, 822, 823, 824, 825, 826, 855,
 	880, 883, 884, 886, 893, 894, 897, 901,
-	913, 918, 919, 921, 924, 926, 1511, 1509,
-	1512, 1517, 1519, 1509, 1520, 1521, 1522, 1509,
-	928, 1509, 1509, 1513, 1514, 1516, 1509, 1515,
-	1509, 1509, 1509, 1518, 1509, 1509, 1509, 933,
-	934, 938, 939, 1523, 1531, 1532, 1533, 1523,
-	937, 1523, 1523, 934, 1527, 1528, 1523, 1523,
-	1523, 1523, 1523, 940, 944, 945, 1534, 1542,
-	1543, 1544, 1534, 943, 1534, 1534, 940, 1538,
-	1539, 1534, 1534, 1534, 1534, 1534, 1545, 1547,
-	1548, 1549, 1550, 1551, 1552, 1553, 1554, 1555,
-	1556, 1557, 1558, 1559, 1560, 1561, 1562, 1563,
-	1564, 1565, 1566, 1567, 1568, 1569, 1570, 1571,
-	1572, 1573, 1574, 1575, 1576, 1577, 1578, 1579,
-	1580, 1581, 1545, 946, 947, 951, 952, 953,
+	913, 918, 919, 921, 924, 926, 1516, 1514,
+	1517, 1522, 1524, 1514, 1525, 1526, 1527, 1514,
+	928, 1514, 1514, 1518, 1519, 1521, 1514, 1520,
+	1514, 1514, 1514, 1523, 1514, 1514, 1514, 933,
+	934, 938, 939, 1528, 1536, 1537, 1538, 1528,
+	937, 1528, 1528, 934, 1532, 1533, 1528, 1528,
+	1528, 1528, 1528, 940, 944, 945, 1539, 1547,
+	1548, 1549, 1539, 943, 1539, 1539, 940, 1543,
+	1544, 1539, 1539, 1539, 1539, 1539, 1550, 1552,
+	1553, 1554, 1555, 1556, 1557, 1558, 1559, 1560,
+	1561, 1562, 1563, 1564, 1565, 1566, 1567, 1568,
+	1569, 1570, 1571, 1572, 1573, 1574, 1575, 1576,
+	1577, 1578, 1579, 1580, 1581, 1582, 1583, 1584,
+	1585, 1586, 1550, 946, 947, 951, 952, 953,
 	954, 955, 956, 957, 958, 959, 960, 961,
 	962, 964, 966, 967, 999, 1040, 1055, 1062,
-	1064, 1066, 1086, 1089, 1105, 1218, 1545, 1222,
+	1064, 1066, 1086, 1089, 1105, 1218, 1550, 1222,
 	1223, 1224, 1225, 1226, 1227, 1228, 1229, 1230,
 	1231, 1232, 1234, 1235, 1236, 1237, 1238, 1239,
 	1240, 1241, 1242, 1243, 1244, 1245, 1246, 1247,
@@ -3389,7 +3413,9 @@ var _hcltok_trans_targs []int16 = []int16{
 	1343, 1344, 1345, 1346, 1347, 1348, 1349, 1350,
 	1351, 1352, 1353, 1354, 1355, 1356, 1357, 1386,
 	1411, 1414, 1415, 1417, 1424, 1425, 1428, 1432,
-	1444, 1449, 1450, 1452, 1455, 1457,
+	1444, 1449, 1450, 1452, 1455, 1457, 1587, 1464,
+	1464, 1464, 1464, 1588, 1459, 1460, 1461, 1589,
+	1591, 1590, 1462, 1593, 1592, 1463,
 }
 
 var _hcltok_trans_actions []byte = []byte{
@@ -3599,7 +3625,9 @@ var _hcltok_trans_actions []byte = []byte{
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 199,
+	201, 203, 205, 7, 0, 0, 0, 7,
+	7, 7, 0, 7, 7, 0,
 }
 
 var _hcltok_to_state_actions []byte = []byte{
@@ -3785,22 +3813,24 @@ var _hcltok_to_state_actions []byte = []byte{
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 3, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0,
+	3, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 3, 0, 0,
+	0, 0, 3, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
+	166, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 166, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 166, 0,
+	0, 0, 0, 0, 0, 0, 3, 0,
+	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
-	0, 3, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0,
+	0, 0,
 }
 
 var _hcltok_from_state_actions []byte = []byte{
@@ -3986,22 +4016,24 @@ var _hcltok_from_state_actions []byte = []byte{
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 5, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0,
+	5, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 5, 0, 0,
+	0, 0, 5, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
+	5, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 5, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 5, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
-	0, 5, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0,
 }
 
 var _hcltok_eof_trans []int16 = []int16{
@@ -4187,33 +4219,35 @@ var _hcltok_eof_trans []int16 = []int16{
 	1046, 1046, 1046, 1046, 1046, 1046, 1046, 1046,
 	1046, 1046, 1046, 1046, 1046, 1046, 1046, 1046,
 	1046, 1046, 1046, 1046, 1046, 1046, 1046, 1046,
-	1046, 1046, 1046, 0, 1196, 1197, 1198, 1200,
-	1198, 1198, 1198, 1203, 1198, 1198, 1198, 1209,
-	1198, 1198, 1239, 1239, 1239, 1239, 1239, 1239,
+	1046, 1046, 1046, 1659, 1659, 1659, 6, 6,
+	0, 1196, 1197, 1198, 1200, 1198, 1198, 1198,
+	1203, 1198, 1198, 1198, 1209, 1198, 1198, 1239,
 	1239, 1239, 1239, 1239, 1239, 1239, 1239, 1239,
 	1239, 1239, 1239, 1239, 1239, 1239, 1239, 1239,
 	1239, 1239, 1239, 1239, 1239, 1239, 1239, 1239,
-	1239, 1239, 1239, 1239, 1239, 0, 1392, 1394,
-	1395, 1399, 1399, 1392, 1402, 1395, 1405, 1395,
-	1407, 1407, 1407, 0, 1416, 1418, 1418, 1416,
-	1416, 1423, 1425, 1427, 1427, 1427, 0, 1435,
-	1437, 1437, 1435, 1435, 1442, 1444, 1446, 1446,
-	1446, 0, 1483, 1511, 1511, 1511, 1511, 1511,
+	1239, 1239, 1239, 1239, 1239, 1239, 1239, 1239,
+	1239, 1239, 0, 1392, 1394, 1395, 1399, 1399,
+	1392, 1402, 1395, 1405, 1395, 1407, 1407, 1407,
+	0, 1416, 1418, 1418, 1416, 1416, 1423, 1425,
+	1427, 1427, 1427, 0, 1435, 1437, 1437, 1435,
+	1435, 1442, 1444, 1446, 1446, 1446, 0, 1483,
+	1511, 1511, 1511, 1511, 1511, 1511, 1511, 1511,
 	1511, 1511, 1511, 1511, 1511, 1511, 1511, 1511,
 	1511, 1511, 1511, 1511, 1511, 1511, 1511, 1511,
 	1511, 1511, 1511, 1511, 1511, 1511, 1511, 1511,
-	1511, 1511, 1511, 1511, 1511, 1511,
+	1511, 1511, 1511, 1198, 1658, 1203, 1203, 1203,
+	1203, 1203,
 }
 
-const hcltok_start int = 1459
-const hcltok_first_final int = 1459
+const hcltok_start int = 1464
+const hcltok_first_final int = 1464
 const hcltok_error int = 0
 
-const hcltok_en_stringTemplate int = 1509
-const hcltok_en_heredocTemplate int = 1523
-const hcltok_en_bareTemplate int = 1534
-const hcltok_en_identOnly int = 1545
-const hcltok_en_main int = 1459
+const hcltok_en_stringTemplate int = 1514
+const hcltok_en_heredocTemplate int = 1528
+const hcltok_en_bareTemplate int = 1539
+const hcltok_en_identOnly int = 1550
+const hcltok_en_main int = 1464
 
 //line scan_tokens.rl:16
 
@@ -4229,7 +4263,7 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 		StartByte: start.Byte,
 	}
 
-//line scan_tokens.rl:305
+//line scan_tokens.rl:338
 
 	// Ragel state
 	p := 0          // "Pointer" into data
@@ -4257,7 +4291,7 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 	var retBraces []int              // stack of brace levels that cause us to use fret
 	var heredocs []heredocInProgress // stack of heredocs we're currently processing
 
-//line scan_tokens.rl:340
+//line scan_tokens.rl:373
 
 	// Make Go compiler happy
 	_ = ts
@@ -4277,7 +4311,7 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 		f.emitToken(TokenType(b[0]), ts, te)
 	}
 
-//line scan_tokens.go:4289
+//line scan_tokens.go:4315
 	{
 		top = 0
 		ts = 0
@@ -4285,7 +4319,7 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 		act = 0
 	}
 
-//line scan_tokens.go:4297
+//line scan_tokens.go:4323
 	{
 		var _klen int
 		var _trans int
@@ -4309,7 +4343,7 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 //line NONE:1
 				ts = p
 
-//line scan_tokens.go:4320
+//line scan_tokens.go:4347
 			}
 		}
 
@@ -4381,7 +4415,7 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 			_acts++
 			switch _hcltok_actions[_acts-1] {
 			case 0:
-//line scan_tokens.rl:224
+//line scan_tokens.rl:254
 				p--
 
 			case 4:
@@ -4389,13 +4423,13 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 				te = p + 1
 
 			case 5:
-//line scan_tokens.rl:248
+//line scan_tokens.rl:278
 				act = 4
 			case 6:
-//line scan_tokens.rl:250
+//line scan_tokens.rl:280
 				act = 6
 			case 7:
-//line scan_tokens.rl:160
+//line scan_tokens.rl:190
 				te = p + 1
 				{
 					token(TokenTemplateInterp)
@@ -4408,12 +4442,12 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 8:
-//line scan_tokens.rl:170
+//line scan_tokens.rl:200
 				te = p + 1
 				{
 					token(TokenTemplateControl)
@@ -4426,12 +4460,12 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 9:
-//line scan_tokens.rl:84
+//line scan_tokens.rl:114
 				te = p + 1
 				{
 					token(TokenCQuote)
@@ -4444,19 +4478,19 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 
 				}
 			case 10:
-//line scan_tokens.rl:248
+//line scan_tokens.rl:278
 				te = p + 1
 				{
 					token(TokenQuotedLit)
 				}
 			case 11:
-//line scan_tokens.rl:251
+//line scan_tokens.rl:281
 				te = p + 1
 				{
 					token(TokenBadUTF8)
 				}
 			case 12:
-//line scan_tokens.rl:160
+//line scan_tokens.rl:190
 				te = p
 				p--
 				{
@@ -4470,12 +4504,12 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 13:
-//line scan_tokens.rl:170
+//line scan_tokens.rl:200
 				te = p
 				p--
 				{
@@ -4489,46 +4523,46 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 14:
-//line scan_tokens.rl:248
+//line scan_tokens.rl:278
 				te = p
 				p--
 				{
 					token(TokenQuotedLit)
 				}
 			case 15:
-//line scan_tokens.rl:249
+//line scan_tokens.rl:279
 				te = p
 				p--
 				{
 					token(TokenQuotedNewline)
 				}
 			case 16:
-//line scan_tokens.rl:250
+//line scan_tokens.rl:280
 				te = p
 				p--
 				{
 					token(TokenInvalid)
 				}
 			case 17:
-//line scan_tokens.rl:251
+//line scan_tokens.rl:281
 				te = p
 				p--
 				{
 					token(TokenBadUTF8)
 				}
 			case 18:
-//line scan_tokens.rl:248
+//line scan_tokens.rl:278
 				p = (te) - 1
 				{
 					token(TokenQuotedLit)
 				}
 			case 19:
-//line scan_tokens.rl:251
+//line scan_tokens.rl:281
 				p = (te) - 1
 				{
 					token(TokenBadUTF8)
@@ -4549,13 +4583,13 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 				}
 
 			case 21:
-//line scan_tokens.rl:148
+//line scan_tokens.rl:178
 				act = 11
 			case 22:
-//line scan_tokens.rl:259
+//line scan_tokens.rl:289
 				act = 12
 			case 23:
-//line scan_tokens.rl:160
+//line scan_tokens.rl:190
 				te = p + 1
 				{
 					token(TokenTemplateInterp)
@@ -4568,12 +4602,12 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 24:
-//line scan_tokens.rl:170
+//line scan_tokens.rl:200
 				te = p + 1
 				{
 					token(TokenTemplateControl)
@@ -4586,12 +4620,12 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 25:
-//line scan_tokens.rl:111
+//line scan_tokens.rl:141
 				te = p + 1
 				{
 					// This action is called specificially when a heredoc literal
@@ -4636,13 +4670,13 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 					token(TokenStringLit)
 				}
 			case 26:
-//line scan_tokens.rl:259
+//line scan_tokens.rl:289
 				te = p + 1
 				{
 					token(TokenBadUTF8)
 				}
 			case 27:
-//line scan_tokens.rl:160
+//line scan_tokens.rl:190
 				te = p
 				p--
 				{
@@ -4656,12 +4690,12 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 28:
-//line scan_tokens.rl:170
+//line scan_tokens.rl:200
 				te = p
 				p--
 				{
@@ -4675,12 +4709,12 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 29:
-//line scan_tokens.rl:148
+//line scan_tokens.rl:178
 				te = p
 				p--
 				{
@@ -4691,14 +4725,14 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 					token(TokenStringLit)
 				}
 			case 30:
-//line scan_tokens.rl:259
+//line scan_tokens.rl:289
 				te = p
 				p--
 				{
 					token(TokenBadUTF8)
 				}
 			case 31:
-//line scan_tokens.rl:148
+//line scan_tokens.rl:178
 				p = (te) - 1
 				{
 					// This action is called when a heredoc literal _doesn't_ end
@@ -4733,13 +4767,13 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 				}
 
 			case 33:
-//line scan_tokens.rl:156
+//line scan_tokens.rl:186
 				act = 15
 			case 34:
-//line scan_tokens.rl:266
+//line scan_tokens.rl:296
 				act = 16
 			case 35:
-//line scan_tokens.rl:160
+//line scan_tokens.rl:190
 				te = p + 1
 				{
 					token(TokenTemplateInterp)
@@ -4752,12 +4786,12 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 36:
-//line scan_tokens.rl:170
+//line scan_tokens.rl:200
 				te = p + 1
 				{
 					token(TokenTemplateControl)
@@ -4770,24 +4804,24 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 37:
-//line scan_tokens.rl:156
+//line scan_tokens.rl:186
 				te = p + 1
 				{
 					token(TokenStringLit)
 				}
 			case 38:
-//line scan_tokens.rl:266
+//line scan_tokens.rl:296
 				te = p + 1
 				{
 					token(TokenBadUTF8)
 				}
 			case 39:
-//line scan_tokens.rl:160
+//line scan_tokens.rl:190
 				te = p
 				p--
 				{
@@ -4801,12 +4835,12 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 40:
-//line scan_tokens.rl:170
+//line scan_tokens.rl:200
 				te = p
 				p--
 				{
@@ -4820,26 +4854,26 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1459
+						cs = 1464
 						goto _again
 					}
 				}
 			case 41:
-//line scan_tokens.rl:156
+//line scan_tokens.rl:186
 				te = p
 				p--
 				{
 					token(TokenStringLit)
 				}
 			case 42:
-//line scan_tokens.rl:266
+//line scan_tokens.rl:296
 				te = p
 				p--
 				{
 					token(TokenBadUTF8)
 				}
 			case 43:
-//line scan_tokens.rl:156
+//line scan_tokens.rl:186
 				p = (te) - 1
 				{
 					token(TokenStringLit)
@@ -4866,45 +4900,45 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 				}
 
 			case 45:
-//line scan_tokens.rl:270
+//line scan_tokens.rl:300
 				act = 17
 			case 46:
-//line scan_tokens.rl:271
+//line scan_tokens.rl:301
 				act = 18
 			case 47:
-//line scan_tokens.rl:271
+//line scan_tokens.rl:301
 				te = p + 1
 				{
 					token(TokenBadUTF8)
 				}
 			case 48:
-//line scan_tokens.rl:272
+//line scan_tokens.rl:302
 				te = p + 1
 				{
 					token(TokenInvalid)
 				}
 			case 49:
-//line scan_tokens.rl:270
+//line scan_tokens.rl:300
 				te = p
 				p--
 				{
 					token(TokenIdent)
 				}
 			case 50:
-//line scan_tokens.rl:271
+//line scan_tokens.rl:301
 				te = p
 				p--
 				{
 					token(TokenBadUTF8)
 				}
 			case 51:
-//line scan_tokens.rl:270
+//line scan_tokens.rl:300
 				p = (te) - 1
 				{
 					token(TokenIdent)
 				}
 			case 52:
-//line scan_tokens.rl:271
+//line scan_tokens.rl:301
 				p = (te) - 1
 				{
 					token(TokenBadUTF8)
@@ -4925,86 +4959,98 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 				}
 
 			case 54:
-//line scan_tokens.rl:278
-				act = 22
+//line scan_tokens.rl:309
+				act = 23
 			case 55:
-//line scan_tokens.rl:301
-				act = 39
+//line scan_tokens.rl:334
+				act = 42
 			case 56:
-//line scan_tokens.rl:280
+//line scan_tokens.rl:311
 				te = p + 1
 				{
 					token(TokenComment)
 				}
 			case 57:
-//line scan_tokens.rl:281
+//line scan_tokens.rl:312
 				te = p + 1
 				{
 					token(TokenNewline)
 				}
 			case 58:
-//line scan_tokens.rl:283
+//line scan_tokens.rl:314
 				te = p + 1
 				{
 					token(TokenEqualOp)
 				}
 			case 59:
-//line scan_tokens.rl:284
+//line scan_tokens.rl:315
 				te = p + 1
 				{
 					token(TokenNotEqual)
 				}
 			case 60:
-//line scan_tokens.rl:285
+//line scan_tokens.rl:316
 				te = p + 1
 				{
 					token(TokenGreaterThanEq)
 				}
 			case 61:
-//line scan_tokens.rl:286
+//line scan_tokens.rl:317
 				te = p + 1
 				{
 					token(TokenLessThanEq)
 				}
 			case 62:
-//line scan_tokens.rl:287
+//line scan_tokens.rl:318
 				te = p + 1
 				{
 					token(TokenAnd)
 				}
 			case 63:
-//line scan_tokens.rl:288
+//line scan_tokens.rl:319
 				te = p + 1
 				{
 					token(TokenOr)
 				}
 			case 64:
-//line scan_tokens.rl:289
+//line scan_tokens.rl:320
 				te = p + 1
 				{
 					token(TokenEllipsis)
 				}
 			case 65:
-//line scan_tokens.rl:290
+//line scan_tokens.rl:321
 				te = p + 1
 				{
 					token(TokenFatArrow)
 				}
 			case 66:
-//line scan_tokens.rl:291
+//line scan_tokens.rl:322
 				te = p + 1
 				{
-					selfToken()
+					token(TokenDoubleQuestion)
 				}
 			case 67:
-//line scan_tokens.rl:180
+//line scan_tokens.rl:323
+				te = p + 1
+				{
+					token(TokenQuestionDot)
+				}
+			case 68:
+//line scan_tokens.rl:324
+				te = p + 1
+				{
+					selfToken()
+				}
+			case 69:
+//line scan_tokens.rl:210
 				te = p + 1
 				{
 					token(TokenOBrace)
 					braces++
 				}
-			case 68:
-//line scan_tokens.rl:185
+			case 70:
+//line scan_tokens.rl:215
 				te = p + 1
 				{
 					if len(retBraces) > 0 && retBraces[len(retBraces)-1] == braces {
@@ -5023,8 +5069,8 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						braces--
 					}
 				}
-			case 69:
-//line scan_tokens.rl:197
+			case 71:
+//line scan_tokens.rl:227
 				te = p + 1
 				{
 					// Only consume from the retBraces stack and return if we are at
@@ -5052,8 +5098,8 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						braces--
 					}
 				}
-			case 70:
-//line scan_tokens.rl:79
+			case 72:
+//line scan_tokens.rl:109
 				te = p + 1
 				{
 					token(TokenOQuote)
@@ -5061,12 +5107,12 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1509
+						cs = 1514
 						goto _again
 					}
 				}
-			case 71:
-//line scan_tokens.rl:89
+			case 73:
+//line scan_tokens.rl:119
 				te = p + 1
 				{
 					token(TokenOHeredoc)
@@ -5091,109 +5137,124 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 						stack = append(stack, 0)
 						stack[top] = cs
 						top++
-						cs = 1523
+						cs = 1528
 						goto _again
 					}
 				}
-			case 72:
-//line scan_tokens.rl:301
+			case 74:
+//line scan_tokens.rl:334
 				te = p + 1
 				{
 					token(TokenBadUTF8)
 				}
-			case 73:
-//line scan_tokens.rl:302
+			case 75:
+//line scan_tokens.rl:335
 				te = p + 1
 				{
 					token(TokenInvalid)
 				}
-			case 74:
-//line scan_tokens.rl:276
+			case 76:
+//line scan_tokens.rl:306
 				te = p
 				p--
 
-			case 75:
-//line scan_tokens.rl:277
+			case 77:
+//line scan_tokens.rl:307
 				te = p
 				p--
 				{
 					token(TokenNumberLit)
 				}
-			case 76:
-//line scan_tokens.rl:278
+			case 78:
+//line scan_tokens.rl:308
+				te = p
+				p--
+				{
+					f.emitToken(TokenDot, ts, ts+1)
+					f.emitToken(TokenNumberLit, ts+1, te)
+				}
+			case 79:
+//line scan_tokens.rl:309
 				te = p
 				p--
 				{
 					token(TokenIdent)
 				}
-			case 77:
-//line scan_tokens.rl:280
+			case 80:
+//line scan_tokens.rl:311
 				te = p
 				p--
 				{
 					token(TokenComment)
 				}
-			case 78:
-//line scan_tokens.rl:291
+			case 81:
+//line scan_tokens.rl:324
 				te = p
 				p--
 				{
 					selfToken()
 				}
-			case 79:
-//line scan_tokens.rl:301
+			case 82:
+//line scan_tokens.rl:334
 				te = p
 				p--
 				{
 					token(TokenBadUTF8)
 				}
-			case 80:
-//line scan_tokens.rl:302
+			case 83:
+//line scan_tokens.rl:335
 				te = p
 				p--
 				{
 					token(TokenInvalid)
 				}
-			case 81:
-//line scan_tokens.rl:277
+			case 84:
+//line scan_tokens.rl:307
 				p = (te) - 1
 				{
 					token(TokenNumberLit)
 				}
-			case 82:
-//line scan_tokens.rl:278
+			case 85:
+//line scan_tokens.rl:308
+				p = (te) - 1
+				{
+					f.emitToken(TokenDot, ts, ts+1)
+					f.emitToken(TokenNumberLit, ts+1, te)
+				}
+			case 86:
+//line scan_tokens.rl:309
 				p = (te) - 1
 				{
 					token(TokenIdent)
 				}
-			case 83:
-//line scan_tokens.rl:291
+			case 87:
+//line scan_tokens.rl:324
 				p = (te) - 1
 				{
 					selfToken()
 				}
-			case 84:
-//line scan_tokens.rl:301
+			case 88:
+//line scan_tokens.rl:334
 				p = (te) - 1
 				{
 					token(TokenBadUTF8)
 				}
-			case 85:
+			case 89:
 //line NONE:1
 				switch act {
-				case 22:
+				case 23:
 					{
 						p = (te) - 1
 						token(TokenIdent)
 					}
-				case 39:
+				case 42:
 					{
 						p = (te) - 1
 						token(TokenBadUTF8)
 					}
 				}
 
-//line scan_tokens.go:5055
+//line scan_tokens.go:5258
 			}
 		}
 
@@ -5212,7 +5273,7 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 //line NONE:1
 				act = 0
 
-//line scan_tokens.go:5073
+//line scan_tokens.go:5277
 			}
 		}
 
@@ -5238,7 +5299,7 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 		}
 	}
 
-//line scan_tokens.rl:363
+//line scan_tokens.rl:396
 
 	// If we fall out here without being in a final state then we've
 	// encountered something that the scanner can't match, which we'll
@@ -39,8 +39,28 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
         );
         BrokenUTF8 = any - AnyUTF8;
 
-        NumberLitContinue = (digit|'.'|('e'|'E') ('+'|'-')? digit);
-        NumberLit = digit ("" | (NumberLitContinue - '.') | (NumberLitContinue* (NumberLitContinue - '.')));
+        # Underscores are accepted anywhere after a digit, and a fractional
+        # part after any base prefix, so that the parser can report precisely
+        # what is wrong with an invalid literal. All decimal digits are
+        # accepted in octal and binary literals for the same reason.
+        NumberLitDigits = digit (digit|'_')*;
+        NumberLitContinue = (NumberLitDigits|'.'|('e'|'E') ('+'|'-')? NumberLitDigits);
+        NumberLitDecimal = NumberLitDigits ("" | (NumberLitContinue* (NumberLitContinue - '.')));
+        NumberLitHexDigits = (xdigit|'_')*;
+        NumberLitBaseDigits = (digit|'_')*;
+        NumberLitBased = '0' (
+            ('x'|'X') NumberLitHexDigits ('.' digit NumberLitHexDigits)? |
+            ('o'|'O'|'b'|'B') NumberLitBaseDigits ('.' digit NumberLitBaseDigits)?
+        );
+        NumberLit = NumberLitDecimal | NumberLitBased;
+
+        # A number immediately after a period is in attribute name position,
+        # as in the legacy index syntax foo.0 or a mistaken attribute name
+        # like centos_7.2_ap-south-1, so it is scanned as a separate period
+        # and a plain decimal number that can't be extended by the other
+        # forms above.
+        LegacyIndexContinue = (digit|'.'|('e'|'E') ('+'|'-')? digit);
+        LegacyIndex = '.' digit ("" | (LegacyIndexContinue* (LegacyIndexContinue - '.')));
         Ident = (ID_Start | '_') (ID_Continue | '-')*;
 
         # Symbols that just represent themselves are handled as a single rule.
@@ -55,6 +75,8 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
 
         Ellipsis = "...";
         FatArrow = "=>";
+        DoubleQuestion = "??";
+        QuestionDot = "?.";
 
         Newline = '\r' ? '\n';
         EndOfLine = Newline;
@@ -283,6 +305,7 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
         main := |*
             Spaces           => {};
             NumberLit        => { token(TokenNumberLit) };
+            LegacyIndex      => { f.emitToken(TokenDot, ts, ts+1); f.emitToken(TokenNumberLit, ts+1, te) };
             Ident            => { token(TokenIdent) };
 
             Comment          => { token(TokenComment) };
@@ -296,6 +319,8 @@ func scanTokens(data []byte, filename string, start hcl.Pos, mode scanMode) []To
             LogicalOr        => { token(TokenOr); };
             Ellipsis         => { token(TokenEllipsis); };
             FatArrow         => { token(TokenFatArrow); };
+            DoubleQuestion   => { token(TokenDoubleQuestion); };
+            QuestionDot      => { token(TokenQuestionDot); };
             SelfToken        => { selfToken() };
 
             "{"              => openBrace;
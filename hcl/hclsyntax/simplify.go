@@ -0,0 +1,322 @@
+package hclsyntax
+
+import (
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// Simplify partially evaluates the given expression using the variables and
+// functions in the given EvalContext, returning a new expression tree where
+// the parts whose values are already known have been replaced with literals.
+//
+// A sub-expression is folded into a LiteralValueExpr when all of its own
+// sub-expressions were folded and it then evaluates to a wholly-known value
+// without errors. Sub-expressions that refer to variables that are not
+// present in the context or whose values are unknown are kept, along with
+// any sub-expressions that fail to evaluate, so that evaluating the result
+// later with a complete EvalContext produces the same result and the same
+// errors as evaluating the original expression. Sub-expressions that refer
+// to sensitive values are kept too, so that their results are still reported
+// as sensitive by hcl.ExprSensitive.
+//
+// Calls to functions are folded only if the function name is included in
+// pureFuncs, indicating that the function always returns the same result for
+// the same arguments. Calls to any other function, such as one that returns
+// the current time or a random identifier, are kept so that they are called
+// again each time the result is evaluated.
+//
+// In addition, a conditional expression whose condition is known is replaced
+// with its selected result expression, and adjacent known parts of a
+// template are merged into a single literal part. When a conditional is
+// pruned in this way its result is no longer converted to a type that would
+// also suit the discarded result, so the type of a pruned conditional's
+// value may differ from that of the original if its two results have
+// different types.
+//
+// The given expression is not modified. The result may share nodes with it
+// where no simplification was possible.
+func Simplify(expr Expression, ctx *hcl.EvalContext, pureFuncs ...string) Expression {
+	s := &simplifier{
+		pure: make(map[string]bool, len(pureFuncs)),
+	}
+	for _, name := range pureFuncs {
+		s.pure[name] = true
+	}
+	return s.simplify(expr, ctx)
+}
+
+type simplifier struct {
+	pure map[string]bool
+}
+
+func (s *simplifier) simplify(expr Expression, ctx *hcl.EvalContext) Expression {
+	// Each case decides whether the result can be folded, which is the case
+	// only if all of its children were folded, so that each node is
+	// evaluated at most once.
+	var ret Expression
+	var fold bool
+	switch e := expr.(type) {
+	case *LiteralValueExpr, *BadExpr, *AnonSymbolExpr:
+		// These have no child expressions, and there's nothing to gain by
+		// evaluating them.
+		return expr
+	case *ScopeTraversalExpr:
+		ret = e
+		fold = true
+	case *RelativeTraversalExpr:
+		ne := *e
+		ne.Source = s.simplify(e.Source, ctx)
+		ret = &ne
+		fold = isLiteral(ne.Source)
+	case *FunctionCallExpr:
+		ne := *e
+		ne.Args = s.simplifyAll(e.Args, ctx)
+		ret = &ne
+		fold = s.pure[e.Name] && allLiteral(ne.Args)
+	case *ConditionalExpr:
+		cond := s.simplify(e.Condition, ctx)
+		if lit, isLit := cond.(*LiteralValueExpr); isLit {
+			if condVal, err := convert.Convert(lit.Val, cty.Bool); err == nil && !condVal.IsNull() {
+				if condVal.True() {
+					return s.simplify(e.TrueResult, ctx)
+				}
+				return s.simplify(e.FalseResult, ctx)
+			}
+		}
+		ne := *e
+		ne.Condition = cond
+		ne.TrueResult = s.simplify(e.TrueResult, ctx)
+		ne.FalseResult = s.simplify(e.FalseResult, ctx)
+		ret = &ne
+		fold = allLiteral([]Expression{ne.Condition, ne.TrueResult, ne.FalseResult})
+	case *IndexExpr:
+		ne := *e
+		ne.Collection = s.simplify(e.Collection, ctx)
+		ne.Key = s.simplify(e.Key, ctx)
+		ret = &ne
+		fold = isLiteral(ne.Collection) && isLiteral(ne.Key)
+	case *TupleConsExpr:
+		ne := *e
+		ne.Exprs = s.simplifyAll(e.Exprs, ctx)
+		ret = &ne
+		fold = allLiteral(ne.Exprs)
+	case *ObjectConsExpr:
+		ne := *e
+		ne.Items = make([]ObjectConsItem, len(e.Items))
+		fold = true
+		for i, item := range e.Items {
+			ne.Items[i] = ObjectConsItem{
+				KeyExpr:   s.simplify(item.KeyExpr, ctx),
+				ValueExpr: s.simplify(item.ValueExpr, ctx),
+			}
+			fold = fold && isLiteral(ne.Items[i].KeyExpr) && isLiteral(ne.Items[i].ValueExpr)
+		}
+		ret = &ne
+	case *ObjectConsKeyExpr:
+		if e.literalName() != "" {
+			return e
+		}
+		wrapped := s.simplify(e.Wrapped, ctx)
+		if hcl.ExprAsKeyword(wrapped) != "" {
+			// If the wrapped expression were to become a literal null, true
+			// or false then it would be taken as a naked identifier, so we
+			// must retain the original expression in that case.
+			return e
+		}
+		return &ObjectConsKeyExpr{Wrapped: wrapped}
+	case *ForExpr:
+		// The key, value and condition expressions are evaluated in a child
+		// scope where the iterator symbols are defined, so we must make sure
+		// they don't resolve to any variables of the same name in ctx.
+		childCtx := ctx.NewChild()
+		childCtx.Variables = map[string]cty.Value{}
+		iterVars := map[string]bool{e.ValVar: true}
+		if e.KeyVar != "" {
+			childCtx.Variables[e.KeyVar] = cty.DynamicVal
+			iterVars[e.KeyVar] = true
+		}
+		childCtx.Variables[e.ValVar] = cty.DynamicVal
+
+		ne := *e
+		ne.CollExpr = s.simplify(e.CollExpr, ctx)
+		fold = isLiteral(ne.CollExpr)
+		if e.KeyExpr != nil {
+			ne.KeyExpr = s.simplify(e.KeyExpr, childCtx)
+			fold = fold && s.onlyRefersTo(ne.KeyExpr, iterVars)
+		}
+		ne.ValExpr = s.simplify(e.ValExpr, childCtx)
+		fold = fold && s.onlyRefersTo(ne.ValExpr, iterVars)
+		if e.CondExpr != nil {
+			ne.CondExpr = s.simplify(e.CondExpr, childCtx)
+			fold = fold && s.onlyRefersTo(ne.CondExpr, iterVars)
+		}
+		ret = &ne
+	case *LetExpr:
+		// The body is evaluated in a child scope where the bound name is
+		// defined, so we must make sure it doesn't resolve to any variable of
+		// the same name in ctx. If the bound value is already known then
+		// the parts of the body that use it may be folded too.
+		ne := *e
+		ne.ValueExpr = s.simplify(e.ValueExpr, ctx)
+
+		boundVal := cty.DynamicVal
+		if lit, isLit := ne.ValueExpr.(*LiteralValueExpr); isLit {
+			boundVal = lit.Val
+		}
+		childCtx := ctx.NewChild()
+		childCtx.Variables = map[string]cty.Value{
+			e.Name: boundVal,
+		}
+		ne.BodyExpr = s.simplify(e.BodyExpr, childCtx)
+		ret = &ne
+		fold = isLiteral(ne.ValueExpr) && isLiteral(ne.BodyExpr)
+	case *SplatExpr:
+		// The Each expression refers to Item, which has no value here and
+		// so evaluates to an unknown value, preventing any parts of it that
+		// depend on the current item from being folded.
+		ne := *e
+		ne.Source = s.simplify(e.Source, ctx)
+		ne.Each = s.simplify(e.Each, ctx)
+		ret = &ne
+		fold = isLiteral(ne.Source) && s.onlyRefersTo(ne.Each, nil)
+	case *BinaryOpExpr:
+		ne := *e
+		ne.LHS = s.simplify(e.LHS, ctx)
+		ne.RHS = s.simplify(e.RHS, ctx)
+		ret = &ne
+		fold = isLiteral(ne.LHS) && isLiteral(ne.RHS)
+	case *UnaryOpExpr:
+		ne := *e
+		ne.Val = s.simplify(e.Val, ctx)
+		ret = &ne
+		fold = isLiteral(ne.Val)
+	case *TemplateExpr:
+		ne := *e
+		ne.Parts = mergeTemplateParts(s.simplifyAll(e.Parts, ctx))
+		ret = &ne
+		fold = allLiteral(ne.Parts)
+	case *TemplateJoinExpr:
+		ne := *e
+		ne.Tuple = s.simplify(e.Tuple, ctx)
+		ret = &ne
+		fold = isLiteral(ne.Tuple)
+	case *TemplateWrapExpr:
+		ne := *e
+		ne.Wrapped = s.simplify(e.Wrapped, ctx)
+		ret = &ne
+		fold = isLiteral(ne.Wrapped)
+	default:
+		// We don't know how to simplify the children of other expression
+		// types, and so we can't know whether it's safe to fold them.
+		return expr
+	}
+
+	if !fold || hcl.ExprSensitive(ret, ctx) {
+		// A literal would lose the association with any sensitive variables
+		// the value was derived from.
+		return ret
+	}
+
+	val, diags := ret.Value(ctx)
+	if diags.HasErrors() || !val.IsWhollyKnown() {
+		return ret
+	}
+	return &LiteralValueExpr{
+		Val:      val,
+		SrcRange: expr.Range(),
+	}
+}
+
+// onlyRefersTo returns true if the given expression, which has already been
+// simplified, refers to no variables other than the given names and calls
+// only pure functions, so that its value depends only on those variables.
+func (s *simplifier) onlyRefersTo(expr Expression, names map[string]bool) bool {
+	ok := true
+	VisitAll(expr, func(node Node) hcl.Diagnostics {
+		switch n := node.(type) {
+		case *ScopeTraversalExpr:
+			if !names[n.Traversal.RootName()] {
+				ok = false
+			}
+		case *FunctionCallExpr:
+			if !s.pure[n.Name] {
+				ok = false
+			}
+		case *ForExpr, *LetExpr:
+			// These introduce names of their own, which we don't attempt
+			// to track here.
+			ok = false
+		}
+		return nil
+	})
+	return ok
+}
+
+// isLiteral returns true if the given expression, which has already been
+// simplified, was folded to a literal value.
+func isLiteral(expr Expression) bool {
+	switch e := expr.(type) {
+	case *LiteralValueExpr:
+		return true
+	case *ObjectConsKeyExpr:
+		return e.literalName() != "" || isLiteral(e.Wrapped)
+	default:
+		return false
+	}
+}
+
+func allLiteral(exprs []Expression) bool {
+	for _, expr := range exprs {
+		if !isLiteral(expr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *simplifier) simplifyAll(exprs []Expression, ctx *hcl.EvalContext) []Expression {
+	if exprs == nil {
+		return nil
+	}
+	ret := make([]Expression, len(exprs))
+	for i, expr := range exprs {
+		ret[i] = s.simplify(expr, ctx)
+	}
+	return ret
+}
+
+// mergeTemplateParts combines each sequence of adjacent template parts that
+// are literals into a single literal string part.
+func mergeTemplateParts(parts []Expression) []Expression {
+	ret := make([]Expression, 0, len(parts))
+	for _, part := range parts {
+		str, ok := templatePartString(part)
+		if ok && len(ret) > 0 {
+			if prevStr, prevOK := templatePartString(ret[len(ret)-1]); prevOK {
+				ret[len(ret)-1] = &LiteralValueExpr{
+					Val:      cty.StringVal(prevStr + str),
+					SrcRange: hcl.RangeBetween(ret[len(ret)-1].Range(), part.Range()),
+				}
+				continue
+			}
+		}
+		ret = append(ret, part)
+	}
+	return ret
+}
+
+// templatePartString returns the string that the given template part
+// contributes to the template result, if it is a literal whose value can
+// be included in a template.
+func templatePartString(part Expression) (string, bool) {
+	lit, isLit := part.(*LiteralValueExpr)
+	if !isLit || lit.Val.IsNull() || !lit.Val.IsKnown() {
+		return "", false
+	}
+	strVal, err := convert.Convert(lit.Val, cty.String)
+	if err != nil {
+		return "", false
+	}
+	return strVal.AsString(), true
+}
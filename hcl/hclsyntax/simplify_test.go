@@ -0,0 +1,336 @@
+package hclsyntax
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+func TestSimplify(t *testing.T) {
+	funcs := map[string]function.Function{
+		"upper": stdlib.UpperFunc,
+	}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"known": cty.StringVal("hello"),
+			"flag":  cty.True,
+			"list":  cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			"unk":   cty.UnknownVal(cty.String),
+		},
+		Functions: funcs,
+	}
+
+	// fullCtx has values for all of the variables that are missing or
+	// unknown in ctx, so that we can check that the simplified expression
+	// produces the same result as the original.
+	fullCtx := ctx.NewChild()
+	fullCtx.Variables = map[string]cty.Value{
+		"unk":     cty.StringVal("later"),
+		"missing": cty.NumberIntVal(5),
+		"objs": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("x")}),
+		}),
+	}
+
+	tests := []struct {
+		input string
+		want  string // the result as formatted by simplifyTestString
+	}{
+		{
+			`1`,
+			`1`,
+		},
+		{
+			`known`,
+			`"hello"`,
+		},
+		{
+			`unk`,
+			`unk`,
+		},
+		{
+			`missing`,
+			`missing`,
+		},
+		{
+			`upper(known)`,
+			`"HELLO"`,
+		},
+		{
+			`upper(unk)`,
+			`upper(unk)`,
+		},
+		{
+			`missing + (2 * 3)`,
+			`(missing + 6)`,
+		},
+		{
+			`[known, missing, list[1]]`,
+			`["hello", missing, "b"]`,
+		},
+		{
+			`{a = known, b = missing}`,
+			`{a = "hello", b = missing}`,
+		},
+		{
+			`{"${known}" = missing}`,
+			`{"hello" = missing}`,
+		},
+		{
+			`flag ? missing : nope`,
+			`missing`,
+		},
+		{
+			`!flag ? missing : upper(known)`,
+			`"HELLO"`,
+		},
+		{
+			`missing > 2 ? known : unk`,
+			`((missing > 2) ? "hello" : unk)`,
+		},
+		{
+			`"${known}, ${upper(known)}! ${unk} and ${missing}"`,
+			`template("hello, HELLO! ", unk, " and ", missing)`,
+		},
+		{
+			`"${known} world"`,
+			`"hello world"`,
+		},
+		{
+			`[for s in list: upper(s)]`,
+			`["A", "B"]`,
+		},
+		{
+			`[for known in list: known]`, // iterator shadows the variable
+			`["a", "b"]`,
+		},
+		{
+			`[for s in missing: s + upper(known)]`,
+			`for(missing, (s + "HELLO"))`,
+		},
+		{
+			`[for known in missing: known]`,
+			`for(missing, known)`,
+		},
+		{
+			`let s = upper(known) in "${s}!"`,
+			`"HELLO!"`,
+		},
+		{
+			`let s = upper(known) in [s, missing]`,
+			`let(s, "HELLO", ["HELLO", missing])`,
+		},
+		{
+			`let known = missing in known`,
+			`let(known, missing, known)`,
+		},
+		{
+			`list.*`,
+			`["a", "b"]`,
+		},
+		{
+			`objs.*.name`,
+			`splat(objs)`,
+		},
+		{
+			`nope(known)`,
+			`nope("hello")`,
+		},
+		{
+			`missing[upper(known)]`,
+			`missing["HELLO"]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			expr, parseDiags := ParseExpression([]byte(test.input), "", hcl.Pos{Line: 1, Column: 1})
+			if parseDiags.HasErrors() {
+				t.Fatalf("unexpected parse errors: %s", parseDiags.Error())
+			}
+
+			got := Simplify(expr, ctx, "upper")
+
+			if gotStr := simplifyTestString(got); gotStr != test.want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", gotStr, test.want)
+			}
+
+			wantVal, wantDiags := expr.Value(fullCtx)
+			gotVal, gotDiags := got.Value(fullCtx)
+			if len(gotDiags) != len(wantDiags) {
+				t.Errorf("wrong number of diagnostics from simplified expression %d; want %d", len(gotDiags), len(wantDiags))
+			}
+			if !gotVal.RawEquals(wantVal) {
+				t.Errorf("simplified expression has wrong value\ngot:  %#v\nwant: %#v", gotVal, wantVal)
+			}
+		})
+	}
+}
+
+func TestSimplifyNoMutate(t *testing.T) {
+	expr, parseDiags := ParseExpression([]byte(`[upper(known), missing]`), "", hcl.Pos{Line: 1, Column: 1})
+	if parseDiags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", parseDiags.Error())
+	}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"known": cty.StringVal("hello"),
+		},
+		Functions: map[string]function.Function{
+			"upper": stdlib.UpperFunc,
+		},
+	}
+
+	Simplify(expr, ctx, "upper")
+
+	if got, want := simplifyTestString(expr), `[upper(known), missing]`; got != want {
+		t.Errorf("original expression was modified\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestSimplifySensitive(t *testing.T) {
+	expr, parseDiags := ParseExpression([]byte(`[upper(secret), upper(known)]`), "", hcl.Pos{Line: 1, Column: 1})
+	if parseDiags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", parseDiags.Error())
+	}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"known":  cty.StringVal("hello"),
+			"secret": cty.StringVal("hunter2"),
+		},
+		Sensitive: []hcl.Traversal{
+			{hcl.TraverseRoot{Name: "secret"}},
+		},
+		Functions: map[string]function.Function{
+			"upper": stdlib.UpperFunc,
+		},
+	}
+
+	got := Simplify(expr, ctx, "upper")
+
+	if got, want := simplifyTestString(got), `[upper(secret), "HELLO"]`; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+	if !hcl.ExprSensitive(got, ctx) {
+		t.Errorf("simplified expression is not sensitive")
+	}
+}
+
+func TestSimplifyFunctions(t *testing.T) {
+	calls := 0
+	counted := function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "v", Type: cty.Number},
+		},
+		Type: function.StaticReturnType(cty.Number),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			calls++
+			return args[0], nil
+		},
+	})
+	ctx := &hcl.EvalContext{
+		Functions: map[string]function.Function{
+			"pure":   counted,
+			"impure": counted,
+		},
+	}
+
+	expr, parseDiags := ParseExpression([]byte(`[pure(pure(pure(pure(1)))), impure(pure(2))]`), "", hcl.Pos{Line: 1, Column: 1})
+	if parseDiags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", parseDiags.Error())
+	}
+
+	got := Simplify(expr, ctx, "pure")
+
+	if got, want := simplifyTestString(got), `[1, impure(2)]`; got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+	// Each call to a pure function should be evaluated only once, rather
+	// than again for each of the expressions that contain it.
+	if got, want := calls, 5; got != want {
+		t.Errorf("wrong number of function calls %d; want %d", got, want)
+	}
+}
+
+// simplifyTestString produces a compact string representation of an
+// expression tree for comparing results in TestSimplify.
+func simplifyTestString(expr Expression) string {
+	switch e := expr.(type) {
+	case *LiteralValueExpr:
+		switch {
+		case e.Val.Type() == cty.String:
+			return fmt.Sprintf("%q", e.Val.AsString())
+		case e.Val.Type() == cty.Number:
+			return e.Val.AsBigFloat().Text('f', -1)
+		case e.Val.Type().IsTupleType() || e.Val.Type().IsListType():
+			var parts []Expression
+			for it := e.Val.ElementIterator(); it.Next(); {
+				_, v := it.Element()
+				parts = append(parts, &LiteralValueExpr{Val: v})
+			}
+			return simplifyTestString(&TupleConsExpr{Exprs: parts})
+		default:
+			return fmt.Sprintf("%#v", e.Val)
+		}
+	case *ScopeTraversalExpr:
+		return e.Traversal.RootName()
+	case *FunctionCallExpr:
+		return e.Name + simplifyTestList("(", e.Args, ")")
+	case *ConditionalExpr:
+		return fmt.Sprintf("(%s ? %s : %s)", simplifyTestString(e.Condition), simplifyTestString(e.TrueResult), simplifyTestString(e.FalseResult))
+	case *IndexExpr:
+		return fmt.Sprintf("%s[%s]", simplifyTestString(e.Collection), simplifyTestString(e.Key))
+	case *TupleConsExpr:
+		return simplifyTestList("[", e.Exprs, "]")
+	case *ObjectConsExpr:
+		var exprs []Expression
+		for _, item := range e.Items {
+			exprs = append(exprs, item.KeyExpr, item.ValueExpr)
+		}
+		ret := "{"
+		for i := 0; i < len(exprs); i += 2 {
+			if i > 0 {
+				ret += ", "
+			}
+			ret += simplifyTestString(exprs[i]) + " = " + simplifyTestString(exprs[i+1])
+		}
+		return ret + "}"
+	case *ObjectConsKeyExpr:
+		if ln := e.literalName(); ln != "" {
+			return ln
+		}
+		return simplifyTestString(e.Wrapped)
+	case *ForExpr:
+		return fmt.Sprintf("for(%s, %s)", simplifyTestString(e.CollExpr), simplifyTestString(e.ValExpr))
+	case *LetExpr:
+		return fmt.Sprintf("let(%s, %s, %s)", e.Name, simplifyTestString(e.ValueExpr), simplifyTestString(e.BodyExpr))
+	case *SplatExpr:
+		return fmt.Sprintf("splat(%s)", simplifyTestString(e.Source))
+	case *BinaryOpExpr:
+		ops := map[*Operation]string{OpAdd: "+", OpMultiply: "*", OpGreaterThan: ">"}
+		return fmt.Sprintf("(%s %s %s)", simplifyTestString(e.LHS), ops[e.Op], simplifyTestString(e.RHS))
+	case *UnaryOpExpr:
+		return fmt.Sprintf("!%s", simplifyTestString(e.Val))
+	case *TemplateExpr:
+		return simplifyTestList("template(", e.Parts, ")")
+	case *TemplateWrapExpr:
+		return simplifyTestString(e.Wrapped)
+	default:
+		return fmt.Sprintf("%T", expr)
+	}
+}
+
+func simplifyTestList(open string, exprs []Expression, close string) string {
+	ret := open
+	for i, expr := range exprs {
+		if i > 0 {
+			ret += ", "
+		}
+		ret += simplifyTestString(expr)
+	}
+	return ret + close
+}
@@ -34,6 +34,18 @@ type Body struct {
 	Attributes Attributes
 	Blocks     Blocks
 
+	// BadAttributes records regions of the body that could not be parsed
+	// as either an attribute or a block. This is populated only by
+	// ParseConfigTolerant, and is always nil for bodies produced by the
+	// normal parser.
+	BadAttributes []*BadAttribute
+
+	// InnerComments are the comments within the body that are not
+	// attached to any of its attributes or blocks, such as comments
+	// separated from the following item by a blank line, comments at the
+	// end of the body, and comments within attribute expressions.
+	InnerComments []*Comment
+
 	// These are used with PartialContent to produce a "remaining items"
 	// body to return. They are nil on all bodies fresh out of the parser.
 	hiddenAttrs  map[string]struct{}
@@ -49,6 +61,9 @@ var assertBodyImplBody hcl.Body = &Body{}
 func (b *Body) walkChildNodes(w internalWalkFunc) {
 	w(b.Attributes)
 	w(b.Blocks)
+	for _, bad := range b.BadAttributes {
+		w(bad)
+	}
 }
 
 func (b *Body) Range() hcl.Range {
@@ -229,8 +244,10 @@ func (b *Body) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Bod
 	}
 
 	remain := &Body{
-		Attributes: b.Attributes,
-		Blocks:     b.Blocks,
+		Attributes:    b.Attributes,
+		Blocks:        b.Blocks,
+		BadAttributes: b.BadAttributes,
+		InnerComments: b.InnerComments,
 
 		hiddenAttrs:  hiddenAttrs,
 		hiddenBlocks: hiddenBlocks,
@@ -319,6 +336,12 @@ type Attribute struct {
 	Name string
 	Expr Expression
 
+	// LeadComments are the comments on the lines immediately preceding the
+	// attribute, and LineComments are any comments that follow it on the
+	// same line as the end of its expression.
+	LeadComments []*Comment
+	LineComments []*Comment
+
 	SrcRange    hcl.Range
 	NameRange   hcl.Range
 	EqualsRange hcl.Range
@@ -346,6 +369,22 @@ func (a *Attribute) AsHCLAttribute() *hcl.Attribute {
 	}
 }
 
+// BadAttribute represents a region of a body where an attribute or block
+// definition was expected but could not be parsed. These are produced only
+// by ParseConfigTolerant, to mark the parts of the source that were skipped
+// while recovering from syntax errors.
+type BadAttribute struct {
+	SrcRange hcl.Range
+}
+
+func (a *BadAttribute) walkChildNodes(w internalWalkFunc) {
+	// Bad attributes have no child nodes
+}
+
+func (a *BadAttribute) Range() hcl.Range {
+	return a.SrcRange
+}
+
 // Blocks is the list of nested blocks within a body.
 type Blocks []*Block
 
@@ -375,6 +414,13 @@ type Block struct {
 	Labels []string
 	Body   *Body
 
+	// LeadComments are the comments on the lines immediately preceding the
+	// block header, and LineComments are any comments that follow the
+	// block's closing brace on the same line. Comments inside the block
+	// are attached to the nodes within Body.
+	LeadComments []*Comment
+	LineComments []*Comment
+
 	TypeRange       hcl.Range
 	LabelRanges     []hcl.Range
 	OpenBraceRange  hcl.Range
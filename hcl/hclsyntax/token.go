@@ -63,8 +63,10 @@ const (
 	TokenEllipsis TokenType = '…'
 	TokenFatArrow TokenType = '⇒'
 
-	TokenQuestion TokenType = '?'
-	TokenColon    TokenType = ':'
+	TokenQuestion       TokenType = '?'
+	TokenColon          TokenType = ':'
+	TokenDoubleQuestion TokenType = '⁇'
+	TokenQuestionDot    TokenType = '⸮'
 
 	TokenTemplateInterp  TokenType = '∫'
 	TokenTemplateControl TokenType = 'λ'
@@ -39,6 +39,8 @@ func _() {
 	_ = x[TokenFatArrow-8658]
 	_ = x[TokenQuestion-63]
 	_ = x[TokenColon-58]
+	_ = x[TokenDoubleQuestion-8263]
+	_ = x[TokenQuestionDot-11822]
 	_ = x[TokenTemplateInterp-8747]
 	_ = x[TokenTemplateControl-955]
 	_ = x[TokenTemplateSeqEnd-8718]
@@ -64,7 +66,7 @@ func _() {
 	_ = x[TokenNil-0]
 }
 
-const _TokenType_name = "TokenNilTokenNewlineTokenBangTokenPercentTokenBitwiseAndTokenApostropheTokenOParenTokenCParenTokenStarTokenPlusTokenCommaTokenMinusTokenDotTokenSlashTokenColonTokenSemicolonTokenLessThanTokenEqualTokenGreaterThanTokenQuestionTokenCommentTokenOHeredocTokenIdentTokenNumberLitTokenQuotedLitTokenStringLitTokenOBrackTokenCBrackTokenBitwiseXorTokenBacktickTokenCHeredocTokenOBraceTokenBitwiseOrTokenCBraceTokenBitwiseNotTokenOQuoteTokenCQuoteTokenTemplateControlTokenEllipsisTokenFatArrowTokenTemplateSeqEndTokenAndTokenOrTokenTemplateInterpTokenEqualOpTokenNotEqualTokenLessThanEqTokenGreaterThanEqTokenEOFTokenTabsTokenQuotedNewlineTokenStarStarTokenInvalidTokenBadUTF8"
+const _TokenType_name = "TokenNilTokenNewlineTokenBangTokenPercentTokenBitwiseAndTokenApostropheTokenOParenTokenCParenTokenStarTokenPlusTokenCommaTokenMinusTokenDotTokenSlashTokenColonTokenSemicolonTokenLessThanTokenEqualTokenGreaterThanTokenQuestionTokenCommentTokenOHeredocTokenIdentTokenNumberLitTokenQuotedLitTokenStringLitTokenOBrackTokenCBrackTokenBitwiseXorTokenBacktickTokenCHeredocTokenOBraceTokenBitwiseOrTokenCBraceTokenBitwiseNotTokenOQuoteTokenCQuoteTokenTemplateControlTokenEllipsisTokenDoubleQuestionTokenFatArrowTokenTemplateSeqEndTokenAndTokenOrTokenTemplateInterpTokenEqualOpTokenNotEqualTokenLessThanEqTokenGreaterThanEqTokenEOFTokenTabsTokenQuotedNewlineTokenStarStarTokenQuestionDotTokenInvalidTokenBadUTF8"
 
 var _TokenType_map = map[TokenType]string{
 	0:      _TokenType_name[0:8],
@@ -106,21 +108,23 @@ var _TokenType_map = map[TokenType]string{
 	187:    _TokenType_name[427:438],
 	955:    _TokenType_name[438:458],
 	8230:   _TokenType_name[458:471],
-	8658:   _TokenType_name[471:484],
-	8718:   _TokenType_name[484:503],
-	8743:   _TokenType_name[503:511],
-	8744:   _TokenType_name[511:518],
-	8747:   _TokenType_name[518:537],
-	8788:   _TokenType_name[537:549],
-	8800:   _TokenType_name[549:562],
-	8804:   _TokenType_name[562:577],
-	8805:   _TokenType_name[577:595],
-	9220:   _TokenType_name[595:603],
-	9225:   _TokenType_name[603:612],
-	9252:   _TokenType_name[612:630],
-	10138:  _TokenType_name[630:643],
-	65533:  _TokenType_name[643:655],
-	128169: _TokenType_name[655:667],
+	8263:   _TokenType_name[471:490],
+	8658:   _TokenType_name[490:503],
+	8718:   _TokenType_name[503:522],
+	8743:   _TokenType_name[522:530],
+	8744:   _TokenType_name[530:537],
+	8747:   _TokenType_name[537:556],
+	8788:   _TokenType_name[556:568],
+	8800:   _TokenType_name[568:581],
+	8804:   _TokenType_name[581:596],
+	8805:   _TokenType_name[596:614],
+	9220:   _TokenType_name[614:622],
+	9225:   _TokenType_name[622:631],
+	9252:   _TokenType_name[631:649],
+	10138:  _TokenType_name[649:662],
+	11822:  _TokenType_name[662:678],
+	65533:  _TokenType_name[678:690],
+	128169: _TokenType_name[690:702],
 }
 
 func (i TokenType) String() string {
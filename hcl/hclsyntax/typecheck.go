@@ -0,0 +1,901 @@
+package hclsyntax
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl2/ext/customdecode"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// A TypeContext is the type-only counterpart of hcl.EvalContext. It provides
+// the types of the variables and the signatures of the functions that should
+// be used when checking the type of an expression with ExprType.
+type TypeContext struct {
+	Variables map[string]cty.Type
+	Functions map[string]function.Function
+	parent    *TypeContext
+
+	// values is used instead of Variables by typeContextForEvalContext,
+	// in which case the types of the variables are the types of these
+	// values.
+	values map[string]cty.Value
+}
+
+// NewChild returns a new TypeContext that is a child of the receiver.
+func (ctx *TypeContext) NewChild() *TypeContext {
+	return &TypeContext{parent: ctx}
+}
+
+// Parent returns the parent of the receiver, or nil if the receiver has
+// no parent.
+func (ctx *TypeContext) Parent() *TypeContext {
+	return ctx.parent
+}
+
+// TypeContextForEvalContext returns a TypeContext with the same structure
+// as the given EvalContext, whose variables have the types of the
+// EvalContext's variable values and whose functions are the EvalContext's
+// functions.
+//
+// The result is nil if the given EvalContext is nil.
+func TypeContextForEvalContext(ctx *hcl.EvalContext) *TypeContext {
+	if ctx == nil {
+		return nil
+	}
+
+	ret := TypeContextForEvalContext(ctx.Parent()).NewChild()
+	ret.Functions = ctx.Functions
+	if ctx.Variables != nil {
+		ret.Variables = make(map[string]cty.Type, len(ctx.Variables))
+		for name, val := range ctx.Variables {
+			ret.Variables[name] = val.Type()
+		}
+	}
+	return ret
+}
+
+// typeContextForEvalContext is like TypeContextForEvalContext except that it
+// takes the variable types from the EvalContext only when they are needed,
+// rather than copying all of them in advance.
+func typeContextForEvalContext(ctx *hcl.EvalContext) *TypeContext {
+	if ctx == nil {
+		return nil
+	}
+
+	ret := typeContextForEvalContext(ctx.Parent()).NewChild()
+	ret.Functions = ctx.Functions
+	ret.values = ctx.Variables
+	return ret
+}
+
+// variable returns the type of the variable with the given name in the
+// receiver, ignoring any parent contexts.
+func (ctx *TypeContext) variable(name string) (cty.Type, bool) {
+	if ctx.values != nil {
+		val, exists := ctx.values[name]
+		return val.Type(), exists
+	}
+	ty, exists := ctx.Variables[name]
+	return ty, exists
+}
+
+// variableNames returns the names of the variables in the receiver,
+// ignoring any parent contexts.
+func (ctx *TypeContext) variableNames() []string {
+	var ret []string
+	if ctx.values != nil {
+		for name := range ctx.values {
+			ret = append(ret, name)
+		}
+		return ret
+	}
+	for name := range ctx.Variables {
+		ret = append(ret, name)
+	}
+	return ret
+}
+
+// ExprType statically infers the type of the result of the given expression
+// when evaluated with variables of the types given in the TypeContext,
+// without evaluating it.
+//
+// Unlike Value, ExprType visits every part of the expression, including both
+// results of a conditional expression and the bodies of "for" expressions,
+// and so it returns all of the type errors it can find at once. Since values
+// are not known, it cannot detect errors that depend on particular values,
+// such as dividing by zero or indexing a list with an out-of-range index.
+//
+// If the result type cannot be predicted, cty.DynamicPseudoType is
+// returned. This is also the case when the returned diagnostics contain
+// errors.
+func ExprType(expr Expression, ctx *TypeContext) (cty.Type, hcl.Diagnostics) {
+	c := newTypeChecker()
+	val := c.check(expr, ctx)
+	if c.diags.HasErrors() {
+		return cty.DynamicPseudoType, c.diags
+	}
+	return val.Type(), c.diags
+}
+
+// staticResult returns a placeholder for the result of the given expression
+// when evaluated in the given EvalContext, without evaluating it. The result
+// is an unknown value of the expression's type, unless the expression is a
+// literal null, in which case the result is that null.
+//
+// This is used when only the type of an expression is needed, such as for
+// the result that wasn't selected by a conditional expression. If the type
+// can't be determined precisely then the result is cty.DynamicVal, and so
+// the caller must accept a less precise type in that case. Any errors are
+// ignored.
+func staticResult(expr Expression, ctx *hcl.EvalContext) cty.Value {
+	c := newTypeChecker()
+	val := c.check(expr, typeContextForEvalContext(ctx))
+	switch {
+	case val.RawEquals(cty.NullVal(cty.DynamicPseudoType)) && !c.diags.HasErrors():
+		return val
+	case c.diags.HasErrors() || val.Type().HasDynamicTypes():
+		return cty.DynamicVal
+	default:
+		return cty.UnknownVal(val.Type())
+	}
+}
+
+// typeChecker is the state for a single call to ExprType.
+//
+// Internally the checker represents the result of each expression as a
+// cty.Value, which is unknown unless the expression is a literal. Retaining
+// literal values allows us to determine the attribute names of object
+// constructors and the element types of tuples indexed by a literal number.
+type typeChecker struct {
+	anonSymbols map[*AnonSymbolExpr]cty.Value
+	diags       hcl.Diagnostics
+}
+
+func newTypeChecker() *typeChecker {
+	return &typeChecker{
+		anonSymbols: map[*AnonSymbolExpr]cty.Value{},
+	}
+}
+
+func (c *typeChecker) check(expr Expression, ctx *TypeContext) cty.Value {
+	switch e := expr.(type) {
+	case *LiteralValueExpr:
+		return e.Val
+	case *BadExpr:
+		return cty.DynamicVal
+	case *ScopeTraversalExpr:
+		return c.checkScopeTraversal(e, ctx)
+	case *RelativeTraversalExpr:
+		src := c.check(e.Source, ctx)
+		ret, diags := e.Traversal.TraverseRel(src)
+		c.appendDiags(diags, e)
+		return unknownResult(ret)
+	case *FunctionCallExpr:
+		return c.checkFunctionCall(e, ctx)
+	case *ConditionalExpr:
+		return c.checkConditional(e, ctx)
+	case *IndexExpr:
+		coll := c.check(e.Collection, ctx)
+		key := c.check(e.Key, ctx)
+		ret, diags := hcl.Index(coll, key, &e.SrcRange)
+		c.appendDiags(diags, e)
+		return unknownResult(ret)
+	case *TupleConsExpr:
+		vals := make([]cty.Value, len(e.Exprs))
+		for i, expr := range e.Exprs {
+			vals[i] = c.check(expr, ctx)
+		}
+		return cty.TupleVal(vals)
+	case *ObjectConsExpr:
+		return c.checkObjectCons(e, ctx)
+	case *ObjectConsKeyExpr:
+		return c.checkObjectConsKey(e, ctx)
+	case *ForExpr:
+		return c.checkFor(e, ctx)
+	case *LetExpr:
+		val := c.check(e.ValueExpr, ctx)
+		childCtx := ctx.NewChild()
+		childCtx.Variables = map[string]cty.Type{
+			e.Name: val.Type(),
+		}
+		return c.check(e.BodyExpr, childCtx)
+	case *SplatExpr:
+		return c.checkSplat(e, ctx)
+	case *AnonSymbolExpr:
+		if val, exists := c.anonSymbols[e]; exists {
+			return val
+		}
+		return cty.DynamicVal
+	case *BinaryOpExpr:
+		return c.checkBinaryOp(e, ctx)
+	case *UnaryOpExpr:
+		return c.checkUnaryOp(e, ctx)
+	case *TemplateExpr:
+		return c.checkTemplate(e, ctx)
+	case *TemplateJoinExpr:
+		c.check(e.Tuple, ctx)
+		return cty.UnknownVal(cty.String)
+	case *TemplateWrapExpr:
+		return c.check(e.Wrapped, ctx)
+	default:
+		// We don't know how to check other expression types, so we'll
+		// conservatively assume they could produce any value.
+		return cty.DynamicVal
+	}
+}
+
+func (c *typeChecker) appendDiags(diags hcl.Diagnostics, expr Expression) {
+	for _, diag := range diags {
+		if diag.Expression == nil {
+			diag.Expression = expr
+		}
+	}
+	c.diags = append(c.diags, diags...)
+}
+
+func (c *typeChecker) checkScopeTraversal(e *ScopeTraversalExpr, ctx *TypeContext) cty.Value {
+	split := e.Traversal.SimpleSplit()
+	root := split.Abs[0].(hcl.TraverseRoot)
+
+	hasNonNil := false
+	for thisCtx := ctx; thisCtx != nil; thisCtx = thisCtx.parent {
+		if thisCtx.Variables == nil && thisCtx.values == nil {
+			continue
+		}
+		hasNonNil = true
+		ty, exists := thisCtx.variable(root.Name)
+		if exists {
+			ret, diags := split.Rel.TraverseRel(cty.UnknownVal(ty))
+			c.appendDiags(diags, e)
+			return unknownResult(ret)
+		}
+	}
+
+	if !hasNonNil {
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity:   hcl.DiagError,
+			Summary:    "Variables not allowed",
+			Detail:     "Variables may not be used here.",
+			Subject:    &root.SrcRange,
+			Expression: e,
+		})
+		return cty.DynamicVal
+	}
+
+	var avail []string
+	for thisCtx := ctx; thisCtx != nil; thisCtx = thisCtx.parent {
+		avail = append(avail, thisCtx.variableNames()...)
+	}
+	suggestion := nameSuggestion(root.Name, avail)
+	if suggestion != "" {
+		suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+	}
+	c.diags = append(c.diags, &hcl.Diagnostic{
+		Severity:   hcl.DiagError,
+		Summary:    "Unknown variable",
+		Detail:     fmt.Sprintf("There is no variable named %q.%s", root.Name, suggestion),
+		Subject:    &root.SrcRange,
+		Expression: e,
+	})
+	return cty.DynamicVal
+}
+
+func (c *typeChecker) checkFunctionCall(e *FunctionCallExpr, ctx *TypeContext) cty.Value {
+	var f function.Function
+	exists := false
+	hasNonNilMap := false
+	for thisCtx := ctx; thisCtx != nil; thisCtx = thisCtx.parent {
+		if thisCtx.Functions == nil {
+			continue
+		}
+		hasNonNilMap = true
+		f, exists = thisCtx.Functions[e.Name]
+		if exists {
+			break
+		}
+	}
+
+	// We check all of the arguments first, so that we'll find any errors
+	// inside them even if the call itself turns out to be invalid.
+	args := make([]cty.Value, len(e.Args))
+	for i, arg := range e.Args {
+		diagsLen := len(c.diags)
+		args[i] = c.check(arg, ctx)
+		if exists && functionParamIsCustomDecoded(f, i) {
+			// A function taking the expression itself is expected to deal
+			// with any errors that evaluating it would produce, so we
+			// discard them.
+			c.diags = c.diags[:diagsLen]
+		}
+	}
+
+	if !exists {
+		if !hasNonNilMap {
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity:   hcl.DiagError,
+				Summary:    "Function calls not allowed",
+				Detail:     "Functions may not be called here.",
+				Subject:    e.Range().Ptr(),
+				Expression: e,
+			})
+			return cty.DynamicVal
+		}
+
+		var avail []string
+		for thisCtx := ctx; thisCtx != nil; thisCtx = thisCtx.parent {
+			for name := range thisCtx.Functions {
+				avail = append(avail, name)
+			}
+		}
+		suggestion := nameSuggestion(e.Name, avail)
+		if suggestion != "" {
+			suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+		}
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity:   hcl.DiagError,
+			Summary:    "Call to unknown function",
+			Detail:     fmt.Sprintf("There is no function named %q.%s", e.Name, suggestion),
+			Subject:    &e.NameRange,
+			Context:    e.Range().Ptr(),
+			Expression: e,
+		})
+		return cty.DynamicVal
+	}
+
+	params := f.Params()
+	varParam := f.VarParam()
+
+	argExprs := e.Args
+	if e.ExpandFinal {
+		expandExpr := argExprs[len(argExprs)-1]
+		expandVal := args[len(args)-1]
+		expandTy := expandVal.Type()
+		switch {
+		case expandTy == cty.DynamicPseudoType:
+			return cty.DynamicVal
+		case expandTy.IsTupleType():
+			if expandVal.IsNull() {
+				c.diags = append(c.diags, &hcl.Diagnostic{
+					Severity:   hcl.DiagError,
+					Summary:    "Invalid expanding argument value",
+					Detail:     "The expanding argument (indicated by ...) must not be null.",
+					Subject:    expandExpr.Range().Ptr(),
+					Context:    e.Range().Ptr(),
+					Expression: expandExpr,
+				})
+				return cty.DynamicVal
+			}
+
+			// The number of elements in a tuple is part of its type, so we
+			// can still check the call as if the elements were given as
+			// separate arguments.
+			etys := expandTy.TupleElementTypes()
+			newArgs := make([]cty.Value, 0, len(args)-1+len(etys))
+			newArgs = append(newArgs, args[:len(args)-1]...)
+			newExprs := make([]Expression, 0, cap(newArgs))
+			newExprs = append(newExprs, argExprs[:len(argExprs)-1]...)
+			for _, ety := range etys {
+				newArgs = append(newArgs, cty.UnknownVal(ety))
+				newExprs = append(newExprs, expandExpr)
+			}
+			args = newArgs
+			argExprs = newExprs
+		case expandTy.IsListType() || expandTy.IsSetType():
+			// We can't know how many arguments a list or set will expand
+			// to, so we can't check the call any further.
+			return cty.DynamicVal
+		default:
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity:   hcl.DiagError,
+				Summary:    "Invalid expanding argument value",
+				Detail:     "The expanding argument (indicated by ...) must be of a tuple, list, or set type.",
+				Subject:    expandExpr.Range().Ptr(),
+				Context:    e.Range().Ptr(),
+				Expression: expandExpr,
+			})
+			return cty.DynamicVal
+		}
+	}
+
+	if len(args) < len(params) {
+		missing := params[len(args)]
+		qual := ""
+		if varParam != nil {
+			qual = " at least"
+		}
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Not enough function arguments",
+			Detail: fmt.Sprintf(
+				"Function %q expects%s %d argument(s). Missing value for %q.",
+				e.Name, qual, len(params), missing.Name,
+			),
+			Subject:    &e.CloseParenRange,
+			Context:    e.Range().Ptr(),
+			Expression: e,
+		})
+		return cty.DynamicVal
+	}
+
+	if varParam == nil && len(args) > len(params) {
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Too many function arguments",
+			Detail: fmt.Sprintf(
+				"Function %q expects only %d argument(s).",
+				e.Name, len(params),
+			),
+			Subject:    argExprs[len(params)].StartRange().Ptr(),
+			Context:    e.Range().Ptr(),
+			Expression: e,
+		})
+		return cty.DynamicVal
+	}
+
+	valid := true
+	customDecoded := false
+	for i, arg := range args {
+		param := varParam
+		if i < len(params) {
+			param = &params[i]
+		}
+		argExpr := argExprs[i]
+
+		if customdecode.CustomExpressionDecoderForType(param.Type) != nil {
+			// We can't know what the custom decoder would produce without
+			// calling the function, so we'll just assume it succeeds.
+			customDecoded = true
+			continue
+		}
+
+		val, err := convert.Convert(arg, param.Type)
+		if err != nil {
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid function argument",
+				Detail: fmt.Sprintf(
+					"Invalid value for %q parameter: %s.",
+					param.Name, err,
+				),
+				Subject:    argExpr.StartRange().Ptr(),
+				Context:    e.Range().Ptr(),
+				Expression: argExpr,
+			})
+			valid = false
+		}
+		args[i] = val
+	}
+	if !valid {
+		return cty.DynamicVal
+	}
+	if customDecoded {
+		// A function that decodes its own arguments, like try, often
+		// decides its return type by evaluating them, which we can't do
+		// without values, so we can't say anything about its result.
+		return cty.DynamicVal
+	}
+
+	retTy, err := f.ReturnTypeForValues(args)
+	if err != nil {
+		switch terr := err.(type) {
+		case function.ArgError:
+			param := varParam
+			if terr.Index < len(params) {
+				param = &params[terr.Index]
+			}
+			argExpr := argExprs[terr.Index]
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid function argument",
+				Detail: fmt.Sprintf(
+					"Invalid value for %q parameter: %s.",
+					param.Name, err,
+				),
+				Subject:    argExpr.StartRange().Ptr(),
+				Context:    e.Range().Ptr(),
+				Expression: argExpr,
+			})
+		default:
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Error in function call",
+				Detail: fmt.Sprintf(
+					"Call to function %q failed: %s.",
+					e.Name, err,
+				),
+				Subject:    e.StartRange().Ptr(),
+				Context:    e.Range().Ptr(),
+				Expression: e,
+			})
+		}
+		return cty.DynamicVal
+	}
+
+	return cty.UnknownVal(retTy)
+}
+
+// functionParamIsCustomDecoded returns true if the parameter of the given
+// function that corresponds to the argument at the given index has a custom
+// expression decoder.
+func functionParamIsCustomDecoded(f function.Function, i int) bool {
+	var param *function.Parameter
+	if params := f.Params(); i < len(params) {
+		param = &params[i]
+	} else {
+		param = f.VarParam()
+	}
+	return param != nil && customdecode.CustomExpressionDecoderForType(param.Type) != nil
+}
+
+func (c *typeChecker) checkConditional(e *ConditionalExpr, ctx *TypeContext) cty.Value {
+	cond := c.check(e.Condition, ctx)
+	trueResult := c.check(e.TrueResult, ctx)
+	falseResult := c.check(e.FalseResult, ctx)
+
+	if cond.IsNull() {
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity:   hcl.DiagError,
+			Summary:    "Null condition",
+			Detail:     "The condition value is null. Conditions must either be true or false.",
+			Subject:    e.Condition.Range().Ptr(),
+			Context:    &e.SrcRange,
+			Expression: e.Condition,
+		})
+	} else if _, err := convert.Convert(cond, cty.Bool); err != nil {
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity:   hcl.DiagError,
+			Summary:    "Incorrect condition type",
+			Detail:     "The condition expression must be of type bool.",
+			Subject:    e.Condition.Range().Ptr(),
+			Context:    &e.SrcRange,
+			Expression: e.Condition,
+		})
+	}
+
+	trueTy := trueResult.Type()
+	falseTy := falseResult.Type()
+	switch {
+	case trueResult.RawEquals(cty.NullVal(cty.DynamicPseudoType)):
+		return cty.UnknownVal(falseTy)
+	case falseResult.RawEquals(cty.NullVal(cty.DynamicPseudoType)):
+		return cty.UnknownVal(trueTy)
+	case trueTy == cty.DynamicPseudoType, falseTy == cty.DynamicPseudoType:
+		return cty.DynamicVal
+	}
+
+	resultTy, _ := convert.UnifyUnsafe([]cty.Type{trueTy, falseTy})
+	if resultTy == cty.NilType {
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Inconsistent conditional result types",
+			Detail: fmt.Sprintf(
+				"The true and false result expressions must have consistent types. The given expressions are %s and %s, respectively.",
+				trueTy.FriendlyName(), falseTy.FriendlyName(),
+			),
+			Subject:    hcl.RangeBetween(e.TrueResult.Range(), e.FalseResult.Range()).Ptr(),
+			Context:    &e.SrcRange,
+			Expression: e,
+		})
+		return cty.DynamicVal
+	}
+	return cty.UnknownVal(resultTy)
+}
+
+func (c *typeChecker) checkObjectCons(e *ObjectConsExpr, ctx *TypeContext) cty.Value {
+	// As with evaluation, we can only predict the object type if all of
+	// the keys are known, which for type checking means they are literals.
+	known := true
+
+	vals := make(map[string]cty.Value, len(e.Items))
+	for _, item := range e.Items {
+		key := c.check(item.KeyExpr, ctx)
+		val := c.check(item.ValueExpr, ctx)
+
+		if key.IsNull() {
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity:   hcl.DiagError,
+				Summary:    "Null value as key",
+				Detail:     "Can't use a null value as a key.",
+				Subject:    item.ValueExpr.Range().Ptr(),
+				Expression: item.KeyExpr,
+			})
+			known = false
+			continue
+		}
+
+		key, err := convert.Convert(key, cty.String)
+		if err != nil {
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity:   hcl.DiagError,
+				Summary:    "Incorrect key type",
+				Detail:     fmt.Sprintf("Can't use this value as a key: %s.", err.Error()),
+				Subject:    item.KeyExpr.Range().Ptr(),
+				Expression: item.KeyExpr,
+			})
+			known = false
+			continue
+		}
+
+		if !key.IsKnown() {
+			known = false
+			continue
+		}
+
+		vals[key.AsString()] = val
+	}
+
+	if !known {
+		return cty.DynamicVal
+	}
+	return cty.ObjectVal(vals)
+}
+
+func (c *typeChecker) checkObjectConsKey(e *ObjectConsKeyExpr, ctx *TypeContext) cty.Value {
+	if travExpr, isTraversal := e.Wrapped.(*ScopeTraversalExpr); isTraversal && len(travExpr.Traversal) > 1 {
+		// We'll let Value produce the "Ambiguous attribute key" error, since
+		// it doesn't depend on any values.
+		_, diags := e.Value(nil)
+		c.diags = append(c.diags, diags...)
+		return cty.DynamicVal
+	}
+
+	if ln := e.literalName(); ln != "" {
+		return cty.StringVal(ln)
+	}
+	return c.check(e.Wrapped, ctx)
+}
+
+func (c *typeChecker) checkFor(e *ForExpr, ctx *TypeContext) cty.Value {
+	coll := c.check(e.CollExpr, ctx)
+	collTy := coll.Type()
+
+	keyTy := cty.DynamicPseudoType
+	valTy := cty.DynamicPseudoType
+	switch {
+	case coll.IsNull():
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity:   hcl.DiagError,
+			Summary:    "Iteration over null value",
+			Detail:     "A null value cannot be used as the collection in a 'for' expression.",
+			Subject:    e.CollExpr.Range().Ptr(),
+			Context:    &e.SrcRange,
+			Expression: e.CollExpr,
+		})
+	case collTy == cty.DynamicPseudoType:
+		// Both remain dynamic
+	case collTy.IsListType():
+		keyTy = cty.Number
+		valTy = collTy.ElementType()
+	case collTy.IsMapType():
+		keyTy = cty.String
+		valTy = collTy.ElementType()
+	case collTy.IsSetType():
+		keyTy = collTy.ElementType()
+		valTy = collTy.ElementType()
+	case collTy.IsTupleType():
+		keyTy = cty.Number
+	case collTy.IsObjectType():
+		keyTy = cty.String
+	default:
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Iteration over non-iterable value",
+			Detail: fmt.Sprintf(
+				"A value of type %s cannot be used as the collection in a 'for' expression.",
+				collTy.FriendlyName(),
+			),
+			Subject:    e.CollExpr.Range().Ptr(),
+			Context:    &e.SrcRange,
+			Expression: e.CollExpr,
+		})
+	}
+
+	childCtx := ctx.NewChild()
+	childCtx.Variables = map[string]cty.Type{}
+	if e.KeyVar != "" {
+		childCtx.Variables[e.KeyVar] = keyTy
+	}
+	childCtx.Variables[e.ValVar] = valTy
+
+	if e.CondExpr != nil {
+		cond := c.check(e.CondExpr, childCtx)
+		if cond.IsNull() {
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity:   hcl.DiagError,
+				Summary:    "Condition is null",
+				Detail:     "The value of the 'if' clause must not be null.",
+				Subject:    e.CondExpr.Range().Ptr(),
+				Context:    &e.SrcRange,
+				Expression: e.CondExpr,
+			})
+		} else if _, err := convert.Convert(cond, cty.Bool); err != nil {
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity:   hcl.DiagError,
+				Summary:    "Invalid 'for' condition",
+				Detail:     fmt.Sprintf("The 'if' clause value is invalid: %s.", err.Error()),
+				Subject:    e.CondExpr.Range().Ptr(),
+				Context:    &e.SrcRange,
+				Expression: e.CondExpr,
+			})
+		}
+	}
+
+	if e.KeyExpr != nil {
+		key := c.check(e.KeyExpr, childCtx)
+		if key.IsNull() {
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity:   hcl.DiagError,
+				Summary:    "Invalid object key",
+				Detail:     "Key expression in 'for' expression must not produce a null value.",
+				Subject:    e.KeyExpr.Range().Ptr(),
+				Context:    &e.SrcRange,
+				Expression: e.KeyExpr,
+			})
+		} else if _, err := convert.Convert(key, cty.String); err != nil {
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity:   hcl.DiagError,
+				Summary:    "Invalid object key",
+				Detail:     fmt.Sprintf("The key expression produced an invalid result: %s.", err.Error()),
+				Subject:    e.KeyExpr.Range().Ptr(),
+				Context:    &e.SrcRange,
+				Expression: e.KeyExpr,
+			})
+		}
+	}
+
+	c.check(e.ValExpr, childCtx)
+
+	// The result is either a tuple or an object whose length or attributes
+	// depend on the collection's value, so we can't predict its type.
+	return cty.DynamicVal
+}
+
+func (c *typeChecker) checkSplat(e *SplatExpr, ctx *TypeContext) cty.Value {
+	source := c.check(e.Source, ctx)
+	sourceTy := source.Type()
+	if sourceTy == cty.DynamicPseudoType {
+		// We still check the "Each" expression for any errors that don't
+		// depend on the item value, which is unknown in this case.
+		c.check(e.Each, ctx)
+		return cty.DynamicVal
+	}
+
+	autoUpgrade := !(sourceTy.IsTupleType() || sourceTy.IsListType() || sourceTy.IsSetType())
+	if source.IsNull() {
+		if autoUpgrade {
+			return cty.EmptyTupleVal
+		}
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity:   hcl.DiagError,
+			Summary:    "Splat of null value",
+			Detail:     "Splat expressions (with the * symbol) cannot be applied to null sequences.",
+			Subject:    e.Source.Range().Ptr(),
+			Context:    hcl.RangeBetween(e.Source.Range(), e.MarkerRange).Ptr(),
+			Expression: e.Source,
+		})
+		return cty.DynamicVal
+	}
+	if autoUpgrade {
+		sourceTy = cty.Tuple([]cty.Type{sourceTy})
+	}
+
+	defer delete(c.anonSymbols, e.Item)
+	switch {
+	case sourceTy.IsListType() || sourceTy.IsSetType():
+		c.anonSymbols[e.Item] = cty.UnknownVal(sourceTy.ElementType())
+		each := c.check(e.Each, ctx)
+		return cty.UnknownVal(cty.List(each.Type()))
+	default:
+		etys := sourceTy.TupleElementTypes()
+		resultTys := make([]cty.Type, len(etys))
+		for i, ety := range etys {
+			c.anonSymbols[e.Item] = cty.UnknownVal(ety)
+			each := c.check(e.Each, ctx)
+			resultTys[i] = each.Type()
+		}
+		return cty.UnknownVal(cty.Tuple(resultTys))
+	}
+}
+
+func (c *typeChecker) checkBinaryOp(e *BinaryOpExpr, ctx *TypeContext) cty.Value {
+	params := e.Op.Impl.Params()
+
+	lhs := c.check(e.LHS, ctx)
+	rhs := c.check(e.RHS, ctx)
+
+	if _, err := convert.Convert(lhs, params[0].Type); err != nil {
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity:   hcl.DiagError,
+			Summary:    "Invalid operand",
+			Detail:     fmt.Sprintf("Unsuitable value for left operand: %s.", err),
+			Subject:    e.LHS.Range().Ptr(),
+			Context:    &e.SrcRange,
+			Expression: e.LHS,
+		})
+	}
+	if _, err := convert.Convert(rhs, params[1].Type); err != nil {
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity:   hcl.DiagError,
+			Summary:    "Invalid operand",
+			Detail:     fmt.Sprintf("Unsuitable value for right operand: %s.", err),
+			Subject:    e.RHS.Range().Ptr(),
+			Context:    &e.SrcRange,
+			Expression: e.RHS,
+		})
+	}
+
+	if e.Op == OpNullCoalesce {
+		// The result type depends on whether the left operand is null.
+		ret, err := e.Op.Impl.Call([]cty.Value{lhs, rhs})
+		if err != nil {
+			return cty.DynamicVal
+		}
+		return ret
+	}
+
+	return cty.UnknownVal(e.Op.Type)
+}
+
+func (c *typeChecker) checkUnaryOp(e *UnaryOpExpr, ctx *TypeContext) cty.Value {
+	param := e.Op.Impl.Params()[0]
+
+	val := c.check(e.Val, ctx)
+	if _, err := convert.Convert(val, param.Type); err != nil {
+		c.diags = append(c.diags, &hcl.Diagnostic{
+			Severity:   hcl.DiagError,
+			Summary:    "Invalid operand",
+			Detail:     fmt.Sprintf("Unsuitable value for unary operand: %s.", err),
+			Subject:    e.Val.Range().Ptr(),
+			Context:    &e.SrcRange,
+			Expression: e.Val,
+		})
+	}
+
+	return cty.UnknownVal(e.Op.Type)
+}
+
+func (c *typeChecker) checkTemplate(e *TemplateExpr, ctx *TypeContext) cty.Value {
+	if e.IsStringLiteral() {
+		return c.check(e.Parts[0], ctx)
+	}
+
+	for _, part := range e.Parts {
+		val := c.check(part, ctx)
+		if val.IsNull() {
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity:   hcl.DiagError,
+				Summary:    "Invalid template interpolation value",
+				Detail:     "The expression result is null. Cannot include a null value in a string template.",
+				Subject:    part.Range().Ptr(),
+				Context:    &e.SrcRange,
+				Expression: part,
+			})
+			continue
+		}
+		if _, err := convert.Convert(val, cty.String); err != nil {
+			c.diags = append(c.diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid template interpolation value",
+				Detail: fmt.Sprintf(
+					"Cannot include the given value in a string template: %s.",
+					err.Error(),
+				),
+				Subject:    part.Range().Ptr(),
+				Context:    &e.SrcRange,
+				Expression: part,
+			})
+		}
+	}
+
+	return cty.UnknownVal(cty.String)
+}
+
+// unknownResult returns an unknown value of the same type as the given
+// value, so that the checker only retains values that come directly from
+// literals in the source.
+func unknownResult(val cty.Value) cty.Value {
+	return cty.UnknownVal(val.Type())
+}
@@ -0,0 +1,535 @@
+package hclsyntax
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl2/ext/customdecode"
+	"github.com/hashicorp/hcl2/ext/tryfunc"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+func TestExprType(t *testing.T) {
+	ctx := &TypeContext{
+		Variables: map[string]cty.Type{
+			"str":    cty.String,
+			"num":    cty.Number,
+			"flag":   cty.Bool,
+			"list":   cty.List(cty.String),
+			"set":    cty.Set(cty.Number),
+			"numMap": cty.Map(cty.Number),
+			"tup":    cty.Tuple([]cty.Type{cty.String, cty.Number}),
+			"obj": cty.Object(map[string]cty.Type{
+				"name": cty.String,
+				"tags": cty.List(cty.String),
+			}),
+			"objs": cty.List(cty.Object(map[string]cty.Type{
+				"id": cty.Number,
+			})),
+			"dyn": cty.DynamicPseudoType,
+		},
+		Functions: map[string]function.Function{
+			"upper":  stdlib.UpperFunc,
+			"concat": stdlib.ConcatFunc,
+			"min":    stdlib.MinFunc,
+			"length": stdlib.LengthFunc,
+			"try":    tryfunc.TryFunc,
+			"can":    tryfunc.CanFunc,
+			"exprfunc": function.New(&function.Spec{
+				Params: []function.Parameter{
+					{
+						Name: "expr",
+						Type: customdecode.ExpressionClosureType,
+					},
+				},
+				Type: function.StaticReturnType(cty.Bool),
+				Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+					return cty.True, nil
+				},
+			}),
+		},
+	}
+
+	tests := []struct {
+		input     string
+		ctx       *TypeContext
+		want      cty.Type
+		wantDiags []string // summaries
+	}{
+		{
+			`"hello"`,
+			nil,
+			cty.String,
+			nil,
+		},
+		{
+			`"hello ${str}"`,
+			ctx,
+			cty.String,
+			nil,
+		},
+		{
+			`"${num}"`,
+			ctx,
+			cty.Number,
+			nil,
+		},
+		{
+			`num + 1`,
+			ctx,
+			cty.Number,
+			nil,
+		},
+		{
+			`str + 1`, // a string might contain a number
+			ctx,
+			cty.Number,
+			nil,
+		},
+		{
+			`list + 1`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid operand"},
+		},
+		{
+			`!num`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid operand"},
+		},
+		{
+			`flag && num > 2`,
+			ctx,
+			cty.Bool,
+			nil,
+		},
+		{
+			`[str, num]`,
+			ctx,
+			cty.Tuple([]cty.Type{cty.String, cty.Number}),
+			nil,
+		},
+		{
+			`{a = str, "b" = num}`,
+			ctx,
+			cty.Object(map[string]cty.Type{
+				"a": cty.String,
+				"b": cty.Number,
+			}),
+			nil,
+		},
+		{
+			`{"${str}" = num}`,
+			ctx,
+			cty.DynamicPseudoType,
+			nil,
+		},
+		{
+			`{"${list}" = num}`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Incorrect key type"},
+		},
+		{
+			`list[0]`,
+			ctx,
+			cty.String,
+			nil,
+		},
+		{
+			`tup[1]`,
+			ctx,
+			cty.Number,
+			nil,
+		},
+		{
+			`numMap["a"]`,
+			ctx,
+			cty.Number,
+			nil,
+		},
+		{
+			`num[0]`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid index"},
+		},
+		{
+			`list[list]`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid index"},
+		},
+		{
+			`obj.name`,
+			ctx,
+			cty.String,
+			nil,
+		},
+		{
+			`obj.tags[0]`,
+			ctx,
+			cty.String,
+			nil,
+		},
+		{
+			`obj.nope`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Unsupported attribute"},
+		},
+		{
+			`obj?.name`,
+			ctx,
+			cty.String,
+			nil,
+		},
+		{
+			`obj?.nope`,
+			ctx,
+			cty.DynamicPseudoType,
+			nil,
+		},
+		{
+			`dyn?.anything`,
+			ctx,
+			cty.DynamicPseudoType,
+			nil,
+		},
+		{
+			`[obj][0].name`,
+			ctx,
+			cty.String,
+			nil,
+		},
+		{
+			`dyn.anything[0]`,
+			ctx,
+			cty.DynamicPseudoType,
+			nil,
+		},
+		{
+			`nope`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Unknown variable"},
+		},
+		{
+			`str`,
+			nil,
+			cty.DynamicPseudoType,
+			[]string{"Variables not allowed"},
+		},
+		{
+			`objs.*.id`,
+			ctx,
+			cty.List(cty.Number),
+			nil,
+		},
+		{
+			`tup[*]`,
+			ctx,
+			cty.Tuple([]cty.Type{cty.String, cty.Number}),
+			nil,
+		},
+		{
+			`obj.*.name`,
+			ctx,
+			cty.Tuple([]cty.Type{cty.String}),
+			nil,
+		},
+		{
+			`objs.*.nope`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Unsupported attribute"},
+		},
+		{
+			`upper(str)`,
+			ctx,
+			cty.String,
+			nil,
+		},
+		{
+			`upper(list)`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid function argument"},
+		},
+		{
+			`upper()`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Not enough function arguments"},
+		},
+		{
+			`upper(str, str)`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Too many function arguments"},
+		},
+		{
+			`upper(list + 1, str)`, // errors in the arguments are reported too
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid operand", "Too many function arguments"},
+		},
+		{
+			`min(tup...)`,
+			ctx,
+			cty.Number,
+			nil,
+		},
+		{
+			`upper(tup...)`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Too many function arguments"},
+		},
+		{
+			`upper(num...)`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid expanding argument value"},
+		},
+		{
+			`concat(list, list)`,
+			ctx,
+			cty.List(cty.String),
+			nil,
+		},
+		{
+			`lower(str)`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Call to unknown function"},
+		},
+		{
+			`upper("a")`,
+			&TypeContext{},
+			cty.DynamicPseudoType,
+			[]string{"Function calls not allowed"},
+		},
+		{
+			// Errors in arguments that the function receives as expressions
+			// are left for the function to handle.
+			`exprfunc(nope.foo + list)`,
+			ctx,
+			cty.DynamicPseudoType,
+			nil,
+		},
+		{
+			`try(obj.name, "b")`,
+			ctx,
+			cty.DynamicPseudoType,
+			nil,
+		},
+		{
+			`can(obj.nope)`,
+			ctx,
+			cty.DynamicPseudoType,
+			nil,
+		},
+		{
+			`str ?? "default"`,
+			ctx,
+			cty.String,
+			nil,
+		},
+		{
+			`str ?? num`,
+			ctx,
+			cty.String,
+			nil,
+		},
+		{
+			`list ?? num`,
+			ctx,
+			cty.DynamicPseudoType,
+			nil,
+		},
+		{
+			`flag ? str : num`,
+			ctx,
+			cty.String,
+			nil,
+		},
+		{
+			`flag ? null : list`,
+			ctx,
+			cty.List(cty.String),
+			nil,
+		},
+		{
+			`flag ? list : num`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Inconsistent conditional result types"},
+		},
+		{
+			`list ? str : str`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Incorrect condition type"},
+		},
+		{
+			// Errors in both branches are reported, regardless of the
+			// condition.
+			`flag ? num + list : upper(list)`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid operand", "Invalid function argument"},
+		},
+		{
+			`[for s in list: upper(s)]`,
+			ctx,
+			cty.DynamicPseudoType,
+			nil,
+		},
+		{
+			`[for i, s in list: s + list if i > 0]`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid operand"},
+		},
+		{
+			`{for k, v in numMap: k => v if v}`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid 'for' condition"},
+		},
+		{
+			`{for v in set: list => v}`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid object key"},
+		},
+		{
+			`[for v in num: v]`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Iteration over non-iterable value"},
+		},
+		{
+			`[for v in dyn: v.whatever]`,
+			ctx,
+			cty.DynamicPseudoType,
+			nil,
+		},
+		{
+			`[for v in list: v + list]`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid operand"},
+		},
+		{
+			`let s = obj.name in [s, upper(s)]`,
+			ctx,
+			cty.Tuple([]cty.Type{cty.String, cty.String}),
+			nil,
+		},
+		{
+			`let str = num in str + list`, // the binding shadows the variable
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid operand"},
+		},
+		{
+			`"${list}"`,
+			ctx,
+			cty.List(cty.String),
+			nil,
+		},
+		{
+			`"a ${list}"`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid template interpolation value"},
+		},
+		{
+			`"a ${null}"`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Invalid template interpolation value"},
+		},
+		{
+			`"%{ for s in list }${s}%{ endfor }"`,
+			ctx,
+			cty.String,
+			nil,
+		},
+		{
+			`{a.b = 1}`,
+			ctx,
+			cty.DynamicPseudoType,
+			[]string{"Ambiguous attribute key"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			expr, parseDiags := ParseExpression([]byte(test.input), "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+			if parseDiags.HasErrors() {
+				t.Fatalf("unexpected parse errors: %s", parseDiags.Error())
+			}
+
+			got, diags := ExprType(expr, test.ctx)
+
+			var gotDiags []string
+			for _, diag := range diags {
+				gotDiags = append(gotDiags, diag.Summary)
+			}
+			if len(gotDiags) != len(test.wantDiags) {
+				t.Errorf("wrong diagnostics\ngot:  %#v\nwant: %#v", gotDiags, test.wantDiags)
+			} else {
+				for i := range gotDiags {
+					if gotDiags[i] != test.wantDiags[i] {
+						t.Errorf("wrong diagnostics\ngot:  %#v\nwant: %#v", gotDiags, test.wantDiags)
+						break
+					}
+				}
+			}
+			for _, diag := range diags {
+				if diag.Subject == nil {
+					t.Errorf("diagnostic %q has no subject", diag.Summary)
+				}
+			}
+
+			if !got.Equals(test.want) {
+				t.Errorf("wrong result type\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTypeContextForEvalContext(t *testing.T) {
+	parent := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"a": cty.StringVal("hello"),
+		},
+		Functions: map[string]function.Function{
+			"upper": stdlib.UpperFunc,
+		},
+	}
+	child := parent.NewChild()
+	child.Variables = map[string]cty.Value{
+		"b": cty.ListValEmpty(cty.Number),
+	}
+
+	expr, parseDiags := ParseExpression([]byte(`[upper(a), b]`), "", hcl.Pos{Line: 1, Column: 1})
+	if parseDiags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", parseDiags.Error())
+	}
+
+	got, diags := ExprType(expr, TypeContextForEvalContext(child))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	want := cty.Tuple([]cty.Type{cty.String, cty.List(cty.Number)})
+	if !got.Equals(want) {
+		t.Errorf("wrong result type\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
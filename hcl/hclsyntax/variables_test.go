@@ -216,6 +216,47 @@ func TestVariables(t *testing.T) {
 				},
 			},
 		},
+		{
+			&LetExpr{
+				Name: "a",
+				ValueExpr: &ScopeTraversalExpr{
+					Traversal: hcl.Traversal{
+						hcl.TraverseRoot{
+							Name: "foo",
+						},
+					},
+				},
+				BodyExpr: &BinaryOpExpr{
+					LHS: &ScopeTraversalExpr{
+						Traversal: hcl.Traversal{
+							hcl.TraverseRoot{
+								Name: "a",
+							},
+						},
+					},
+					Op: OpAdd,
+					RHS: &ScopeTraversalExpr{
+						Traversal: hcl.Traversal{
+							hcl.TraverseRoot{
+								Name: "bar",
+							},
+						},
+					},
+				},
+			},
+			[]hcl.Traversal{
+				{
+					hcl.TraverseRoot{
+						Name: "foo",
+					},
+				},
+				{
+					hcl.TraverseRoot{
+						Name: "bar",
+					},
+				},
+			},
+		},
 		{
 			&ScopeTraversalExpr{
 				Traversal: hcl.Traversal{
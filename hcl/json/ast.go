@@ -2,8 +2,10 @@ package json
 
 import (
 	"math/big"
+	"sync"
 
 	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
 )
 
 type node interface {
@@ -30,6 +32,17 @@ type objectAttr struct {
 	Name      string
 	Value     node
 	NameRange hcl.Range // range of the name string
+
+	// NamePositions is the Positions of the name string; see stringVal.
+	NamePositions []hcl.Pos
+
+	// PrevDef is the nearest earlier property with the same name in the
+	// same object, if any. Whether a duplicate is valid depends on how the
+	// object is interpreted, so the parser just records it here along with
+	// DupSeverity, the severity for diagnostics about it where it is
+	// invalid, as chosen by ParseOptions.
+	PrevDef     *objectAttr
+	DupSeverity hcl.DiagnosticSeverity
 }
 
 func (n *objectAttr) Range() hcl.Range {
@@ -40,6 +53,27 @@ func (n *objectAttr) StartRange() hcl.Range {
 	return n.NameRange
 }
 
+// duplicateSeverity returns the severity for a diagnostic about the receiving
+// attribute duplicating an earlier attribute of the same body. Duplicates
+// within the same JSON object may be only warnings, as described in
+// ParseOptions, while those in separate objects are always errors.
+func (n *objectAttr) duplicateSeverity() hcl.DiagnosticSeverity {
+	if n.PrevDef != nil {
+		return n.DupSeverity
+	}
+	return hcl.DiagError
+}
+
+// NameVal returns the name of the attribute as a string node, for situations
+// where the name is to be interpreted as an expression.
+func (n *objectAttr) NameVal() *stringVal {
+	return &stringVal{
+		Value:     n.Name,
+		SrcRange:  n.NameRange,
+		Positions: n.NamePositions,
+	}
+}
+
 type arrayVal struct {
 	Values    []node
 	SrcRange  hcl.Range // range of the entire object, bracket-to-bracket
@@ -83,6 +117,12 @@ func (n *numberVal) StartRange() hcl.Range {
 type stringVal struct {
 	Value    string
 	SrcRange hcl.Range
+
+	// Positions gives the source position of each byte of Value, followed
+	// by the position of the closing quote, for a string whose source
+	// contains escape sequences and so is not a verbatim copy of Value.
+	// It is nil for all other strings.
+	Positions []hcl.Pos
 }
 
 func (n *stringVal) Range() hcl.Range {
@@ -93,6 +133,113 @@ func (n *stringVal) StartRange() hcl.Range {
 	return n.SrcRange
 }
 
+// ValueStartPos returns the position of the first byte of the value within
+// the string's source, just after the opening quote.
+//
+// When parsing the value as a template, this is the start position to use
+// so that the resulting positions can be converted to source positions
+// using SourceRange.
+func (n *stringVal) ValueStartPos() hcl.Pos {
+	return hcl.Pos{
+		Line:   n.SrcRange.Start.Line,
+		Byte:   n.SrcRange.Start.Byte + 1,
+		Column: n.SrcRange.Start.Column + 1,
+	}
+}
+
+// SourceRange converts the given range within the value, as produced by
+// parsing it from ValueStartPos, to the range of the corresponding source
+// code. Ranges outside of the value are returned unchanged.
+func (n *stringVal) SourceRange(rng hcl.Range) hcl.Range {
+	if n.Positions == nil || rng.Filename != n.SrcRange.Filename {
+		return rng
+	}
+	start := n.ValueStartPos().Byte
+	startOfs, endOfs := rng.Start.Byte-start, rng.End.Byte-start
+	if startOfs < 0 || endOfs < startOfs || endOfs >= len(n.Positions) {
+		return rng
+	}
+	return hcl.Range{
+		Filename: rng.Filename,
+		Start:    n.Positions[startOfs],
+		End:      n.Positions[endOfs],
+	}
+}
+
+// SourceDiagnostics updates the subject and context ranges of the given
+// diagnostics, produced by parsing or evaluating the value as a template,
+// to refer to the corresponding source code.
+func (n *stringVal) SourceDiagnostics(diags hcl.Diagnostics) {
+	if n.Positions == nil {
+		return
+	}
+	for _, diag := range diags {
+		if diag.Subject != nil {
+			rng := n.SourceRange(*diag.Subject)
+			diag.Subject = &rng
+		}
+		if diag.Context != nil {
+			rng := n.SourceRange(*diag.Context)
+			diag.Context = &rng
+		}
+	}
+}
+
+// SourceTraversals is like SourceDiagnostics but for the ranges of the steps
+// of the given traversals, returning new traversals with updated ranges.
+func (n *stringVal) SourceTraversals(traversals []hcl.Traversal) []hcl.Traversal {
+	if n.Positions == nil {
+		return traversals
+	}
+	ret := make([]hcl.Traversal, len(traversals))
+	for i, traversal := range traversals {
+		ret[i] = n.sourceTraversal(traversal)
+	}
+	return ret
+}
+
+func (n *stringVal) sourceTraversal(traversal hcl.Traversal) hcl.Traversal {
+	ret := make(hcl.Traversal, len(traversal))
+	for i, step := range traversal {
+		switch ts := step.(type) {
+		case hcl.TraverseRoot:
+			ts.SrcRange = n.SourceRange(ts.SrcRange)
+			ret[i] = ts
+		case hcl.TraverseAttr:
+			ts.SrcRange = n.SourceRange(ts.SrcRange)
+			ret[i] = ts
+		case hcl.TraverseIndex:
+			ts.SrcRange = n.SourceRange(ts.SrcRange)
+			ret[i] = ts
+		case hcl.TraverseSplat:
+			ts.SrcRange = n.SourceRange(ts.SrcRange)
+			ts.Each = n.sourceTraversal(ts.Each)
+			ret[i] = ts
+		default:
+			ret[i] = step
+		}
+	}
+	return ret
+}
+
+// SourceExpression returns a copy of the given expression, produced by
+// parsing the value as a template, with all of its source positions
+// converted to refer to the corresponding source code as for SourceRange.
+func (n *stringVal) SourceExpression(expr hclsyntax.Expression) hclsyntax.Expression {
+	if n.Positions == nil || expr == nil {
+		return expr
+	}
+	return hclsyntax.MapExpressionPositions(expr, n.sourcePos)
+}
+
+func (n *stringVal) sourcePos(pos hcl.Pos) hcl.Pos {
+	ofs := pos.Byte - n.ValueStartPos().Byte
+	if ofs < 0 || ofs >= len(n.Positions) {
+		return pos
+	}
+	return n.Positions[ofs]
+}
+
 type nullVal struct {
 	SrcRange hcl.Range
 }
@@ -105,6 +252,58 @@ func (n *nullVal) StartRange() hcl.Range {
 	return n.SrcRange
 }
 
+// lazyVal is a placeholder for an object or array that has not been parsed
+// yet, produced when parsing in lazy mode. Use resolveNode to obtain the
+// parsed value.
+type lazyVal struct {
+	SrcRange  hcl.Range // range of the entire value, including delimiters
+	OpenRange hcl.Range // range of the opening brace or bracket
+
+	// src is the source code from the start of SrcRange to the end of the
+	// file. We parse only the value, but we keep the source that follows it
+	// so that the parser produces the same diagnostics as in a full parse
+	// for a value that ends in an error.
+	src []byte
+
+	opts ParseOptions // the options for parsing the value
+
+	once  sync.Once
+	val   node
+	diags hcl.Diagnostics
+}
+
+func (n *lazyVal) Range() hcl.Range {
+	return n.SrcRange
+}
+
+func (n *lazyVal) StartRange() hcl.Range {
+	return n.OpenRange
+}
+
+// parse parses the value on first use, and returns the same result on each
+// subsequent call. Any objects and arrays nested in the value are themselves
+// represented by lazyVal nodes.
+func (n *lazyVal) parse() (node, hcl.Diagnostics) {
+	n.once.Do(func() {
+		p := newLazyPeeker(n.src, pos{
+			Filename: n.SrcRange.Filename,
+			Pos:      n.SrcRange.Start,
+		}, n.opts)
+		n.val, n.diags = parseValue(p)
+		n.src = nil // no longer needed
+	})
+	return n.val, n.diags
+}
+
+// resolveNode returns the given node, or the result of parsing it if it is a
+// lazyVal.
+func resolveNode(n node) (node, hcl.Diagnostics) {
+	if lazy, ok := n.(*lazyVal); ok {
+		return lazy.parse()
+	}
+	return n, nil
+}
+
 // invalidVal is used as a placeholder where a value is needed for a valid
 // parse tree but the input was invalid enough to prevent one from being
 // created.
@@ -0,0 +1,45 @@
+package json
+
+import (
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+)
+
+// IsJSONExpression returns true if and only if the given expression is one
+// that has been produced by this package, and is thus a JSON value whose
+// source code can be parsed again using ParseExpressionWithStartPos.
+//
+// Applications can use this to recognize expressions that must be interpreted
+// using the JSON syntax rules, such as when serializing a body that may
+// combine native syntax and JSON syntax files.
+func IsJSONExpression(maybeJSONExpr hcl.Expression) bool {
+	_, ok := maybeJSONExpr.(*expression)
+	return ok
+}
+
+// ParseStringTemplate parses the given expression, which must have been
+// produced by this package, as a native syntax template in the same way as
+// when the expression is evaluated with an EvalContext. The result is nil if
+// the expression is not a JSON string.
+//
+// The source ranges in the returned expression and diagnostics refer to the
+// JSON source, taking into account any escape sequences in the string.
+func ParseStringTemplate(expr hcl.Expression) (hclsyntax.Expression, hcl.Diagnostics) {
+	jsonExpr, ok := expr.(*expression)
+	if !ok {
+		return nil, nil
+	}
+	src, diags := resolveNode(jsonExpr.src)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	v, ok := src.(*stringVal)
+	if !ok {
+		return nil, diags
+	}
+
+	tmpl, tmplDiags := hclsyntax.ParseTemplate([]byte(v.Value), v.SrcRange.Filename, v.ValueStartPos())
+	v.SourceDiagnostics(tmplDiags)
+	diags = append(diags, tmplDiags...)
+	return v.SourceExpression(tmpl), diags
+}
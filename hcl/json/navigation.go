@@ -7,6 +7,10 @@ import (
 
 type navigation struct {
 	root node
+
+	// comments are the comment tokens in the file, if it was parsed in
+	// relaxed mode. See Comments.
+	comments []token
 }
 
 // Implementation of hcled.ContextString
@@ -30,6 +34,9 @@ func (n navigation) ContextString(offset int) string {
 }
 
 func navigationStepsRev(v node, offset int) []string {
+	// Navigation is best-effort, so we ignore any syntax errors in a
+	// lazily-parsed value.
+	v, _ = resolveNode(v)
 	switch tv := v.(type) {
 	case *objectVal:
 		// Do any of our properties have an object that contains the target
@@ -39,7 +46,7 @@ func navigationStepsRev(v node, offset int) []string {
 			av := attr.Value
 
 			switch av.(type) {
-			case *objectVal, *arrayVal:
+			case *objectVal, *arrayVal, *lazyVal:
 				// okay
 			default:
 				continue
@@ -54,7 +61,7 @@ func navigationStepsRev(v node, offset int) []string {
 		for i, elem := range tv.Values {
 
 			switch elem.(type) {
-			case *objectVal, *arrayVal:
+			case *objectVal, *arrayVal, *lazyVal:
 				// okay
 			default:
 				continue
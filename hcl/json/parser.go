@@ -1,6 +1,7 @@
 package json
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 
@@ -9,15 +10,41 @@ import (
 )
 
 func parseFileContent(buf []byte, filename string) (node, hcl.Diagnostics) {
-	tokens := scan(buf, pos{
+	node, _, diags := parseFile(buf, filename, ParseOptions{})
+	return node, diags
+}
+
+// parseFile parses the given buffer as described by the given options,
+// returning the root value along with any comment tokens.
+//
+// In lazy mode, any objects and arrays nested inside the root value are
+// represented by lazyVal nodes, which are parsed only when needed. Syntax
+// errors within a nested value are therefore not reported until that value
+// is parsed, except that mismatched brackets and braces prevent the value
+// from being skipped, in which case the value is parsed immediately.
+func parseFile(buf []byte, filename string, opts ParseOptions) (node, []token, hcl.Diagnostics) {
+	start := pos{
 		Filename: filename,
 		Pos: hcl.Pos{
 			Byte:   0,
 			Line:   1,
 			Column: 1,
 		},
-	})
-	p := newPeeker(tokens)
+	}
+	return parseSource(buf, start, opts)
+}
+
+// parseSource is like parseFile but allows the caller to specify the position
+// of the first byte of the given buffer, for parsing a value that appears
+// somewhere other than at the start of a file.
+func parseSource(buf []byte, start pos, opts ParseOptions) (node, []token, hcl.Diagnostics) {
+	var p *peeker
+	if opts.Lazy {
+		p = newLazyPeeker(buf, start, opts)
+	} else {
+		p = newPeeker(scan(buf, start, opts.Relaxed), opts)
+	}
+
 	node, diags := parseValue(p)
 	if len(diags) == 0 && p.Peek().Type != tokenEOF {
 		diags = diags.Append(&hcl.Diagnostic{
@@ -27,7 +54,22 @@ func parseFileContent(buf []byte, filename string) (node, hcl.Diagnostics) {
 			Subject:  p.Peek().Range.Ptr(),
 		})
 	}
-	return node, diags
+
+	for _, comment := range p.comments {
+		if !bytes.HasPrefix(comment.Bytes, []byte("/*")) {
+			continue
+		}
+		if len(comment.Bytes) < 4 || !bytes.HasSuffix(comment.Bytes, []byte("*/")) {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unterminated comment",
+				Detail:   "There is no closing marker for this block comment.",
+				Subject:  comment.Range.Ptr(),
+			})
+		}
+	}
+
+	return node, p.comments, diags
 }
 
 func parseValue(p *peeker) (node, hcl.Diagnostics) {
@@ -90,6 +132,66 @@ func parseValue(p *peeker) (node, hcl.Diagnostics) {
 	}
 }
 
+// parseNestedValue parses a value that appears inside an object or array.
+//
+// In lazy mode, a nested object or array is skipped and represented by a
+// lazyVal node, unless its delimiters are unbalanced. See parseFile.
+func parseNestedValue(p *peeker) (node, hcl.Diagnostics) {
+	if p.lazy {
+		switch p.Peek().Type {
+		case tokenBraceO, tokenBrackO:
+			if lazy := skipValue(p); lazy != nil {
+				return lazy, nil
+			}
+		}
+	}
+	return parseValue(p)
+}
+
+// skipValue advances the given peeker past the object or array that starts
+// at its next token, without parsing it, and returns a lazyVal node for it.
+//
+// If the value has unbalanced delimiters then skipValue returns nil without
+// advancing the peeker, so that the caller can parse the value immediately
+// and produce the usual diagnostics for the problem.
+func skipValue(p *peeker) node {
+	saved := *p
+	open := p.Read()
+	closers := []tokenType{closerFor(open.Type)}
+	for {
+		tok := p.Read()
+		switch tok.Type {
+		case tokenBraceO, tokenBrackO:
+			closers = append(closers, closerFor(tok.Type))
+		case tokenBraceC, tokenBrackC:
+			if tok.Type != closers[len(closers)-1] {
+				*p = saved
+				return nil
+			}
+			closers = closers[:len(closers)-1]
+			if len(closers) == 0 {
+				rng := hcl.RangeBetween(open.Range, tok.Range)
+				return &lazyVal{
+					src:       p.SourceFrom(rng.Start),
+					SrcRange:  rng,
+					OpenRange: open.Range,
+					opts:      p.opts,
+				}
+			}
+		case tokenEOF, tokenInvalid:
+			*p = saved
+			return nil
+		}
+	}
+}
+
+func closerFor(open tokenType) tokenType {
+	if open == tokenBraceO {
+		return tokenBraceC
+	}
+	return tokenBrackC
+}
+
 func tokenCanStartValue(tok token) bool {
 	switch tok.Type {
 	case tokenBraceO, tokenBrackO, tokenNumber, tokenString, tokenKeyword:
@@ -104,6 +206,7 @@ func parseObject(p *peeker) (node, hcl.Diagnostics) {
 
 	open := p.Read()
 	attrs := []*objectAttr{}
+	attrsByName := map[string]*objectAttr{}
 
 	// recover is used to shift the peeker to what seems to be the end of
 	// our object, so that when we encounter an error we leave the peeker
@@ -185,22 +288,35 @@ Token:
 			})
 		}
 
-		valNode, valDiags := parseValue(p)
+		valNode, valDiags := parseNestedValue(p)
 		diags = diags.Extend(valDiags)
 		if valNode == nil {
 			return nil, diags
 		}
 
-		attrs = append(attrs, &objectAttr{
-			Name:      key,
-			Value:     valNode,
-			NameRange: keyStrNode.SrcRange,
-		})
+		attr := &objectAttr{
+			Name:          key,
+			Value:         valNode,
+			NameRange:     keyStrNode.SrcRange,
+			NamePositions: keyStrNode.Positions,
+		}
+		if prev, exists := attrsByName[key]; exists {
+			attr.PrevDef = prev
+			attr.DupSeverity = hcl.DiagError
+			if p.opts.DuplicateKeyWarnings {
+				attr.DupSeverity = hcl.DiagWarning
+			}
+		}
+		attrsByName[key] = attr
+		attrs = append(attrs, attr)
 
 		switch p.Peek().Type {
 		case tokenComma:
 			comma := p.Read()
 			if p.Peek().Type == tokenBraceC {
+				if p.opts.Relaxed {
+					break Token
+				}
 				// Special error message for this common mistake
 				return nil, diags.Append(&hcl.Diagnostic{
 					Severity: hcl.DiagError,
@@ -285,7 +401,7 @@ Token:
 			break Token
 		}
 
-		valNode, valDiags := parseValue(p)
+		valNode, valDiags := parseNestedValue(p)
 		diags = diags.Extend(valDiags)
 		if valNode == nil {
 			return nil, diags
@@ -297,6 +413,9 @@ Token:
 		case tokenComma:
 			comma := p.Read()
 			if p.Peek().Type == tokenBrackC {
+				if p.opts.Relaxed {
+					break Token
+				}
 				// Special error message for this common mistake
 				return nil, diags.Append(&hcl.Diagnostic{
 					Severity: hcl.DiagError,
@@ -444,10 +563,19 @@ func parseString(p *peeker) (node, hcl.Diagnostics) {
 		}
 	}
 
-	return &stringVal{
+	ret := &stringVal{
 		Value:    str,
 		SrcRange: tok.Range,
-	}, nil
+	}
+	if bytes.IndexByte(tok.Bytes, '\\') >= 0 {
+		// The value isn't a verbatim copy of the source, so we need to
+		// keep track of where each part of it came from in order to
+		// produce accurate source ranges for templates in the value.
+		if decoded, positions := scanStringPositions(tok); decoded == str {
+			ret.Positions = positions
+		}
+	}
+	return ret, nil
 }
 
 func parseKeyword(p *peeker) (node, hcl.Diagnostics) {
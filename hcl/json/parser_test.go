@@ -86,6 +86,7 @@ func TestParse(t *testing.T) {
 					Start: hcl.Pos{Line: 1, Column: 1, Byte: 0},
 					End:   hcl.Pos{Line: 1, Column: 15, Byte: 14},
 				},
+				Positions: singleLinePositions(1, 2, 3, 4, 5, 6, 8, 9, 10, 11, 12, 13),
 			},
 			0,
 		},
@@ -97,6 +98,7 @@ func TestParse(t *testing.T) {
 					Start: hcl.Pos{Line: 1, Column: 1, Byte: 0},
 					End:   hcl.Pos{Line: 1, Column: 18, Byte: 17},
 				},
+				Positions: singleLinePositions(1, 2, 3, 4, 5, 6, 7, 9, 10, 11, 12, 13, 14, 16),
 			},
 			0,
 		},
@@ -108,6 +110,7 @@ func TestParse(t *testing.T) {
 					Start: hcl.Pos{Line: 1, Column: 1, Byte: 0},
 					End:   hcl.Pos{Line: 1, Column: 11, Byte: 10},
 				},
+				Positions: singleLinePositions(1, 2, 3, 4, 5, 6, 7, 9),
 			},
 			0,
 		},
@@ -357,50 +360,55 @@ func TestParse(t *testing.T) {
 		},
 		{
 			`{"hello": true, "hello": true}`,
-			&objectVal{
-				Attrs: []*objectAttr{
-					{
-						Name: "hello",
-						Value: &booleanVal{
-							Value: true,
-							SrcRange: hcl.Range{
-								Start: hcl.Pos{Line: 1, Column: 11, Byte: 10},
-								End:   hcl.Pos{Line: 1, Column: 15, Byte: 14},
-							},
-						},
-						NameRange: hcl.Range{
-							Start: hcl.Pos{Line: 1, Column: 2, Byte: 1},
-							End:   hcl.Pos{Line: 1, Column: 9, Byte: 8},
+			func() node {
+				first := &objectAttr{
+					Name: "hello",
+					Value: &booleanVal{
+						Value: true,
+						SrcRange: hcl.Range{
+							Start: hcl.Pos{Line: 1, Column: 11, Byte: 10},
+							End:   hcl.Pos{Line: 1, Column: 15, Byte: 14},
 						},
 					},
-					{
-						Name: "hello",
-						Value: &booleanVal{
-							Value: true,
-							SrcRange: hcl.Range{
-								Start: hcl.Pos{Line: 1, Column: 26, Byte: 25},
-								End:   hcl.Pos{Line: 1, Column: 30, Byte: 29},
+					NameRange: hcl.Range{
+						Start: hcl.Pos{Line: 1, Column: 2, Byte: 1},
+						End:   hcl.Pos{Line: 1, Column: 9, Byte: 8},
+					},
+				}
+				return &objectVal{
+					Attrs: []*objectAttr{
+						first,
+						{
+							Name: "hello",
+							Value: &booleanVal{
+								Value: true,
+								SrcRange: hcl.Range{
+									Start: hcl.Pos{Line: 1, Column: 26, Byte: 25},
+									End:   hcl.Pos{Line: 1, Column: 30, Byte: 29},
+								},
 							},
-						},
-						NameRange: hcl.Range{
-							Start: hcl.Pos{Line: 1, Column: 17, Byte: 16},
-							End:   hcl.Pos{Line: 1, Column: 24, Byte: 23},
+							NameRange: hcl.Range{
+								Start: hcl.Pos{Line: 1, Column: 17, Byte: 16},
+								End:   hcl.Pos{Line: 1, Column: 24, Byte: 23},
+							},
+							PrevDef:     first,
+							DupSeverity: hcl.DiagError,
 						},
 					},
-				},
-				SrcRange: hcl.Range{
-					Start: hcl.Pos{Line: 1, Column: 1, Byte: 0},
-					End:   hcl.Pos{Line: 1, Column: 31, Byte: 30},
-				},
-				OpenRange: hcl.Range{
-					Start: hcl.Pos{Line: 1, Column: 1, Byte: 0},
-					End:   hcl.Pos{Line: 1, Column: 2, Byte: 1},
-				},
-				CloseRange: hcl.Range{
-					Start: hcl.Pos{Line: 1, Column: 30, Byte: 29},
-					End:   hcl.Pos{Line: 1, Column: 31, Byte: 30},
-				},
-			},
+					SrcRange: hcl.Range{
+						Start: hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:   hcl.Pos{Line: 1, Column: 31, Byte: 30},
+					},
+					OpenRange: hcl.Range{
+						Start: hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:   hcl.Pos{Line: 1, Column: 2, Byte: 1},
+					},
+					CloseRange: hcl.Range{
+						Start: hcl.Pos{Line: 1, Column: 30, Byte: 29},
+						End:   hcl.Pos{Line: 1, Column: 31, Byte: 30},
+					},
+				}
+			}(),
 			0,
 		},
 		{
@@ -623,3 +631,13 @@ func mustBigFloat(s string) *big.Float {
 	}
 	return f
 }
+
+// singleLinePositions returns the positions of the given byte offsets in a
+// source file consisting only of ASCII characters on a single line.
+func singleLinePositions(offsets ...int) []hcl.Pos {
+	ret := make([]hcl.Pos, len(offsets))
+	for i, offset := range offsets {
+		ret[i] = hcl.Pos{Line: 1, Column: offset + 1, Byte: offset}
+	}
+	return ret
+}
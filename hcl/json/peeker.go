@@ -1,25 +1,129 @@
 package json
 
+import (
+	"github.com/hashicorp/hcl2/hcl"
+)
+
 type peeker struct {
 	tokens []token
 	pos    int
+
+	// opts are the options the parser was called with. When parsing the
+	// relaxed dialect, comment tokens are never returned by the peeker, but
+	// are instead collected in comments in the order they are scanned.
+	opts     ParseOptions
+	comments []token
+
+	// If lazy is set then the tokens are scanned on demand from buf rather
+	// than all at once, with next holding the next token, and the parser
+	// skips over nested objects and arrays rather than parsing them.
+	// bufPos is the position of the start of buf, and src is the whole
+	// buffer being parsed, which starts at the position given in srcStart.
+	//
+	// A lazy peeker can be copied to save its state, such as to backtrack
+	// after looking ahead.
+	lazy     bool
+	next     token
+	buf      []byte
+	bufPos   pos
+	src      []byte
+	srcStart pos
 }
 
-func newPeeker(tokens []token) *peeker {
-	return &peeker{
+func newPeeker(tokens []token, opts ParseOptions) *peeker {
+	p := &peeker{
 		tokens: tokens,
 		pos:    0,
+		opts:   opts,
+	}
+	if opts.Relaxed {
+		p.tokens = make([]token, 0, len(tokens))
+		for _, tok := range tokens {
+			if tok.Type == tokenComment {
+				p.comments = append(p.comments, tok)
+				continue
+			}
+			p.tokens = append(p.tokens, tok)
+		}
+	}
+	if last := p.tokens[len(p.tokens)-1]; last.Type == tokenInvalid {
+		// The scanner stops at an invalid byte, so we behave as if the
+		// input ends there, as in lazy mode.
+		p.tokens = append(p.tokens, token{
+			Type: tokenEOF,
+			Range: hcl.Range{
+				Filename: last.Range.Filename,
+				Start:    last.Range.Start,
+				End:      last.Range.Start,
+			},
+		})
+	}
+	return p
+}
+
+// newLazyPeeker creates a peeker that scans tokens from the given buffer only
+// as they are needed, and puts the parser into lazy mode. See ParseLazy.
+func newLazyPeeker(src []byte, start pos, opts ParseOptions) *peeker {
+	p := &peeker{
+		opts:   opts,
+		lazy:   true,
+		buf:    src,
+		bufPos: start,
+
+		src:      src,
+		srcStart: start,
 	}
+	p.scanNext()
+	return p
 }
 
 func (p *peeker) Peek() token {
+	if p.lazy {
+		return p.next
+	}
 	return p.tokens[p.pos]
 }
 
 func (p *peeker) Read() token {
+	if p.lazy {
+		ret := p.next
+		switch ret.Type {
+		case tokenEOF:
+			// Stay at EOF
+		case tokenInvalid:
+			// The scanner can't proceed past an invalid byte, so we
+			// behave as if the input ends here.
+			p.next = token{
+				Type:  tokenEOF,
+				Range: posRange(p.bufPos, p.bufPos),
+			}
+		default:
+			p.scanNext()
+		}
+		return ret
+	}
+
 	ret := p.tokens[p.pos]
 	if ret.Type != tokenEOF {
 		p.pos++
 	}
 	return ret
 }
+
+// scanNext scans the next token from the buffer of a lazy peeker, collecting
+// any comments that precede it.
+func (p *peeker) scanNext() {
+	for {
+		p.next, p.buf, p.bufPos = scanToken(p.buf, p.bufPos, p.opts.Relaxed)
+		if p.next.Type != tokenComment {
+			return
+		}
+		p.comments = append(p.comments, p.next)
+	}
+}
+
+// SourceFrom returns the source bytes from the given position to the end of
+// the buffer of a lazy peeker.
+func (p *peeker) SourceFrom(start hcl.Pos) []byte {
+	return p.src[start.Byte-p.srcStart.Pos.Byte:]
+}
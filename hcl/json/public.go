@@ -18,8 +18,76 @@ import (
 // from its HasErrors method. If HasErrors returns true, the file represents
 // the subset of data that was able to be parsed, which may be none.
 func Parse(src []byte, filename string) (*hcl.File, hcl.Diagnostics) {
-	rootNode, diags := parseFileContent(src, filename)
+	return ParseWithOptions(src, filename, ParseOptions{})
+}
+
+// ParseExpression parses the given buffer as a standalone JSON value and
+// returns it as an expression, interpreted in the same way as the value of
+// an attribute in a JSON-based configuration file.
+func ParseExpression(src []byte, filename string) (hcl.Expression, hcl.Diagnostics) {
+	return ParseExpressionWithStartPos(src, filename, hcl.Pos{Byte: 0, Line: 1, Column: 1})
+}
+
+// ParseExpressionWithStartPos is like ParseExpression but returns source
+// ranges relative to the given start position, for a value that appears
+// somewhere other than at the start of a file.
+func ParseExpressionWithStartPos(src []byte, filename string, start hcl.Pos) (hcl.Expression, hcl.Diagnostics) {
+	node, _, diags := parseSource(src, pos{Filename: filename, Pos: start}, ParseOptions{})
+	return &expression{src: node}, diags
+}
+
+// ParseOptions customizes the behavior of ParseWithOptions. The zero value
+// selects the default behavior of Parse.
+type ParseOptions struct {
+	// Relaxed enables a relaxed dialect of JSON that is easier for humans
+	// to edit, which additionally permits "//" line comments, "/* */" block
+	// comments, and a trailing comma after the last property of an object
+	// or the last element of an array.
+	//
+	// The comments are discarded when interpreting the file, but can be
+	// retrieved from the resulting file using the Comments function.
+	Relaxed bool
+
+	// Lazy defers the parsing of nested values, as described for ParseLazy.
+	Lazy bool
+
+	// DuplicateKeyWarnings downgrades the errors for duplicate property
+	// names within a single JSON object to warnings, for compatibility with
+	// legacy files that rely on only the first definition being used.
+	//
+	// This affects objects interpreted as expressions, as the attributes of
+	// a body, or as the labels of blocks. A block type name may appear more
+	// than once in the same object without any diagnostic, since that is
+	// the usual way to define several blocks of the same type.
+	DuplicateKeyWarnings bool
+}
+
+// ParseWithOptions is like Parse but allows the caller to customize the
+// parser's behavior using the given options.
+func ParseWithOptions(src []byte, filename string, opts ParseOptions) (*hcl.File, hcl.Diagnostics) {
+	rootNode, comments, diags := parseFile(src, filename, opts)
+	return newFile(rootNode, comments, src, filename, diags)
+}
+
+// ParseLazy is like Parse except that it parses only the root value of the
+// given buffer up front, deferring the parsing of each object and array
+// nested inside it until its content is needed.
+//
+// This reduces the time and memory required to load a large file when the
+// calling application needs only some of its content, such as when it
+// requests only some of the block types in the file, or when a file contains
+// large values that are never evaluated.
+//
+// The resulting file behaves the same as one returned by Parse, with the
+// same source ranges, except that syntax errors within a nested value are
+// not detected until that value is needed, and are then returned by the
+// method of the file's body or expression that required it. Callers that
+// need to know that the whole file is valid should use Parse instead.
+func ParseLazy(src []byte, filename string) (*hcl.File, hcl.Diagnostics) {
+	return ParseWithOptions(src, filename, ParseOptions{Lazy: true})
+}
 
+func newFile(rootNode node, comments []token, src []byte, filename string, diags hcl.Diagnostics) (*hcl.File, hcl.Diagnostics) {
 	switch rootNode.(type) {
 	case *objectVal, *arrayVal:
 		// okay
@@ -57,7 +125,10 @@ func Parse(src []byte, filename string) (*hcl.File, hcl.Diagnostics) {
 			val: rootNode,
 		},
 		Bytes: src,
-		Nav:   navigation{rootNode},
+		Nav: navigation{
+			root:     rootNode,
+			comments: comments,
+		},
 	}
 	return file, diags
 }
@@ -67,6 +138,12 @@ func Parse(src []byte, filename string) (*hcl.File, hcl.Diagnostics) {
 //
 // If the file cannot be read, an error diagnostic with nil context is returned.
 func ParseFile(filename string) (*hcl.File, hcl.Diagnostics) {
+	return ParseFileWithOptions(filename, ParseOptions{})
+}
+
+// ParseFileWithOptions is like ParseFile but passes the given options to
+// ParseWithOptions.
+func ParseFileWithOptions(filename string, opts ParseOptions) (*hcl.File, hcl.Diagnostics) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, hcl.Diagnostics{
@@ -90,5 +167,36 @@ func ParseFile(filename string) (*hcl.File, hcl.Diagnostics) {
 		}
 	}
 
-	return Parse(src, filename)
+	return ParseWithOptions(src, filename, opts)
+}
+
+// Comment is a comment in a file written in the relaxed dialect described
+// in ParseOptions.
+type Comment struct {
+	// Bytes is the source code of the whole comment, including its "//",
+	// "/*" or "*/" markers but not the newline that ends a line comment.
+	Bytes []byte
+	Range hcl.Range
+}
+
+// Comments returns the comments in the given file, which must have been
+// returned by one of the parsing functions in this package, in the order
+// they appear in the source. The result is always empty for a file that
+// was not parsed in relaxed mode.
+//
+// Comments within nested values are included even if the file was parsed
+// in lazy mode.
+func Comments(file *hcl.File) []Comment {
+	nav, ok := file.Nav.(navigation)
+	if !ok || len(nav.comments) == 0 {
+		return nil
+	}
+	ret := make([]Comment, len(nav.comments))
+	for i, tok := range nav.comments {
+		ret[i] = Comment{
+			Bytes: tok.Bytes,
+			Range: tok.Range,
+		}
+	}
+	return ret
 }
@@ -1,8 +1,10 @@
 package json
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/go-test/deep"
 	"github.com/hashicorp/hcl2/hcl"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -95,3 +97,315 @@ func TestParseTemplateUnwrap(t *testing.T) {
 		t.Errorf("wrong result %#v; want %#v", val, cty.True)
 	}
 }
+
+func TestParseLazy(t *testing.T) {
+	// ParseLazy should produce a file that behaves identically to one
+	// produced by Parse, so we decode the same source both ways and
+	// compare the results.
+	src := `{
+  "name": "example",
+  "//": "comment",
+  "service": {
+    "web": [
+      {"port": 80, "tags": ["a", "b!"], "listener": {"protocol": "http"}},
+      {"port": "${base + 1}", "listener": [{"protocol": "https"}, {"protocol": "h2"}]}
+    ],
+    "db": {"port": 5432, "settings": {"nested": {"deep": [[1], [2, {"x": true}]]}}}
+  },
+  "ignored": {"huge": [{"a": 1}, {"b": [null]}]}
+}`
+	schema := &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "name"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "service", LabelNames: []string{"name"}},
+		},
+	}
+	serviceSchema := &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "port"},
+			{Name: "tags"},
+			{Name: "settings"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "listener"},
+		},
+	}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"base": cty.NumberIntVal(8000),
+		},
+	}
+
+	// describe flattens the content of the given body into a list of
+	// strings that describe its attributes and blocks along with their
+	// source ranges.
+	var describe func(body hcl.Body, schema *hcl.BodySchema) []string
+	describe = func(body hcl.Body, schema *hcl.BodySchema) []string {
+		content, _, diags := body.PartialContent(schema)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Error())
+		}
+		var ret []string
+		for _, attrS := range schema.Attributes {
+			attr, exists := content.Attributes[attrS.Name]
+			if !exists {
+				continue
+			}
+			val, diags := attr.Expr.Value(ctx)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+			ret = append(ret, fmt.Sprintf("%s = %#v at %s", attr.Name, val, attr.Range))
+		}
+		for _, block := range content.Blocks {
+			ret = append(ret, fmt.Sprintf("%s %q at %s", block.Type, block.Labels, block.DefRange))
+			if block.Type == "service" {
+				ret = append(ret, describe(block.Body, serviceSchema)...)
+			} else {
+				attrs, diags := block.Body.JustAttributes()
+				if diags.HasErrors() {
+					t.Fatalf("unexpected errors: %s", diags.Error())
+				}
+				ret = append(ret, fmt.Sprintf("%d attributes", len(attrs)))
+			}
+		}
+		ret = append(ret, fmt.Sprintf("end at %s", content.MissingItemRange))
+		return ret
+	}
+
+	eagerFile, diags := Parse([]byte(src), "test.json")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors from Parse: %s", diags.Error())
+	}
+	lazyFile, diags := ParseLazy([]byte(src), "test.json")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors from ParseLazy: %s", diags.Error())
+	}
+
+	want := describe(eagerFile.Body, schema)
+	got := describe(lazyFile.Body, schema)
+	for _, problem := range deep.Equal(got, want) {
+		t.Error(problem)
+	}
+
+	// The "ignored" property was never requested, so it should not have
+	// been parsed.
+	root := lazyFile.Body.(*body).val.(*objectVal)
+	ignored := root.Attrs[len(root.Attrs)-1]
+	if lazy, ok := ignored.Value.(*lazyVal); !ok || lazy.val != nil {
+		t.Errorf("unrequested property was parsed: %#v", ignored.Value)
+	}
+
+	for _, offset := range []int{0, 100, 200, 300, len(src) - 5} {
+		got, want := lazyFile.Nav.(navigation).ContextString(offset), eagerFile.Nav.(navigation).ContextString(offset)
+		if got != want {
+			t.Errorf("wrong context string at offset %d\ngot:  %s\nwant: %s", offset, got, want)
+		}
+	}
+}
+
+func TestParseLazy_errors(t *testing.T) {
+	tests := []struct {
+		src       string
+		wantParse bool // true if ParseLazy should report the error
+	}{
+		{`{"a": {"b": 1,}, "c": 1}`, false},
+		{`{"a": [1 2], "c": 1}`, false},
+		{`{"a": {"b": ["c"}}, "c": 1}`, true},
+		{`{"a": {"b": 1}, "c": 1`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			_, wantDiags := Parse([]byte(test.src), "test.json")
+			if !wantDiags.HasErrors() {
+				t.Fatalf("Parse produced no errors")
+			}
+
+			file, diags := ParseLazy([]byte(test.src), "test.json")
+			if test.wantParse {
+				for _, problem := range deep.Equal(diags, wantDiags) {
+					t.Error(problem)
+				}
+				return
+			}
+			if len(diags) != 0 {
+				t.Fatalf("unexpected diagnostics from ParseLazy: %s", diags.Error())
+			}
+
+			// The error should be reported only when the value containing
+			// it is needed.
+			_, diags = file.Body.Content(&hcl.BodySchema{
+				Attributes: []hcl.AttributeSchema{{Name: "c"}},
+			})
+			if len(diags) != 1 || diags[0].Summary != "Extraneous JSON object property" {
+				t.Fatalf("wrong diagnostics from Content: %s", diags.Error())
+			}
+			attrs, diags := file.Body.JustAttributes()
+			if len(diags) != 0 {
+				t.Fatalf("unexpected diagnostics from JustAttributes: %s", diags.Error())
+			}
+			_, diags = attrs["a"].Expr.Value(nil)
+			for _, problem := range deep.Equal(diags, wantDiags) {
+				t.Error(problem)
+			}
+		})
+	}
+}
+
+func TestParseWithOptions_relaxed(t *testing.T) {
+	tests := []struct {
+		src          string
+		wantAttrs    map[string]cty.Value
+		wantComments []string // "bytes at range"
+		wantDiags    []string // summaries
+	}{
+		{
+			`{"a": 1}`,
+			map[string]cty.Value{"a": cty.NumberIntVal(1)},
+			nil,
+			nil,
+		},
+		{
+			"// leading\n{\"a\": 1, // after a\n\"b\": /* inline */ true}\n// trailing",
+			map[string]cty.Value{"a": cty.NumberIntVal(1), "b": cty.True},
+			[]string{
+				"// leading at test.json:1,1-11",
+				"// after a at test.json:2,10-20",
+				"/* inline */ at test.json:3,6-18",
+				"// trailing at test.json:4,1-12",
+			},
+			nil,
+		},
+		{
+			"{\"a\": [1, 2,], \"b\": {\"c\": \"//not a comment\",},}",
+			map[string]cty.Value{
+				"a": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.NumberIntVal(2)}),
+				"b": cty.ObjectVal(map[string]cty.Value{"c": cty.StringVal("//not a comment")}),
+			},
+			nil,
+			nil,
+		},
+		{
+			"{\"a\": {\n  /* multi\n\tline é */ \"b\": 1}}",
+			map[string]cty.Value{
+				"a": cty.ObjectVal(map[string]cty.Value{"b": cty.NumberIntVal(1)}),
+			},
+			[]string{
+				"/* multi\n\tline é */ at test.json:2,3-3,12",
+			},
+			nil,
+		},
+		{
+			`{"a": 1} /* unterminated`,
+			map[string]cty.Value{"a": cty.NumberIntVal(1)},
+			[]string{
+				"/* unterminated at test.json:1,10-25",
+			},
+			[]string{"Unterminated comment"},
+		},
+		{
+			`{"a": 1, /}`,
+			nil,
+			nil,
+			[]string{"Invalid start of value", "Invalid object property name", "Root value must be object"},
+		},
+		{
+			`{"a": 1,,}`,
+			nil,
+			nil,
+			[]string{"Invalid start of value", "Invalid object property name", "Root value must be object"},
+		},
+	}
+
+	for _, lazy := range []bool{false, true} {
+		for _, test := range tests {
+			t.Run(fmt.Sprintf("lazy=%t %s", lazy, test.src), func(t *testing.T) {
+				if test.wantComments != nil || test.src != `{"a": 1}` {
+					// The default strict mode must reject any source that
+					// relies on the relaxed dialect.
+					if _, diags := Parse([]byte(test.src), "test.json"); !diags.HasErrors() {
+						t.Errorf("Parse produced no errors")
+					}
+				}
+
+				file, diags := ParseWithOptions([]byte(test.src), "test.json", ParseOptions{
+					Relaxed: true,
+					Lazy:    lazy,
+				})
+				if test.wantAttrs != nil {
+					attrs, attrDiags := file.Body.JustAttributes()
+					diags = append(diags, attrDiags...)
+					got := map[string]cty.Value{}
+					for name, attr := range attrs {
+						val, valDiags := attr.Expr.Value(nil)
+						diags = append(diags, valDiags...)
+						got[name] = val
+					}
+					if !cty.ObjectVal(got).RawEquals(cty.ObjectVal(test.wantAttrs)) {
+						t.Errorf("wrong attributes\ngot:  %#v\nwant: %#v", got, test.wantAttrs)
+					}
+				}
+
+				var gotDiags []string
+				for _, diag := range diags {
+					gotDiags = append(gotDiags, diag.Summary)
+				}
+				for _, problem := range deep.Equal(gotDiags, test.wantDiags) {
+					t.Errorf("wrong diagnostics: %s\n%s", problem, diags.Error())
+				}
+
+				if test.wantAttrs == nil {
+					return
+				}
+				var gotComments []string
+				for _, comment := range Comments(file) {
+					gotComments = append(gotComments, fmt.Sprintf("%s at %s", comment.Bytes, comment.Range))
+				}
+				for _, problem := range deep.Equal(gotComments, test.wantComments) {
+					t.Errorf("wrong comments: %s", problem)
+				}
+			})
+		}
+	}
+}
+
+func TestParseExpressionWithStartPos(t *testing.T) {
+	src := `{"greeting": "Hello, ${name}!"}`
+	start := hcl.Pos{Line: 3, Column: 10, Byte: 40}
+	expr, diags := ParseExpressionWithStartPos([]byte(src), "test.json", start)
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %s", diags.Error())
+	}
+
+	wantRange := hcl.Range{
+		Filename: "test.json",
+		Start:    start,
+		End:      hcl.Pos{Line: 3, Column: 41, Byte: 71},
+	}
+	if got := expr.Range(); got != wantRange {
+		t.Errorf("wrong range\ngot:  %#v\nwant: %#v", got, wantRange)
+	}
+
+	got, diags := expr.Value(&hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"name": cty.StringVal("Ermintrude"),
+		},
+	})
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics: %s", diags.Error())
+	}
+	want := cty.ObjectVal(map[string]cty.Value{
+		"greeting": cty.StringVal("Hello, Ermintrude!"),
+	})
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	_, diags = ParseExpression([]byte(`{"a": 1} 2`), "test.json")
+	if !diags.HasErrors() {
+		t.Errorf("unexpected success for extraneous data")
+	}
+}
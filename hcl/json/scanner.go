@@ -2,6 +2,9 @@ package json
 
 import (
 	"fmt"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/apparentlymart/go-textseg/textseg"
 	"github.com/hashicorp/hcl2/hcl"
@@ -23,6 +26,7 @@ const (
 	tokenEOF     tokenType = '␄'
 	tokenInvalid tokenType = 0
 	tokenEquals  tokenType = '=' // used only for reminding the user of JSON syntax
+	tokenComment tokenType = 'C' // produced only in relaxed mode
 )
 
 type token struct {
@@ -38,77 +42,92 @@ type token struct {
 // token types keyword, string and number, preferring to capture erroneous
 // extra bytes that we presume the user intended to be part of the token
 // so that we can generate more helpful diagnostics in the parser.
-func scan(buf []byte, start pos) []token {
+//
+// If relaxed is set then the scanner also produces comment tokens, for the
+// relaxed dialect described in ParseOptions.
+func scan(buf []byte, start pos, relaxed bool) []token {
 	var tokens []token
 	p := start
 	for {
-		if len(buf) == 0 {
-			tokens = append(tokens, token{
-				Type:  tokenEOF,
-				Bytes: nil,
-				Range: posRange(p, p),
-			})
+		var tok token
+		tok, buf, p = scanToken(buf, p, relaxed)
+		tokens = append(tokens, tok)
+		switch tok.Type {
+		case tokenEOF, tokenInvalid:
+			// If we've encountered an invalid then we might as well stop
+			// scanning since the parser won't proceed beyond this point.
 			return tokens
 		}
+	}
+}
 
-		buf, p = skipWhitespace(buf, p)
+// scanToken returns the first token in the given buffer, along with the
+// remainder of the buffer and the position where the remainder begins.
+//
+// This is the unit of work for scan, and is also used directly when parsing
+// lazily so that we need not retain tokens for parts of the buffer that we
+// skip over. See scan for details on how the bytes are interpreted.
+func scanToken(buf []byte, p pos, relaxed bool) (token, []byte, pos) {
+	buf, p = skipWhitespace(buf, p)
 
-		if len(buf) == 0 {
-			tokens = append(tokens, token{
-				Type:  tokenEOF,
-				Bytes: nil,
-				Range: posRange(p, p),
-			})
-			return tokens
-		}
+	if len(buf) == 0 {
+		return token{
+			Type:  tokenEOF,
+			Bytes: nil,
+			Range: posRange(p, p),
+		}, buf, p
+	}
 
-		start = p
+	start := p
 
-		first := buf[0]
-		switch {
-		case first == '{' || first == '}' || first == '[' || first == ']' || first == ',' || first == ':' || first == '=':
-			p.Pos.Column++
-			p.Pos.Byte++
-			tokens = append(tokens, token{
-				Type:  tokenType(first),
-				Bytes: buf[0:1],
-				Range: posRange(start, p),
-			})
-			buf = buf[1:]
-		case first == '"':
-			var tokBuf []byte
-			tokBuf, buf, p = scanString(buf, p)
-			tokens = append(tokens, token{
-				Type:  tokenString,
-				Bytes: tokBuf,
-				Range: posRange(start, p),
-			})
-		case byteCanStartNumber(first):
-			var tokBuf []byte
-			tokBuf, buf, p = scanNumber(buf, p)
-			tokens = append(tokens, token{
-				Type:  tokenNumber,
-				Bytes: tokBuf,
-				Range: posRange(start, p),
-			})
-		case byteCanStartKeyword(first):
-			var tokBuf []byte
-			tokBuf, buf, p = scanKeyword(buf, p)
-			tokens = append(tokens, token{
-				Type:  tokenKeyword,
-				Bytes: tokBuf,
-				Range: posRange(start, p),
-			})
-		default:
-			tokens = append(tokens, token{
-				Type:  tokenInvalid,
-				Bytes: buf[:1],
-				Range: start.Range(1, 1),
-			})
-			// If we've encountered an invalid then we might as well stop
-			// scanning since the parser won't proceed beyond this point.
-			return tokens
-		}
+	first := buf[0]
+	switch {
+	case first == '{' || first == '}' || first == '[' || first == ']' || first == ',' || first == ':' || first == '=':
+		p.Pos.Column++
+		p.Pos.Byte++
+		return token{
+			Type:  tokenType(first),
+			Bytes: buf[0:1],
+			Range: posRange(start, p),
+		}, buf[1:], p
+	case first == '"':
+		var tokBuf []byte
+		tokBuf, buf, p = scanString(buf, p)
+		return token{
+			Type:  tokenString,
+			Bytes: tokBuf,
+			Range: posRange(start, p),
+		}, buf, p
+	case byteCanStartNumber(first):
+		var tokBuf []byte
+		tokBuf, buf, p = scanNumber(buf, p)
+		return token{
+			Type:  tokenNumber,
+			Bytes: tokBuf,
+			Range: posRange(start, p),
+		}, buf, p
+	case byteCanStartKeyword(first):
+		var tokBuf []byte
+		tokBuf, buf, p = scanKeyword(buf, p)
+		return token{
+			Type:  tokenKeyword,
+			Bytes: tokBuf,
+			Range: posRange(start, p),
+		}, buf, p
+	case relaxed && first == '/' && len(buf) > 1 && (buf[1] == '/' || buf[1] == '*'):
+		var tokBuf []byte
+		tokBuf, buf, p = scanComment(buf, p)
+		return token{
+			Type:  tokenComment,
+			Bytes: tokBuf,
+			Range: posRange(start, p),
+		}, buf, p
+	default:
+		return token{
+			Type:  tokenInvalid,
+			Bytes: buf[:1],
+			Range: start.Range(1, 1),
+		}, buf, p
 	}
 }
 
@@ -177,6 +196,60 @@ Byte:
 	return buf[:i], buf[i:], p
 }
 
+// scanComment scans either a line comment, which extends up to but not
+// including the end of the line, or a block comment. The scanner doesn't
+// detect unterminated block comments, which extend to the end of the buffer.
+// The parser must check that a block comment token ends with "*/".
+func scanComment(buf []byte, start pos) ([]byte, []byte, pos) {
+	// Skip the two introducer bytes, which we know are single columns.
+	i := 2
+	p := start
+	p.Pos.Byte += 2
+	p.Pos.Column += 2
+
+	if buf[1] == '/' {
+		for i < len(buf) && buf[i] != '\n' && buf[i] != '\r' {
+			advance, _, _ := textseg.ScanGraphemeClusters(buf[i:], true)
+			p.Pos.Byte += advance
+			p.Pos.Column++
+			i += advance
+		}
+		return buf[:i], buf[i:], p
+	}
+
+	for i < len(buf) {
+		if buf[i] == '*' && i+1 < len(buf) && buf[i+1] == '/' {
+			p.Pos.Byte += 2
+			p.Pos.Column += 2
+			i += 2
+			break
+		}
+
+		// We count the whitespace characters in the same way as
+		// skipWhitespace does.
+		switch buf[i] {
+		case '\n':
+			p.Pos.Byte++
+			p.Pos.Column = 1
+			p.Pos.Line++
+			i++
+		case '\r':
+			p.Pos.Byte++
+			i++
+		case '\t':
+			p.Pos.Byte++
+			p.Pos.Column += 2
+			i++
+		default:
+			advance, _, _ := textseg.ScanGraphemeClusters(buf[i:], true)
+			p.Pos.Byte += advance
+			p.Pos.Column++
+			i += advance
+		}
+	}
+	return buf[:i], buf[i:], p
+}
+
 func scanString(buf []byte, start pos) ([]byte, []byte, pos) {
 	// The scanner doesn't validate correct use of escapes, etc. It pays
 	// attention to escapes only for the purpose of identifying the closing
@@ -229,6 +302,113 @@ Byte:
 	return buf[:i], buf[i:], p
 }
 
+// scanStringPositions decodes the given valid string token and returns its
+// value along with the source position of each byte of the value, and a
+// final entry for the position of the closing quote.
+//
+// The result allows mapping positions within the value back to the source,
+// which differ once the string contains any escape sequences. Each byte
+// produced by an escape sequence maps to the start of that sequence.
+// Columns are counted in the same way as in scanString.
+//
+// The caller must already have checked that the token is a valid JSON
+// string, such as by decoding it with the standard library JSON decoder.
+// If the value returned here does not match the standard library's result
+// then the positions must not be used.
+func scanStringPositions(tok token) (string, []hcl.Pos) {
+	buf := tok.Bytes
+	if len(buf) < 2 {
+		return "", nil
+	}
+	buf = buf[:len(buf)-1] // ignore the closing quote
+
+	var ret []byte
+	positions := make([]hcl.Pos, 0, len(buf))
+	p := tok.Range.Start
+	p.Byte++ // skip the opening quote
+	p.Column++
+	i := 1
+	for i < len(buf) {
+		if buf[i] != '\\' {
+			advance, _, _ := textseg.ScanGraphemeClusters(buf[i:], true)
+			for j := 0; j < advance; j++ {
+				pos := p
+				pos.Byte += j
+				positions = append(positions, pos)
+			}
+			ret = append(ret, buf[i:i+advance]...)
+			p.Byte += advance
+			p.Column++
+			i += advance
+			continue
+		}
+
+		var r rune
+		n := 2
+		if i+1 < len(buf) {
+			switch c := buf[i+1]; c {
+			case 'b':
+				r = '\b'
+			case 'f':
+				r = '\f'
+			case 'n':
+				r = '\n'
+			case 'r':
+				r = '\r'
+			case 't':
+				r = '\t'
+			case 'u':
+				r, n = scanStringUnicodeEscape(buf[i:])
+			default:
+				r = rune(c)
+			}
+		}
+
+		var enc [utf8.UTFMax]byte
+		l := utf8.EncodeRune(enc[:], r)
+		for j := 0; j < l; j++ {
+			positions = append(positions, p)
+		}
+		ret = append(ret, enc[:l]...)
+		// Escape sequences are always ASCII, so each byte is a column.
+		p.Byte += n
+		p.Column += n
+		i += n
+	}
+	positions = append(positions, p)
+	return string(ret), positions
+}
+
+// scanStringUnicodeEscape decodes the \u escape sequence at the start of the
+// given buffer, along with a following one if they together represent a
+// UTF-16 surrogate pair, returning the rune and the number of bytes used.
+func scanStringUnicodeEscape(buf []byte) (rune, int) {
+	decode := func(buf []byte) rune {
+		if len(buf) < 6 || buf[0] != '\\' || buf[1] != 'u' {
+			return -1
+		}
+		v, err := strconv.ParseUint(string(buf[2:6]), 16, 16)
+		if err != nil {
+			return -1
+		}
+		return rune(v)
+	}
+
+	r := decode(buf)
+	switch {
+	case r < 0:
+		return utf8.RuneError, 2
+	case !utf16.IsSurrogate(r):
+		return r, 6
+	}
+	if r2 := decode(buf[6:]); r2 >= 0 {
+		if dec := utf16.DecodeRune(r, r2); dec != utf8.RuneError {
+			return dec, 12
+		}
+	}
+	return utf8.RuneError, 6
+}
+
 func skipWhitespace(buf []byte, start pos) ([]byte, pos) {
 	var i int
 	p := start
@@ -844,7 +844,7 @@ func TestScan(t *testing.T) {
 					Column: 1,
 				},
 			}
-			got := scan(buf, start)
+			got := scan(buf, start, false)
 
 			if !reflect.DeepEqual(got, test.Want) {
 				errMsg := &bytes.Buffer{}
@@ -44,8 +44,8 @@ func (b *body) Content(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Diagnostic
 		nameSuggestions = append(nameSuggestions, blockS.Type)
 	}
 
-	jsonAttrs, attrDiags := b.collectDeepAttrs(b.val, nil)
-	diags = append(diags, attrDiags...)
+	// PartialContent already reported any problems with the body's value.
+	jsonAttrs, _ := b.collectDeepAttrs(b.val, nil)
 
 	for _, attr := range jsonAttrs {
 		k := attr.Name
@@ -113,7 +113,7 @@ func (b *body) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Bod
 		if attrS, defined := attrSchemas[attrName]; defined {
 			if existing, exists := content.Attributes[attrName]; exists {
 				diags = append(diags, &hcl.Diagnostic{
-					Severity: hcl.DiagError,
+					Severity: jsonAttr.duplicateSeverity(),
 					Summary:  "Duplicate argument",
 					Detail:   fmt.Sprintf("The argument %q was already set at %s.", attrName, existing.Range),
 					Subject:  &jsonAttr.NameRange,
@@ -170,7 +170,9 @@ func (b *body) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 	attrs := make(map[string]*hcl.Attribute)
 
-	obj, ok := b.val.(*objectVal)
+	val, valDiags := resolveNode(b.val)
+	diags = append(diags, valDiags...)
+	obj, ok := val.(*objectVal)
 	if !ok {
 		diags = append(diags, &hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -195,7 +197,7 @@ func (b *body) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
 
 		if existing, exists := attrs[name]; exists {
 			diags = append(diags, &hcl.Diagnostic{
-				Severity: hcl.DiagError,
+				Severity: jsonAttr.duplicateSeverity(),
 				Summary:  "Duplicate attribute definition",
 				Detail:   fmt.Sprintf("The argument %q was already set at %s.", name, existing.Range),
 				Subject:  &jsonAttr.NameRange,
@@ -217,7 +219,10 @@ func (b *body) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
 }
 
 func (b *body) MissingItemRange() hcl.Range {
-	switch tv := b.val.(type) {
+	// Any problems with a lazily-parsed value are reported by the other
+	// methods, so we can ignore them here.
+	val, _ := resolveNode(b.val)
+	switch tv := val.(type) {
 	case *objectVal:
 		return tv.CloseRange
 	case *arrayVal:
@@ -230,6 +235,11 @@ func (b *body) MissingItemRange() hcl.Range {
 }
 
 func (b *body) unpackBlock(v node, typeName string, typeRange *hcl.Range, labelsLeft []string, labelsUsed []string, labelRanges []hcl.Range, blocks *hcl.Blocks) (diags hcl.Diagnostics) {
+	v, diags = resolveNode(v)
+	if diags.HasErrors() {
+		return
+	}
+
 	if len(labelsLeft) > 0 {
 		labelName := labelsLeft[0]
 		jsonAttrs, attrDiags := b.collectDeepAttrs(v, &labelName)
@@ -248,6 +258,18 @@ func (b *body) unpackBlock(v node, typeName string, typeRange *hcl.Range, labels
 		labelRanges := append(labelRanges, hcl.Range{})
 		for _, p := range jsonAttrs {
 			pk := p.Name
+			if prev := p.PrevDef; prev != nil {
+				// We still unpack the duplicate below, so that every
+				// definition produces a block as the specification
+				// requires, even though we report it like any other
+				// duplicate key.
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: p.DupSeverity,
+					Summary:  "Duplicate block label",
+					Detail:   fmt.Sprintf("A %q block with the %s %q was already defined at %s.", typeName, labelName, pk, prev.NameRange),
+					Subject:  &p.NameRange,
+				})
+			}
 			labelsUsed[len(labelsUsed)-1] = pk
 			labelRanges[len(labelRanges)-1] = p.NameRange
 			diags = append(diags, b.unpackBlock(p.Value, typeName, typeRange, labelsLeft[1:], labelsUsed, labelRanges, blocks)...)
@@ -323,9 +345,13 @@ func (b *body) unpackBlock(v node, typeName string, typeRange *hcl.Range, labels
 // messages to refer to block labels rather than attributes and child blocks.
 // It has no other effect.
 func (b *body) collectDeepAttrs(v node, labelName *string) ([]*objectAttr, hcl.Diagnostics) {
-	var diags hcl.Diagnostics
 	var attrs []*objectAttr
 
+	v, diags := resolveNode(v)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
 	switch tv := v.(type) {
 	case *nullVal:
 		// If a value is null, then we don't return any attributes or return an error.
@@ -335,6 +361,11 @@ func (b *body) collectDeepAttrs(v node, labelName *string) ([]*objectAttr, hcl.D
 
 	case *arrayVal:
 		for _, ev := range tv.Values {
+			ev, evDiags := resolveNode(ev)
+			diags = append(diags, evDiags...)
+			if evDiags.HasErrors() {
+				continue
+			}
 			switch tev := ev.(type) {
 			case *objectVal:
 				attrs = append(attrs, tev.Attrs...)
@@ -379,7 +410,12 @@ func (b *body) collectDeepAttrs(v node, labelName *string) ([]*objectAttr, hcl.D
 }
 
 func (e *expression) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
-	switch v := e.src.(type) {
+	src, diags := resolveNode(e.src)
+	if diags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+
+	switch v := src.(type) {
 	case *stringVal:
 		if ctx != nil {
 			// Parse string contents as a HCL native language expression.
@@ -390,23 +426,15 @@ func (e *expression) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 			expr, diags := hclsyntax.ParseTemplate(
 				[]byte(templateSrc),
 				v.SrcRange.Filename,
-
-				// This won't produce _exactly_ the right result, since
-				// the hclsyntax parser can't "see" any escapes we removed
-				// while parsing JSON, but it's better than nothing.
-				hcl.Pos{
-					Line: v.SrcRange.Start.Line,
-
-					// skip over the opening quote mark
-					Byte:   v.SrcRange.Start.Byte + 1,
-					Column: v.SrcRange.Start.Column + 1,
-				},
+				v.ValueStartPos(),
 			)
 			if diags.HasErrors() {
+				v.SourceDiagnostics(diags)
 				return cty.DynamicVal, diags
 			}
 			val, evalDiags := expr.Value(ctx)
 			diags = append(diags, evalDiags...)
+			v.SourceDiagnostics(diags)
 			return val, diags
 		}
 
@@ -416,7 +444,6 @@ func (e *expression) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 	case *booleanVal:
 		return cty.BoolVal(v.Value), nil
 	case *arrayVal:
-		var diags hcl.Diagnostics
 		vals := []cty.Value{}
 		for _, jsonVal := range v.Values {
 			val, valDiags := (&expression{src: jsonVal}).Value(ctx)
@@ -425,20 +452,35 @@ func (e *expression) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 		}
 		return cty.TupleVal(vals), diags
 	case *objectVal:
-		var diags hcl.Diagnostics
 		attrs := map[string]cty.Value{}
 		attrRanges := map[string]hcl.Range{}
 		known := true
 		for _, jsonAttr := range v.Attrs {
+			if prev := jsonAttr.PrevDef; prev != nil {
+				// The same key always produces the same name, so we can
+				// report this without evaluating the key. The value is
+				// discarded, but we still evaluate it so that any problems
+				// with it are reported, which matters most when the
+				// duplicate itself is only a warning.
+				_, valDiags := (&expression{src: jsonAttr.Value}).Value(ctx)
+				diags = append(diags, valDiags...)
+				diags = append(diags, &hcl.Diagnostic{
+					Severity:    jsonAttr.DupSeverity,
+					Summary:     "Duplicate object attribute",
+					Detail:      fmt.Sprintf("An attribute named %q was already defined at %s.", jsonAttr.Name, prev.NameRange),
+					Subject:     &jsonAttr.NameRange,
+					Expression:  e,
+					EvalContext: ctx,
+				})
+				continue
+			}
+
 			// In this one context we allow keys to contain interpolation
 			// expressions too, assuming we're evaluating in interpolation
 			// mode. This achieves parity with the native syntax where
 			// object expressions can have dynamic keys, while block contents
 			// may not.
-			name, nameDiags := (&expression{src: &stringVal{
-				Value:    jsonAttr.Name,
-				SrcRange: jsonAttr.NameRange,
-			}}).Value(ctx)
+			name, nameDiags := (&expression{src: jsonAttr.NameVal()}).Value(ctx)
 			valExpr := &expression{src: jsonAttr.Value}
 			val, valDiags := valExpr.Value(ctx)
 			diags = append(diags, nameDiags...)
@@ -513,28 +555,20 @@ func (e *expression) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
 func (e *expression) Variables() []hcl.Traversal {
 	var vars []hcl.Traversal
 
-	switch v := e.src.(type) {
+	// Any syntax errors in a lazily-parsed value are reported by Value.
+	src, _ := resolveNode(e.src)
+	switch v := src.(type) {
 	case *stringVal:
 		templateSrc := v.Value
 		expr, diags := hclsyntax.ParseTemplate(
 			[]byte(templateSrc),
 			v.SrcRange.Filename,
-
-			// This won't produce _exactly_ the right result, since
-			// the hclsyntax parser can't "see" any escapes we removed
-			// while parsing JSON, but it's better than nothing.
-			hcl.Pos{
-				Line: v.SrcRange.Start.Line,
-
-				// skip over the opening quote mark
-				Byte:   v.SrcRange.Start.Byte + 1,
-				Column: v.SrcRange.Start.Column + 1,
-			},
+			v.ValueStartPos(),
 		)
 		if diags.HasErrors() {
 			return vars
 		}
-		return expr.Variables()
+		return v.SourceTraversals(expr.Variables())
 
 	case *arrayVal:
 		for _, jsonVal := range v.Values {
@@ -542,10 +576,7 @@ func (e *expression) Variables() []hcl.Traversal {
 		}
 	case *objectVal:
 		for _, jsonAttr := range v.Attrs {
-			keyExpr := &stringVal{ // we're going to treat key as an expression in this context
-				Value:    jsonAttr.Name,
-				SrcRange: jsonAttr.NameRange,
-			}
+			keyExpr := jsonAttr.NameVal() // we're going to treat key as an expression in this context
 			vars = append(vars, (&expression{src: keyExpr}).Variables()...)
 			vars = append(vars, (&expression{src: jsonAttr.Value}).Variables()...)
 		}
@@ -604,7 +635,12 @@ func (e *expression) ExprCall() *hcl.StaticCall {
 
 // Implementation for hcl.ExprList.
 func (e *expression) ExprList() []hcl.Expression {
-	switch v := e.src.(type) {
+	src, diags := resolveNode(e.src)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	switch v := src.(type) {
 	case *arrayVal:
 		ret := make([]hcl.Expression, len(v.Values))
 		for i, node := range v.Values {
@@ -618,15 +654,17 @@ func (e *expression) ExprList() []hcl.Expression {
 
 // Implementation for hcl.ExprMap.
 func (e *expression) ExprMap() []hcl.KeyValuePair {
-	switch v := e.src.(type) {
+	src, diags := resolveNode(e.src)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	switch v := src.(type) {
 	case *objectVal:
 		ret := make([]hcl.KeyValuePair, len(v.Attrs))
 		for i, jsonAttr := range v.Attrs {
 			ret[i] = hcl.KeyValuePair{
-				Key: &expression{src: &stringVal{
-					Value:    jsonAttr.Name,
-					SrcRange: jsonAttr.NameRange,
-				}},
+				Key:   &expression{src: jsonAttr.NameVal()},
 				Value: &expression{src: jsonAttr.Value},
 			}
 		}
@@ -1303,6 +1303,89 @@ func TestExpressionVariables(t *testing.T) {
 				},
 			},
 		},
+		{
+			`{"a":"\n${foo}"}`,
+			[]hcl.Traversal{
+				{
+					hcl.TraverseRoot{
+						Name: "foo",
+						SrcRange: hcl.Range{
+							Filename: "test.json",
+							Start:    hcl.Pos{Line: 1, Column: 11, Byte: 10},
+							End:      hcl.Pos{Line: 1, Column: 14, Byte: 13},
+						},
+					},
+				},
+			},
+		},
+		{
+			`{"a":"\u00e9${foo.bar}"}`,
+			[]hcl.Traversal{
+				{
+					hcl.TraverseRoot{
+						Name: "foo",
+						SrcRange: hcl.Range{
+							Filename: "test.json",
+							Start:    hcl.Pos{Line: 1, Column: 15, Byte: 14},
+							End:      hcl.Pos{Line: 1, Column: 18, Byte: 17},
+						},
+					},
+					hcl.TraverseAttr{
+						Name: "bar",
+						SrcRange: hcl.Range{
+							Filename: "test.json",
+							Start:    hcl.Pos{Line: 1, Column: 18, Byte: 17},
+							End:      hcl.Pos{Line: 1, Column: 22, Byte: 21},
+						},
+					},
+				},
+			},
+		},
+		{
+			`{"a":"\ud83d\ude00${foo}"}`,
+			[]hcl.Traversal{
+				{
+					hcl.TraverseRoot{
+						Name: "foo",
+						SrcRange: hcl.Range{
+							Filename: "test.json",
+							Start:    hcl.Pos{Line: 1, Column: 21, Byte: 20},
+							End:      hcl.Pos{Line: 1, Column: 24, Byte: 23},
+						},
+					},
+				},
+			},
+		},
+		{
+			`{"a":"é\"${foo}"}`,
+			[]hcl.Traversal{
+				{
+					hcl.TraverseRoot{
+						Name: "foo",
+						SrcRange: hcl.Range{
+							Filename: "test.json",
+							Start:    hcl.Pos{Line: 1, Column: 12, Byte: 12},
+							End:      hcl.Pos{Line: 1, Column: 15, Byte: 15},
+						},
+					},
+				},
+			},
+		},
+		{
+			`{"a":{"\t${foo}":"b"}}`,
+			[]hcl.Traversal{
+				{
+					hcl.TraverseRoot{
+						Name: "foo",
+						SrcRange: hcl.Range{
+							Filename: "test.json",
+							Start:    hcl.Pos{Line: 1, Column: 12, Byte: 11},
+							End:      hcl.Pos{Line: 1, Column: 15, Byte: 14},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -1354,6 +1437,37 @@ func TestStaticExpressionList(t *testing.T) {
 	}
 }
 
+func TestParseStringTemplate(t *testing.T) {
+	// The escape sequence is six bytes in the source but only one in the
+	// string's value, so the ranges in the template must be mapped back.
+	src := `{"a": "\u0041${foo}"}`
+	file, diags := Parse([]byte(src), "test.json")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	tmpl, diags := ParseStringTemplate(attrs["a"].Expr)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	if got, want := string(tmpl.Range().SliceBytes([]byte(src))), `\u0041${foo}`; got != want {
+		t.Errorf("template range covers %q; want %q", got, want)
+	}
+	traversal := tmpl.Variables()[0]
+	if got, want := string(traversal.SourceRange().SliceBytes([]byte(src))), "foo"; got != want {
+		t.Errorf("traversal range covers %q; want %q", got, want)
+	}
+
+	tmpl, diags = ParseStringTemplate(&expression{src: &numberVal{}})
+	if tmpl != nil || len(diags) != 0 {
+		t.Errorf("unexpected result for a number %#v; want nil", tmpl)
+	}
+}
+
 func TestExpression_Value(t *testing.T) {
 	src := `{
   "string": "string_val",
@@ -1461,6 +1575,24 @@ func TestExpressionValue_Diags(t *testing.T) {
 			expected: cty.DynamicVal,
 			error:    "Unknown variable",
 		},
+		{
+			name:     "string: unhappy after escapes",
+			src:      `{"v": "\"${UNKNOWN}\""}`,
+			expected: cty.UnknownVal(cty.String),
+			error:    ":1,12-19: Unknown variable",
+		},
+		{
+			name:     "string: invalid template after escapes",
+			src:      `{"v": "\u00e9\t${1 +}"}`,
+			expected: cty.DynamicVal,
+			error:    ":1,21-22: Invalid expression",
+		},
+		{
+			name:     "object_key: unhappy after escapes",
+			src:      `{"v": {"\n${UNKNOWN}": "val"}}`,
+			expected: cty.DynamicVal,
+			error:    ":1,13-20: Unknown variable",
+		},
 		{
 			name:     "array: happy",
 			src:      `{"v": ["happy ${VAR1}"]}`,
@@ -1529,3 +1661,175 @@ func TestExpressionValue_Diags(t *testing.T) {
 	}
 
 }
+
+func TestDuplicateKeys(t *testing.T) {
+	schema := &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "a"},
+			{Name: "v"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "b"},
+			{Type: "r", LabelNames: []string{"name"}},
+		},
+	}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"k": cty.StringVal("key"),
+		},
+	}
+
+	tests := []struct {
+		src        string
+		wantVal    cty.Value // value of attribute "v", if any
+		wantBlocks int
+		wantDiags  []string // "severity summary at range"
+	}{
+		{
+			`{"a": 1, "v": {"x": 1, "y": 2}}`,
+			cty.ObjectVal(map[string]cty.Value{
+				"x": cty.NumberIntVal(1),
+				"y": cty.NumberIntVal(2),
+			}),
+			0,
+			nil,
+		},
+		{
+			`{"v": {"x": 1, "x": 2}}`,
+			cty.ObjectVal(map[string]cty.Value{
+				"x": cty.NumberIntVal(1),
+			}),
+			0,
+			[]string{
+				`%s Duplicate object attribute at test.json:1,16-19: An attribute named "x" was already defined at test.json:1,8-11.`,
+			},
+		},
+		{
+			`{"v": {"${k}": 1, "${k}": {"y": 2, "y": 3}}}`,
+			cty.ObjectVal(map[string]cty.Value{
+				"key": cty.NumberIntVal(1),
+			}),
+			0,
+			[]string{
+				`%s Duplicate object attribute at test.json:1,36-39: An attribute named "y" was already defined at test.json:1,28-31.`,
+				`%s Duplicate object attribute at test.json:1,19-25: An attribute named "${k}" was already defined at test.json:1,8-14.`,
+			},
+		},
+		{
+			// The value of a duplicate is discarded, but problems with it
+			// must still be reported.
+			`{"v": {"x": 1, "x": "${nope}"}}`,
+			cty.ObjectVal(map[string]cty.Value{
+				"x": cty.NumberIntVal(1),
+			}),
+			0,
+			[]string{
+				`error Unknown variable at test.json:1,24-28: There is no variable named "nope".`,
+				`%s Duplicate object attribute at test.json:1,16-19: An attribute named "x" was already defined at test.json:1,8-11.`,
+			},
+		},
+		{
+			`{"v": [{"y": 2, "y": 3}]}`,
+			cty.TupleVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"y": cty.NumberIntVal(2),
+				}),
+			}),
+			0,
+			[]string{
+				`%s Duplicate object attribute at test.json:1,17-20: An attribute named "y" was already defined at test.json:1,9-12.`,
+			},
+		},
+		{
+			`{"a": 1, "a": 2}`,
+			cty.NilVal,
+			0,
+			[]string{
+				`%s Duplicate argument at test.json:1,10-13: The argument "a" was already set at test.json:1,2-8.`,
+			},
+		},
+		{
+			// Attributes defined in separate objects are not duplicate
+			// keys, so these are errors regardless of the options.
+			`[{"a": 1}, {"a": 2}]`,
+			cty.NilVal,
+			0,
+			[]string{
+				`error Duplicate argument at test.json:1,13-16: The argument "a" was already set at test.json:1,3-9.`,
+			},
+		},
+		{
+			// Block types may be repeated to define multiple blocks.
+			`{"b": {}, "b": {}}`,
+			cty.NilVal,
+			2,
+			nil,
+		},
+		{
+			// Repeated labels are reported, but every definition still
+			// produces a block.
+			`{"r": {"x": {}, "x": {}}}`,
+			cty.NilVal,
+			2,
+			[]string{
+				`%s Duplicate block label at test.json:1,17-20: A "r" block with the name "x" was already defined at test.json:1,8-11.`,
+			},
+		},
+		{
+			// Labels in separate objects are not duplicate keys.
+			`{"r": [{"x": {}}, {"x": {}}]}`,
+			cty.NilVal,
+			2,
+			nil,
+		},
+	}
+
+	for _, warnings := range []bool{false, true} {
+		severity := "error"
+		if warnings {
+			severity = "warning"
+		}
+
+		for _, test := range tests {
+			t.Run(fmt.Sprintf("%s %s", severity, test.src), func(t *testing.T) {
+				file, diags := ParseWithOptions([]byte(test.src), "test.json", ParseOptions{
+					DuplicateKeyWarnings: warnings,
+				})
+				if len(diags) != 0 {
+					t.Fatalf("unexpected diagnostics from parse: %s", diags.Error())
+				}
+
+				content, diags := file.Body.Content(schema)
+				if attr := content.Attributes["v"]; attr != nil {
+					val, valDiags := attr.Expr.Value(ctx)
+					diags = append(diags, valDiags...)
+					if !val.RawEquals(test.wantVal) {
+						t.Errorf("wrong value\ngot:  %#v\nwant: %#v", val, test.wantVal)
+					}
+				}
+				if got := len(content.Blocks); got != test.wantBlocks {
+					t.Errorf("wrong number of blocks %d; want %d", got, test.wantBlocks)
+				}
+
+				var gotDiags []string
+				for _, diag := range diags {
+					sev := "error"
+					if diag.Severity == hcl.DiagWarning {
+						sev = "warning"
+					}
+					gotDiags = append(gotDiags, fmt.Sprintf("%s %s at %s: %s", sev, diag.Summary, diag.Subject, diag.Detail))
+				}
+				var wantDiags []string
+				for _, want := range test.wantDiags {
+					if strings.Contains(want, "%s") {
+						want = fmt.Sprintf(want, severity)
+					}
+					wantDiags = append(wantDiags, want)
+				}
+				for _, problem := range deep.Equal(gotDiags, wantDiags) {
+					t.Error(problem)
+				}
+			})
+		}
+	}
+}
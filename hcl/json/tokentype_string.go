@@ -4,21 +4,22 @@ package json
 
 import "strconv"
 
-const _tokenType_name = "tokenInvalidtokenCommatokenColontokenEqualstokenKeywordtokenNumbertokenStringtokenBrackOtokenBrackCtokenBraceOtokenBraceCtokenEOF"
+const _tokenType_name = "tokenInvalidtokenCommatokenColontokenEqualstokenCommenttokenKeywordtokenNumbertokenStringtokenBrackOtokenBrackCtokenBraceOtokenBraceCtokenEOF"
 
 var _tokenType_map = map[tokenType]string{
 	0:    _tokenType_name[0:12],
 	44:   _tokenType_name[12:22],
 	58:   _tokenType_name[22:32],
 	61:   _tokenType_name[32:43],
-	75:   _tokenType_name[43:55],
-	78:   _tokenType_name[55:66],
-	83:   _tokenType_name[66:77],
-	91:   _tokenType_name[77:88],
-	93:   _tokenType_name[88:99],
-	123:  _tokenType_name[99:110],
-	125:  _tokenType_name[110:121],
-	9220: _tokenType_name[121:129],
+	67:   _tokenType_name[43:55],
+	75:   _tokenType_name[55:67],
+	78:   _tokenType_name[67:78],
+	83:   _tokenType_name[78:89],
+	91:   _tokenType_name[89:100],
+	93:   _tokenType_name[100:111],
+	123:  _tokenType_name[111:122],
+	125:  _tokenType_name[122:133],
+	9220: _tokenType_name[133:141],
 }
 
 func (i tokenType) String() string {
@@ -0,0 +1,195 @@
+package hcl
+
+// Provenance describes the sources that contributed to the result of
+// evaluating an expression.
+type Provenance struct {
+	// Ranges are the source ranges of the literal values and variable
+	// references that contributed to the result, along with any ranges
+	// given for the referenced variables using SetVariableProvenance.
+	Ranges []Range
+
+	// Traversals are the references to variables in the evaluation context
+	// whose values contributed to the result, along with any traversals
+	// given for the referenced variables using SetVariableProvenance.
+	// References to the local symbols of expressions, such as the
+	// iterator symbols of a "for" expression, are not included, but the
+	// sources of the values they were derived from are.
+	Traversals []Traversal
+}
+
+// add merges the sources from the given provenance into the receiver,
+// ignoring any that are already present.
+func (p *Provenance) add(other Provenance) {
+	for _, rng := range other.Ranges {
+		p.addRange(rng)
+	}
+	for _, traversal := range other.Traversals {
+		p.addTraversal(traversal)
+	}
+}
+
+func (p *Provenance) addRange(rng Range) {
+	for _, existing := range p.Ranges {
+		if existing == rng {
+			return
+		}
+	}
+	p.Ranges = append(p.Ranges, rng)
+}
+
+func (p *Provenance) addTraversal(traversal Traversal) {
+	rng := traversal.SourceRange()
+	for _, existing := range p.Traversals {
+		if len(existing) == len(traversal) && existing.SourceRange() == rng {
+			return
+		}
+	}
+	p.Traversals = append(p.Traversals, traversal)
+}
+
+// ProvenanceTracker records the provenance of the results of expressions
+// evaluated in an EvalContext whose Provenance field refers to it, or in any
+// descendent of such a context.
+//
+// A tracker may be shared across many evaluations, but it is not safe for
+// concurrent use.
+type ProvenanceTracker struct {
+	exprs  map[Expression]Provenance
+	vars   map[*EvalContext]map[string]trackedVariable
+	frames []*Provenance
+}
+
+type trackedVariable struct {
+	prov  Provenance
+	local bool
+}
+
+// NewProvenanceTracker returns a new, empty ProvenanceTracker.
+func NewProvenanceTracker() *ProvenanceTracker {
+	return &ProvenanceTracker{
+		exprs: map[Expression]Provenance{},
+		vars:  map[*EvalContext]map[string]trackedVariable{},
+	}
+}
+
+// ProvenanceTracker returns the tracker that records the provenance of the
+// results of expressions evaluated in the receiving context, or nil if
+// provenance tracking is not enabled for it.
+func (ctx *EvalContext) ProvenanceTracker() *ProvenanceTracker {
+	for thisCtx := ctx; thisCtx != nil; thisCtx = thisCtx.parent {
+		if thisCtx.Provenance != nil {
+			return thisCtx.Provenance
+		}
+	}
+	return nil
+}
+
+// Provenance returns the provenance of the result of the most recent
+// evaluation of the given expression using the receiving tracker.
+//
+// An expression that is evaluated more than once as part of evaluating
+// another expression, such as the value expression of a "for" expression,
+// reports the provenance of only its last evaluation, but the result of the
+// enclosing expression includes the sources of all of them.
+func (t *ProvenanceTracker) Provenance(expr Expression) Provenance {
+	if t == nil {
+		return Provenance{}
+	}
+	return t.exprs[expr]
+}
+
+// SetVariableProvenance records that the value of the variable with the
+// given name in the given context was derived from the given sources,
+// which will then be included in the provenance of any result that refers
+// to the variable, alongside the reference itself.
+//
+// This allows an application that evaluates expressions in terms of the
+// results of other expressions to report the original sources of a value.
+func (t *ProvenanceTracker) SetVariableProvenance(ctx *EvalContext, name string, prov Provenance) {
+	t.setVariable(ctx, name, trackedVariable{prov: prov})
+}
+
+// SetLocalProvenance is like SetVariableProvenance but for use by
+// expression implementations that define local symbols in a child
+// context, such as the iterator symbols of a "for" expression. References
+// to a local symbol contribute only the sources of its value, and not the
+// reference itself.
+func (t *ProvenanceTracker) SetLocalProvenance(ctx *EvalContext, name string, prov Provenance) {
+	t.setVariable(ctx, name, trackedVariable{prov: prov, local: true})
+}
+
+func (t *ProvenanceTracker) setVariable(ctx *EvalContext, name string, v trackedVariable) {
+	if t == nil {
+		return
+	}
+	if t.vars[ctx] == nil {
+		t.vars[ctx] = map[string]trackedVariable{}
+	}
+	t.vars[ctx][name] = v
+}
+
+// BeginExpr and EndExpr are for use by expression implementations, which
+// must call BeginExpr before evaluating an expression and EndExpr once it
+// has been evaluated. The provenance of the result is the sources recorded
+// in between, including those of any nested expressions, and is also
+// included in the provenance of any enclosing expression.
+//
+// It is safe to call the methods of a nil tracker, which do nothing.
+func (t *ProvenanceTracker) BeginExpr() {
+	if t == nil {
+		return
+	}
+	t.frames = append(t.frames, &Provenance{})
+}
+
+// EndExpr completes the recording started by the most recent call to
+// BeginExpr. See BeginExpr for details.
+func (t *ProvenanceTracker) EndExpr(expr Expression) {
+	if t == nil || len(t.frames) == 0 {
+		return
+	}
+	prov := *t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	t.exprs[expr] = prov
+	if len(t.frames) > 0 {
+		t.frames[len(t.frames)-1].add(prov)
+	}
+}
+
+// AddRange is for use by expression implementations, to record that the
+// source at the given range contributes to the result of the expression
+// currently being evaluated.
+func (t *ProvenanceTracker) AddRange(rng Range) {
+	if t == nil || len(t.frames) == 0 {
+		return
+	}
+	t.frames[len(t.frames)-1].addRange(rng)
+}
+
+// AddTraversal is for use by expression implementations, to record that the
+// value the given absolute traversal refers to in the given context
+// contributes to the result of the expression currently being evaluated.
+func (t *ProvenanceTracker) AddTraversal(ctx *EvalContext, traversal Traversal) {
+	if t == nil || len(t.frames) == 0 || len(traversal) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+
+	root, ok := traversal[0].(TraverseRoot)
+	if ok {
+		for thisCtx := ctx; thisCtx != nil; thisCtx = thisCtx.parent {
+			if _, defined := thisCtx.Variables[root.Name]; !defined {
+				continue
+			}
+			if v, tracked := t.vars[thisCtx][root.Name]; tracked {
+				frame.add(v.prov)
+				if v.local {
+					return
+				}
+			}
+			break
+		}
+	}
+	frame.addRange(traversal.SourceRange())
+	frame.addTraversal(traversal)
+}
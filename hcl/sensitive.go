@@ -0,0 +1,111 @@
+package hcl
+
+import (
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// IsSensitive returns true if the value that the given absolute traversal
+// refers to in the receiving context is marked as sensitive, or contains
+// any parts that are marked as sensitive.
+//
+// The marks are taken from the context that defines the traversal's root
+// variable, so a variable in a child context that shadows a sensitive
+// variable of the same name in a parent is not itself sensitive unless it
+// is marked in the child.
+func (ctx *EvalContext) IsSensitive(traversal Traversal) bool {
+	if len(traversal) == 0 {
+		return false
+	}
+	root, ok := traversal[0].(TraverseRoot)
+	if !ok {
+		return false
+	}
+
+	for thisCtx := ctx; thisCtx != nil; thisCtx = thisCtx.parent {
+		if _, defined := thisCtx.Variables[root.Name]; !defined {
+			continue
+		}
+		for _, marked := range thisCtx.Sensitive {
+			if traversalsOverlap(traversal, marked) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// ExprSensitive returns true if the result of evaluating the given expression
+// in the given context might contain any sensitive values, because it
+// refers to a variable that is wholly or partially marked as sensitive in
+// the context.
+//
+// This is a conservative result: any reference to a value that contains a
+// sensitive part makes the whole result sensitive, regardless of how the
+// expression uses that value.
+func ExprSensitive(expr Expression, ctx *EvalContext) bool {
+	return TraversalsSensitive(expr.Variables(), ctx)
+}
+
+// TraversalsSensitive is like ExprSensitive but works with a set of
+// traversals, such as the result of hcldec.Variables, allowing a caller to
+// determine whether a value decoded from a body contains any sensitive
+// parts.
+func TraversalsSensitive(traversals []Traversal, ctx *EvalContext) bool {
+	for _, traversal := range traversals {
+		if ctx.IsSensitive(traversal) {
+			return true
+		}
+	}
+	return false
+}
+
+// traversalsOverlap returns true if one of the given traversals is a prefix
+// of the other, and so the value that one refers to contains the value that
+// the other refers to.
+//
+// Steps whose keys can't be compared, such as splat steps or unknown index
+// keys, are assumed to match.
+func traversalsOverlap(a, b Traversal) bool {
+	l := len(a)
+	if len(b) < l {
+		l = len(b)
+	}
+	for i := 0; i < l; i++ {
+		aKey, aOk := traverserKey(a[i])
+		bKey, bOk := traverserKey(b[i])
+		if !aOk || !bOk {
+			continue
+		}
+		if !aKey.RawEquals(bKey) {
+			return false
+		}
+	}
+	return true
+}
+
+// traverserKey returns a string representation of the key that the given
+// traversal step looks up, or false if the step doesn't select a
+// particular key.
+func traverserKey(step Traverser) (cty.Value, bool) {
+	switch ts := step.(type) {
+	case TraverseRoot:
+		return cty.StringVal(ts.Name), true
+	case TraverseAttr:
+		return cty.StringVal(ts.Name), true
+	case TraverseIndex:
+		if !ts.Key.IsKnown() || ts.Key.IsNull() {
+			return cty.NilVal, false
+		}
+		// Attribute names and index keys that convert to the same string
+		// select the same element, so we compare them as strings.
+		key, err := convert.Convert(ts.Key, cty.String)
+		if err != nil {
+			return cty.NilVal, false
+		}
+		return key, true
+	default:
+		return cty.NilVal, false
+	}
+}
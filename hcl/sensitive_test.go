@@ -0,0 +1,88 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestEvalContextIsSensitive(t *testing.T) {
+	parent := &EvalContext{
+		Variables: map[string]cty.Value{
+			"secret":   cty.StringVal("hunter2"),
+			"shadowed": cty.StringVal("hunter2"),
+			"obj": cty.ObjectVal(map[string]cty.Value{
+				"public":  cty.StringVal("hello"),
+				"private": cty.StringVal("hunter2"),
+			}),
+			"list": cty.ListVal([]cty.Value{
+				cty.StringVal("hello"),
+				cty.StringVal("hunter2"),
+			}),
+		},
+		Sensitive: []Traversal{
+			{TraverseRoot{Name: "secret"}},
+			{TraverseRoot{Name: "shadowed"}},
+			{TraverseRoot{Name: "obj"}, TraverseAttr{Name: "private"}},
+			{TraverseRoot{Name: "list"}, TraverseIndex{Key: cty.NumberIntVal(1)}},
+		},
+	}
+	child := parent.NewChild()
+	child.Variables = map[string]cty.Value{
+		"shadowed": cty.StringVal("not secret"),
+	}
+
+	tests := []struct {
+		traversal Traversal
+		want      bool
+	}{
+		{
+			Traversal{TraverseRoot{Name: "secret"}},
+			true,
+		},
+		{
+			Traversal{TraverseRoot{Name: "secret"}, TraverseAttr{Name: "whatever"}},
+			true,
+		},
+		{
+			Traversal{TraverseRoot{Name: "shadowed"}},
+			false,
+		},
+		{
+			Traversal{TraverseRoot{Name: "obj"}},
+			true, // contains a sensitive attribute
+		},
+		{
+			Traversal{TraverseRoot{Name: "obj"}, TraverseAttr{Name: "public"}},
+			false,
+		},
+		{
+			Traversal{TraverseRoot{Name: "obj"}, TraverseIndex{Key: cty.StringVal("private")}},
+			true,
+		},
+		{
+			Traversal{TraverseRoot{Name: "list"}, TraverseIndex{Key: cty.NumberIntVal(0)}},
+			false,
+		},
+		{
+			Traversal{TraverseRoot{Name: "list"}, TraverseIndex{Key: cty.StringVal("1")}},
+			true,
+		},
+		{
+			Traversal{TraverseRoot{Name: "list"}, TraverseIndex{Key: cty.UnknownVal(cty.Number)}},
+			true, // might be the sensitive element
+		},
+		{
+			Traversal{TraverseRoot{Name: "undefined"}},
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.traversal.RootName(), func(t *testing.T) {
+			if got := child.IsSensitive(test.traversal); got != test.want {
+				t.Errorf("wrong result %#v for %#v; want %#v", got, test.traversal, test.want)
+			}
+		})
+	}
+}
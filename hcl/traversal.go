@@ -46,6 +46,12 @@ func (t Traversal) TraverseRel(val cty.Value) (cty.Value, Diagnostics) {
 	current := val
 	var diags Diagnostics
 	for _, tr := range t {
+		if ta, ok := tr.(TraverseAttr); ok && ta.Optional && ta.absent(current) {
+			// An optional attribute step short-circuits the remainder of
+			// the traversal when the attribute is absent.
+			return cty.NullVal(cty.DynamicPseudoType), diags
+		}
+
 		var newDiags Diagnostics
 		current, newDiags = tr.TraversalStep(current)
 		diags = append(diags, newDiags...)
@@ -252,12 +258,39 @@ type TraverseAttr struct {
 	isTraverser
 	Name     string
 	SrcRange Range
+
+	// Optional is set for an attribute lookup written with the safe
+	// navigation operator "?.", which produces null rather than an error if
+	// the initial value is null or does not have the attribute. When such
+	// a step is part of a Traversal, the steps that follow it are skipped
+	// in that case and the result of the whole traversal is null.
+	Optional bool
 }
 
 func (tn TraverseAttr) TraversalStep(val cty.Value) (cty.Value, Diagnostics) {
+	if tn.Optional && tn.absent(val) {
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	}
 	return GetAttr(val, tn.Name, &tn.SrcRange)
 }
 
+// absent returns true if the given value is null or is known not to have
+// the attribute that the receiver looks up.
+func (tn TraverseAttr) absent(val cty.Value) bool {
+	if val.IsNull() {
+		return true
+	}
+	ty := val.Type()
+	switch {
+	case ty.IsObjectType():
+		return !ty.HasAttribute(tn.Name)
+	case ty.IsMapType():
+		return val.IsKnown() && val.HasIndex(cty.StringVal(tn.Name)).False()
+	default:
+		return false
+	}
+}
+
 func (tn TraverseAttr) SourceRange() Range {
 	return tn.SrcRange
 }
@@ -0,0 +1,17 @@
+// Package hclconv converts configuration files between the HCL native syntax
+// and the HCL JSON syntax.
+//
+// Converting from native syntax to JSON is straightforward, because the native
+// syntax makes the structure of each body explicit. Each expression becomes a
+// JSON value, using a template string containing its native syntax source
+// where it cannot be represented directly in JSON.
+//
+// Converting from JSON to native syntax requires a schema, because the JSON
+// syntax uses the same constructs for attributes and blocks and so the
+// application's schema is needed to tell them apart. Each JSON string becomes
+// the native expression that the JSON syntax would interpret it as.
+//
+// The conversions preserve the meaning of a configuration as decoded by an
+// application, but not necessarily its exact source. Where a construct cannot
+// be converted faithfully, a warning or error diagnostic describes it.
+package hclconv
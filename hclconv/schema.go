@@ -0,0 +1,56 @@
+package hclconv
+
+import (
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcldec"
+)
+
+// Schema describes the expected structure of a body and of the bodies of its
+// nested blocks, as needed to convert a body from the JSON syntax.
+type Schema struct {
+	// Body is the schema for the body itself. If it is nil, the body is
+	// expected to contain only attributes, as for hcl.Body.JustAttributes.
+	Body *hcl.BodySchema
+
+	// Blocks gives the schemas for the bodies of the nested blocks of each
+	// type declared in Body. The bodies of blocks of any type not present
+	// here are expected to contain only attributes.
+	Blocks map[string]*Schema
+}
+
+// SchemaForSpec returns a schema describing the structure that the given
+// hcldec specification expects, including the bodies of any nested blocks.
+func SchemaForSpec(spec hcldec.Spec) *Schema {
+	ret := &Schema{
+		Body:   hcldec.ImpliedSchema(spec),
+		Blocks: map[string]*Schema{},
+	}
+	for typeName, nested := range hcldec.ChildBlockTypes(spec) {
+		ret.Blocks[typeName] = SchemaForSpec(nested)
+	}
+	return ret
+}
+
+// bodySchema returns the schema for the receiving body with all of its
+// attributes and blocks optional, since our goal is just to distinguish
+// attributes from blocks, not to validate the body.
+func (s *Schema) bodySchema() *hcl.BodySchema {
+	ret := &hcl.BodySchema{
+		Attributes: make([]hcl.AttributeSchema, len(s.Body.Attributes)),
+		Blocks:     s.Body.Blocks,
+	}
+	for i, attrS := range s.Body.Attributes {
+		attrS.Required = false
+		ret.Attributes[i] = attrS
+	}
+	return ret
+}
+
+// blockSchema returns the schema for the bodies of blocks of the given type,
+// which is nil if nothing is known about them.
+func (s *Schema) blockSchema(typeName string) *Schema {
+	if s == nil {
+		return nil
+	}
+	return s.Blocks[typeName]
+}
@@ -0,0 +1,334 @@
+package hclconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// NativeToJSON converts the given file, which must have been parsed from the
+// HCL native syntax, to the JSON syntax.
+//
+// Literal values are converted to the equivalent JSON values, and templates
+// to JSON strings containing the same template. Any other expression becomes
+// a JSON string containing a template that interpolates the expression, which
+// produces the same value as the original when the JSON string is evaluated
+// as an expression. An application that accepts only literal values for a
+// particular attribute, and so does not evaluate its JSON strings as
+// templates, will not accept such a string.
+//
+// Comments are discarded, since the JSON syntax has no general comment
+// syntax, and a warning is returned if the file contains any.
+func NativeToJSON(file *hcl.File) ([]byte, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported file syntax",
+			Detail:   "Only files in the native syntax can be converted to JSON.",
+		})
+		return nil, diags
+	}
+
+	tokens, _ := hclsyntax.LexConfig(file.Bytes, body.SrcRange.Filename, hcl.Pos{Line: 1, Column: 1})
+	for _, tok := range tokens {
+		if tok.Type == hclsyntax.TokenComment {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Comments discarded",
+				Detail:   "The JSON syntax does not support comments, so the comments in this file cannot be converted.",
+				Subject:  tok.Range.Ptr(),
+			})
+			break
+		}
+	}
+
+	obj, bodyDiags := bodyToJSON(body)
+	diags = append(diags, bodyDiags...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var compact, buf bytes.Buffer
+	writeJSON(&compact, obj)
+	if err := json.Indent(&buf, compact.Bytes(), "", "  "); err != nil {
+		// Should never happen, because we generated the JSON ourselves.
+		panic(fmt.Sprintf("generated invalid JSON: %s", err))
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), diags
+}
+
+// jsonObject is a JSON object whose properties are written in the order they
+// were added.
+type jsonObject struct {
+	names  []string
+	values map[string]interface{}
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{
+		values: map[string]interface{}{},
+	}
+}
+
+func (o *jsonObject) Set(name string, val interface{}) {
+	if _, exists := o.values[name]; !exists {
+		o.names = append(o.names, name)
+	}
+	o.values[name] = val
+}
+
+func (o *jsonObject) Get(name string) (interface{}, bool) {
+	val, exists := o.values[name]
+	return val, exists
+}
+
+// writeJSON writes the compact JSON representation of the given value, which
+// must be a *jsonObject, []interface{}, string, json.Number, bool or nil.
+//
+// We use this rather than encoding/json so that we can preserve the order
+// of object properties and avoid escaping HTML characters, which would make
+// the template strings harder to read.
+func writeJSON(buf *bytes.Buffer, val interface{}) {
+	switch val := val.(type) {
+	case *jsonObject:
+		buf.WriteByte('{')
+		for i, name := range val.names {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSON(buf, name)
+			buf.WriteByte(':')
+			writeJSON(buf, val.values[name])
+		}
+		buf.WriteByte('}')
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, elem := range val {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSON(buf, elem)
+		}
+		buf.WriteByte(']')
+	case string:
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		enc.Encode(val)
+		buf.Truncate(buf.Len() - 1) // Encode adds a trailing newline
+	case json.Number:
+		buf.WriteString(string(val))
+	case bool:
+		buf.WriteString(strconv.FormatBool(val))
+	case nil:
+		buf.WriteString("null")
+	default:
+		panic(fmt.Sprintf("can't write %T as JSON", val))
+	}
+}
+
+func bodyToJSON(body *hclsyntax.Body) (*jsonObject, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	ret := newJSONObject()
+
+	attrs := make([]*hclsyntax.Attribute, 0, len(body.Attributes))
+	for _, attr := range body.Attributes {
+		attrs = append(attrs, attr)
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte
+	})
+	for _, attr := range attrs {
+		ret.Set(attr.Name, exprToJSON(attr.Expr))
+	}
+
+	firstBlocks := map[string]*hclsyntax.Block{}
+	for _, block := range body.Blocks {
+		if attr, conflict := body.Attributes[block.Type]; conflict {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Block type conflicts with attribute",
+				Detail:   fmt.Sprintf("The JSON syntax cannot represent both an attribute and blocks named %q in the same body. The attribute is defined at %s.", block.Type, attr.NameRange),
+				Subject:  block.TypeRange.Ptr(),
+			})
+			continue
+		}
+		if first, exists := firstBlocks[block.Type]; exists && len(first.Labels) != len(block.Labels) {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Inconsistent block labels",
+				Detail:   fmt.Sprintf("The JSON syntax requires all blocks of the same type to have the same number of labels, but this block has %d and the %q block at %s has %d.", len(block.Labels), block.Type, first.TypeRange, len(first.Labels)),
+				Subject:  block.DefRange().Ptr(),
+			})
+			continue
+		}
+		firstBlocks[block.Type] = block
+
+		blockObj, blockDiags := bodyToJSON(block.Body)
+		diags = append(diags, blockDiags...)
+
+		// Each label introduces a level of object nesting, with the body
+		// itself at the innermost level. If there is more than one block
+		// with the same labels then their bodies are given in an array.
+		names := append([]string{block.Type}, block.Labels...)
+		obj := ret
+		for _, name := range names[:len(names)-1] {
+			next, exists := obj.Get(name)
+			if !exists {
+				next = newJSONObject()
+				obj.Set(name, next)
+			}
+			obj = next.(*jsonObject)
+		}
+		name := names[len(names)-1]
+		switch existing := obj.values[name].(type) {
+		case nil:
+			obj.Set(name, blockObj)
+		case *jsonObject:
+			obj.Set(name, []interface{}{existing, blockObj})
+		case []interface{}:
+			obj.Set(name, append(existing, blockObj))
+		}
+	}
+
+	return ret, diags
+}
+
+func exprToJSON(expr hclsyntax.Expression) interface{} {
+	switch e := expr.(type) {
+
+	case *hclsyntax.LiteralValueExpr:
+		return valueToJSON(e.Val)
+
+	case *hclsyntax.UnaryOpExpr:
+		// The parser produces a negative number literal as the negation of
+		// a positive one.
+		if lit, isLit := e.Val.(*hclsyntax.LiteralValueExpr); isLit && e.Op == hclsyntax.OpNegate && lit.Val.Type() == cty.Number {
+			return valueToJSON(lit.Val.Negate())
+		}
+
+	case *hclsyntax.TemplateExpr:
+		return templateToJSON(e)
+
+	case *hclsyntax.TemplateWrapExpr:
+		return "${" + nativeSource(e.Wrapped) + "}"
+
+	case *hclsyntax.TupleConsExpr:
+		ret := make([]interface{}, len(e.Exprs))
+		for i, elem := range e.Exprs {
+			ret[i] = exprToJSON(elem)
+		}
+		return ret
+
+	case *hclsyntax.ObjectConsExpr:
+		ret := newJSONObject()
+		for _, item := range e.Items {
+			ret.Set(objectKeyToJSON(item.KeyExpr), exprToJSON(item.ValueExpr))
+		}
+		return ret
+	}
+
+	return "${" + nativeSource(expr) + "}"
+}
+
+func objectKeyToJSON(expr hclsyntax.Expression) string {
+	if key, isKey := expr.(*hclsyntax.ObjectConsKeyExpr); isKey {
+		if name := hcl.ExprAsKeyword(key.Wrapped); name != "" {
+			return name
+		}
+		expr = key.Wrapped
+	}
+	if key, isString := exprToJSON(expr).(string); isString {
+		return key
+	}
+	return "${" + nativeSource(expr) + "}"
+}
+
+func valueToJSON(val cty.Value) interface{} {
+	switch {
+	case val.IsNull():
+		return nil
+	case val.Type() == cty.Bool:
+		return val.True()
+	case val.Type() == cty.Number:
+		return json.Number(val.AsBigFloat().Text('f', -1))
+	case val.Type() == cty.String:
+		return escapeTemplate(val.AsString())
+	default:
+		// The parser produces literals only of primitive types.
+		panic(fmt.Sprintf("unsupported literal value %#v", val))
+	}
+}
+
+// templateToJSON returns a JSON string that represents the same template as
+// the given native syntax template.
+func templateToJSON(tmpl *hclsyntax.TemplateExpr) string {
+	// We generate the native syntax for the template as a quoted string,
+	// and then remove the quotes and the escape sequences for the quoted
+	// string, leaving the template source.
+	toks := hclwrite.TokensForExpression(tmpl)
+	var buf strings.Builder
+	for _, tok := range toks[1 : len(toks)-1] {
+		buf.WriteString(strings.Repeat(" ", tok.SpacesBefore))
+		if tok.Type == hclsyntax.TokenQuotedLit {
+			buf.WriteString(unescapeQuotedLit(string(tok.Bytes)))
+		} else {
+			buf.Write(tok.Bytes)
+		}
+	}
+	return buf.String()
+}
+
+// escapeTemplate returns a template that produces the given literal string.
+func escapeTemplate(s string) string {
+	s = strings.Replace(s, "${", "$${", -1)
+	return strings.Replace(s, "%{", "%%{", -1)
+}
+
+// unescapeQuotedLit interprets the backslash escapes in the given quoted
+// string literal token, leaving the template escapes "$${" and "%%{" as they
+// are. The token was generated by hclwrite and so is assumed to be valid.
+func unescapeQuotedLit(s string) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			buf.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 't':
+			buf.WriteByte('\t')
+		case 'u', 'U':
+			l := 4
+			if s[i] == 'U' {
+				l = 8
+			}
+			r, _ := strconv.ParseUint(s[i+1:i+1+l], 16, 32)
+			buf.WriteRune(rune(r))
+			i += l
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+	return buf.String()
+}
+
+func nativeSource(expr hclsyntax.Expression) string {
+	return string(hclwrite.TokensForExpression(expr).Bytes())
+}
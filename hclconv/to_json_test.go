@@ -0,0 +1,203 @@
+package hclconv
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hcl/json"
+)
+
+func TestNativeToJSON(t *testing.T) {
+	tests := []struct {
+		Input     string
+		Want      string
+		WantDiags []string // summaries
+	}{
+		{
+			``,
+			"{}\n",
+			nil,
+		},
+		{
+			`
+str = "hello"
+num = 5
+neg = -1.5
+bool = true
+nothing = null
+`,
+			`{
+  "str": "hello",
+  "num": 5,
+  "neg": -1.5,
+  "bool": true,
+  "nothing": null
+}
+`,
+			nil,
+		},
+		{
+			`
+ref = foo.bar
+expr = a + b * 2
+call = upper("<${a}>")
+for = [for x in y: x]
+`,
+			`{
+  "ref": "${foo.bar}",
+  "expr": "${a + b * 2}",
+  "call": "${upper(\"<${a}>\")}",
+  "for": "${[for x in y : x]}"
+}
+`,
+			nil,
+		},
+		{
+			`
+tmpl = "Hello, ${name}!\n"
+literal = "$${not} %%{not}"
+escaped = "${"\"quoted\""}"
+directive = "%{ if x }yes%{ else }no%{ endif }"
+heredoc = <<EOT
+  Hello, ${name}!
+EOT
+`,
+			`{
+  "tmpl": "Hello, ${name}!\n",
+  "literal": "$${not} %%{not}",
+  "escaped": "${\"\\\"quoted\\\"\"}",
+  "directive": "%{if x}yes%{else}no%{endif}",
+  "heredoc": "  Hello, ${name}!\n"
+}
+`,
+			nil,
+		},
+		{
+			`
+list = [1, "a", b]
+obj = { a = 1, "b c" = [], "${k}" = k }
+`,
+			`{
+  "list": [
+    1,
+    "a",
+    "${b}"
+  ],
+  "obj": {
+    "a": 1,
+    "b c": [],
+    "${k}": "${k}"
+  }
+}
+`,
+			nil,
+		},
+		{
+			`
+a = 1
+service "web" {
+  port = 80
+}
+resource "a" "b" {
+  x = 1
+}
+resource "a" "b" {
+  x = 2
+}
+resource "a" "c" {
+}
+empty {
+}
+`,
+			`{
+  "a": 1,
+  "service": {
+    "web": {
+      "port": 80
+    }
+  },
+  "resource": {
+    "a": {
+      "b": [
+        {
+          "x": 1
+        },
+        {
+          "x": 2
+        }
+      ],
+      "c": {}
+    }
+  },
+  "empty": {}
+}
+`,
+			nil,
+		},
+		{
+			`
+# A comment
+a = 1 // another
+`,
+			`{
+  "a": 1
+}
+`,
+			[]string{"Comments discarded"},
+		},
+		{
+			`
+a = 1
+a {
+}
+`,
+			``,
+			[]string{"Block type conflicts with attribute"},
+		},
+		{
+			`
+a "x" {
+}
+a {
+}
+`,
+			``,
+			[]string{"Inconsistent block labels"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(test.Input), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors parsing input: %s", diags.Error())
+			}
+
+			got, diags := NativeToJSON(file)
+			if got := string(got); got != test.Want {
+				t.Errorf("wrong result\ngot:\n%s\nwant:\n%s", got, test.Want)
+			}
+			assertDiagSummaries(t, diags, test.WantDiags)
+
+			if diags.HasErrors() {
+				return
+			}
+			if _, diags := json.Parse(got, "test.hcl.json"); diags.HasErrors() {
+				t.Errorf("result is not valid JSON syntax: %s", diags.Error())
+			}
+		})
+	}
+}
+
+func assertDiagSummaries(t *testing.T, diags hcl.Diagnostics, want []string) {
+	t.Helper()
+	if len(diags) != len(want) {
+		t.Fatalf("wrong number of diagnostics %d; want %d\n%s", len(diags), len(want), diags.Error())
+	}
+	for i, diag := range diags {
+		if diag.Summary != want[i] {
+			t.Errorf("wrong summary for diagnostic %d %q; want %q", i, diag.Summary, want[i])
+		}
+	}
+}
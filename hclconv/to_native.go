@@ -0,0 +1,197 @@
+package hclconv
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hcl/json"
+	"github.com/hashicorp/hcl2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// JSONToNative converts the given file, which must have been parsed from the
+// HCL JSON syntax, to the native syntax.
+//
+// The given schema decides which JSON properties represent blocks. It may be
+// nil if the file's body is expected to contain only attributes, in which
+// case any JSON objects become object constructor expressions. Properties
+// that aren't in the schema are also converted as attributes, so that
+// they'll produce the same errors when the result is decoded.
+//
+// Each JSON string is converted to the native expression that its template
+// represents, so the result is equivalent only for attributes whose values
+// are evaluated as expressions. Since a template that consists only of a
+// single interpolation sequence produces its result unconverted, such a
+// template is represented just by the interpolated expression.
+//
+// The JSON syntax allows comments to be written as properties named "//",
+// but these are discarded.
+func JSONToNative(file *hcl.File, schema *Schema) ([]byte, hcl.Diagnostics) {
+	f := hclwrite.NewEmptyFile()
+	diags := bodyToNative(file.Body, schema, f.Body())
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return hclwrite.Format(f.Bytes()), diags
+}
+
+func bodyToNative(body hcl.Body, schema *Schema, out *hclwrite.Body) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	var attrs hcl.Attributes
+	var blocks hcl.Blocks
+	if schema == nil || schema.Body == nil {
+		var attrDiags hcl.Diagnostics
+		attrs, attrDiags = body.JustAttributes()
+		diags = append(diags, attrDiags...)
+	} else {
+		content, remain, contentDiags := body.PartialContent(schema.bodySchema())
+		diags = append(diags, contentDiags...)
+		remainAttrs, attrDiags := remain.JustAttributes()
+		diags = append(diags, attrDiags...)
+
+		attrs = content.Attributes
+		for name, attr := range remainAttrs {
+			attrs[name] = attr
+		}
+		blocks = content.Blocks
+	}
+
+	// We write the attributes and blocks in the order they appear in the
+	// source, as far as possible.
+	type item struct {
+		attr  *hcl.Attribute
+		block *hcl.Block
+		start int
+	}
+	items := make([]item, 0, len(attrs)+len(blocks))
+	for _, attr := range attrs {
+		items = append(items, item{attr: attr, start: attr.Range.Start.Byte})
+	}
+	for _, block := range blocks {
+		items = append(items, item{block: block, start: block.DefRange.Start.Byte})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].start < items[j].start
+	})
+
+	for _, item := range items {
+		switch {
+		case item.attr != nil:
+			attr := item.attr
+			if !hclsyntax.ValidIdentifier(attr.Name) {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid attribute name",
+					Detail:   fmt.Sprintf("The native syntax cannot represent an attribute named %q, because it is not a valid identifier.", attr.Name),
+					Subject:  attr.NameRange.Ptr(),
+				})
+				continue
+			}
+			expr, exprDiags := exprToNative(attr.Expr)
+			diags = append(diags, exprDiags...)
+			if expr == nil {
+				continue
+			}
+			out.SetAttributeRaw(attr.Name, hclwrite.TokensForExpression(expr))
+
+		case item.block != nil:
+			block := item.block
+			blockOut := out.AppendNewBlock(block.Type, block.Labels)
+			diags = append(diags, bodyToNative(block.Body, schema.blockSchema(block.Type), blockOut.Body())...)
+		}
+	}
+
+	return diags
+}
+
+// exprToNative returns the native syntax expression that is equivalent to the
+// given JSON syntax expression, or nil if it cannot be converted.
+func exprToNative(expr hcl.Expression) (hclsyntax.Expression, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	if elems, listDiags := hcl.ExprList(expr); !listDiags.HasErrors() {
+		ret := &hclsyntax.TupleConsExpr{
+			Exprs: make([]hclsyntax.Expression, 0, len(elems)),
+		}
+		for _, elem := range elems {
+			elemExpr, elemDiags := exprToNative(elem)
+			diags = append(diags, elemDiags...)
+			if elemExpr == nil {
+				return nil, diags
+			}
+			ret.Exprs = append(ret.Exprs, elemExpr)
+		}
+		return ret, diags
+	}
+
+	if pairs, mapDiags := hcl.ExprMap(expr); !mapDiags.HasErrors() {
+		ret := &hclsyntax.ObjectConsExpr{
+			Items: make([]hclsyntax.ObjectConsItem, 0, len(pairs)),
+		}
+		for _, pair := range pairs {
+			keyExpr, keyDiags := objectKeyToNative(pair.Key)
+			diags = append(diags, keyDiags...)
+			valExpr, valDiags := exprToNative(pair.Value)
+			diags = append(diags, valDiags...)
+			if keyExpr == nil || valExpr == nil {
+				return nil, diags
+			}
+			ret.Items = append(ret.Items, hclsyntax.ObjectConsItem{
+				KeyExpr:   keyExpr,
+				ValueExpr: valExpr,
+			})
+		}
+		return ret, diags
+	}
+
+	// With no EvalContext, the JSON syntax returns the literal value of
+	// every other kind of value, including the source of a template.
+	val, valDiags := expr.Value(nil)
+	diags = append(diags, valDiags...)
+	if valDiags.HasErrors() {
+		return nil, diags
+	}
+	if val.Type() != cty.String || val.IsNull() {
+		return &hclsyntax.LiteralValueExpr{Val: val}, diags
+	}
+
+	tmpl, tmplDiags := json.ParseStringTemplate(expr)
+	diags = append(diags, tmplDiags...)
+	if tmpl == nil || tmplDiags.HasErrors() {
+		return nil, diags
+	}
+	if wrap, isWrap := tmpl.(*hclsyntax.TemplateWrapExpr); isWrap {
+		return wrap.Wrapped, diags
+	}
+	return tmpl, diags
+}
+
+// objectKeyToNative returns the native syntax expression for the key of an
+// object property, which is a keyword if possible.
+func objectKeyToNative(expr hcl.Expression) (hclsyntax.Expression, hcl.Diagnostics) {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	key := val.AsString()
+
+	if hclsyntax.ValidIdentifier(key) {
+		return &hclsyntax.ObjectConsKeyExpr{
+			Wrapped: &hclsyntax.ScopeTraversalExpr{
+				Traversal: hcl.Traversal{hcl.TraverseRoot{Name: key}},
+			},
+		}, diags
+	}
+
+	tmpl, tmplDiags := json.ParseStringTemplate(expr)
+	diags = append(diags, tmplDiags...)
+	if tmpl == nil || tmplDiags.HasErrors() {
+		return nil, diags
+	}
+	return &hclsyntax.ObjectConsKeyExpr{
+		Wrapped: tmpl,
+	}, diags
+}
@@ -0,0 +1,302 @@
+package hclconv
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hcl/json"
+	"github.com/hashicorp/hcl2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+func TestJSONToNative(t *testing.T) {
+	schema := &Schema{
+		Body: &hcl.BodySchema{
+			Attributes: []hcl.AttributeSchema{
+				{Name: "name", Required: true},
+			},
+			Blocks: []hcl.BlockHeaderSchema{
+				{Type: "service", LabelNames: []string{"name"}},
+				{Type: "resource", LabelNames: []string{"type", "name"}},
+			},
+		},
+		Blocks: map[string]*Schema{
+			"service": {
+				Body: &hcl.BodySchema{
+					Blocks: []hcl.BlockHeaderSchema{
+						{Type: "listener"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		Input     string
+		Schema    *Schema
+		Want      string
+		WantDiags []string // summaries
+	}{
+		{
+			`{}`,
+			nil,
+			"",
+			nil,
+		},
+		{
+			`{"str": "hello", "num": 5, "neg": -1.5, "bool": true, "nothing": null}`,
+			nil,
+			`str     = "hello"
+num     = 5
+neg     = -1.5
+bool    = true
+nothing = null
+`,
+			nil,
+		},
+		{
+			`{
+  "ref": "${foo.bar}",
+  "expr": "${a + b * 2}",
+  "tmpl": "Hello, ${name}!\n",
+  "literal": "$${not}",
+  "directive": "%{ if x }yes%{ endif }",
+  "list": [1, "${b}"],
+  "obj": {"a": 1, "b c": "${k}", "${k}": 2}
+}`,
+			nil,
+			`ref       = foo.bar
+expr      = a + b * 2
+tmpl      = "Hello, ${name}!\n"
+literal   = "$${not}"
+directive = "%{if x}yes%{endif}"
+list      = [1, b]
+obj       = { a = 1, "b c" = k, "${k}" = 2 }
+`,
+			nil,
+		},
+		{
+			`{"service": {"web": {"port": 80}}}`,
+			nil,
+			`service = { web = { port = 80 } }
+`,
+			nil,
+		},
+		{
+			`{
+  "name": "example",
+  "service": {
+    "web": {
+      "port": 80,
+      "listener": [{"protocol": "http"}, {"protocol": "https"}]
+    }
+  },
+  "resource": {
+    "a": {
+      "b": [{"x": 1}, {"x": 2}]
+    }
+  },
+  "extra": {"y": 1}
+}`,
+			schema,
+			`name = "example"
+service "web" {
+  port = 80
+  listener {
+    protocol = "http"
+  }
+  listener {
+    protocol = "https"
+  }
+}
+resource "a" "b" {
+  x = 1
+}
+resource "a" "b" {
+  x = 2
+}
+extra = { y = 1 }
+`,
+			nil,
+		},
+		{
+			`{"service": {"web": {}}}`,
+			schema,
+			`service "web" {
+}
+`,
+			nil,
+		},
+		{
+			`{"invalid name": 1}`,
+			nil,
+			``,
+			[]string{"Invalid attribute name"},
+		},
+		{
+			`{"a": "${"}`,
+			nil,
+			``,
+			[]string{"Invalid expression"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			file, diags := json.Parse([]byte(test.Input), "test.hcl.json")
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors parsing input: %s", diags.Error())
+			}
+
+			got, diags := JSONToNative(file, test.Schema)
+			if got := string(got); got != test.Want {
+				t.Errorf("wrong result\ngot:\n%s\nwant:\n%s", got, test.Want)
+			}
+			assertDiagSummaries(t, diags, test.WantDiags)
+		})
+	}
+}
+
+func TestJSONToNativeDiagnosticRanges(t *testing.T) {
+	// The escape sequence is six bytes in the source but only one in the
+	// string value, so the template's positions must be mapped back to the
+	// source to find the bad token.
+	src := `{"a": "\u0041${foo +}"}`
+	file, diags := json.Parse([]byte(src), "test.hcl.json")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors parsing input: %s", diags.Error())
+	}
+
+	_, diags = JSONToNative(file, nil)
+	if len(diags) != 1 {
+		t.Fatalf("wrong number of diagnostics %d; want 1\n%s", len(diags), diags.Error())
+	}
+	want := hcl.Range{
+		Filename: "test.hcl.json",
+		Start:    hcl.Pos{Line: 1, Column: 21, Byte: 20},
+		End:      hcl.Pos{Line: 1, Column: 22, Byte: 21},
+	}
+	if got := *diags[0].Subject; got != want {
+		t.Errorf("wrong subject %#v; want %#v", got, want)
+	}
+	if got, want := string(want.SliceBytes([]byte(src))), "}"; got != want {
+		t.Errorf("subject covers %q; want %q", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	spec := hcldec.ObjectSpec{
+		"name": &hcldec.AttrSpec{
+			Name:     "name",
+			Type:     cty.String,
+			Required: true,
+		},
+		"tags": &hcldec.AttrSpec{
+			Name: "tags",
+			Type: cty.Map(cty.String),
+		},
+		"services": &hcldec.BlockMapSpec{
+			TypeName:   "service",
+			LabelNames: []string{"name"},
+			Nested: hcldec.ObjectSpec{
+				"port": &hcldec.AttrSpec{
+					Name: "port",
+					Type: cty.Number,
+				},
+				"listeners": &hcldec.BlockListSpec{
+					TypeName: "listener",
+					Nested: &hcldec.AttrSpec{
+						Name: "protocol",
+						Type: cty.String,
+					},
+				},
+				"env": &hcldec.BlockAttrsSpec{
+					TypeName:    "env",
+					ElementType: cty.String,
+				},
+			},
+		},
+	}
+	src := `
+name = "${upper(prefix)}-app"
+tags = {
+  owner = "ops"
+  "${prefix}" = format("%s,%s", [for s in ["a", "b"] : "${s}!"]...)
+}
+
+service "web" {
+  port = base_port + 80
+
+  listener {
+    protocol = "http"
+  }
+  listener {
+    protocol = "https"
+  }
+
+  env {
+    GREETING = <<EOT
+Hello, ${prefix}!
+EOT
+  }
+}
+
+service "db" {
+  port = 5432
+}
+`
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"prefix":    cty.StringVal("test"),
+			"base_port": cty.NumberIntVal(8000),
+		},
+		Functions: map[string]function.Function{
+			"upper":  stdlib.UpperFunc,
+			"format": stdlib.FormatFunc,
+		},
+	}
+
+	nativeFile, diags := hclsyntax.ParseConfig([]byte(src), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors parsing input: %s", diags.Error())
+	}
+	want, diags := hcldec.Decode(nativeFile.Body, spec, ctx)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors decoding input: %s", diags.Error())
+	}
+
+	jsonSrc, diags := NativeToJSON(nativeFile)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors converting to JSON: %s", diags.Error())
+	}
+	jsonFile, diags := json.Parse(jsonSrc, "test.hcl.json")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors parsing JSON: %s\n%s", diags.Error(), jsonSrc)
+	}
+	got, diags := hcldec.Decode(jsonFile.Body, spec, ctx)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors decoding JSON: %s\n%s", diags.Error(), jsonSrc)
+	}
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result from JSON\ngot:  %#v\nwant: %#v\n%s", got, want, jsonSrc)
+	}
+
+	nativeSrc, diags := JSONToNative(jsonFile, SchemaForSpec(spec))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors converting to native syntax: %s", diags.Error())
+	}
+	nativeFile, diags = hclsyntax.ParseConfig(nativeSrc, "test.hcl", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors parsing native syntax: %s\n%s", diags.Error(), nativeSrc)
+	}
+	got, diags = hcldec.Decode(nativeFile.Body, spec, ctx)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors decoding native syntax: %s\n%s", diags.Error(), nativeSrc)
+	}
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result from native syntax\ngot:  %#v\nwant: %#v\n%s", got, want, nativeSrc)
+	}
+}
@@ -26,6 +26,17 @@ func PartialDecode(body hcl.Body, spec Spec, ctx *hcl.EvalContext) (cty.Value, h
 	return decode(body, nil, ctx, spec, true)
 }
 
+// Sensitive returns true if the value that would result from decoding the
+// given body with the given spec and context contains any parts derived
+// from values that are marked as sensitive in the context.
+//
+// As with hcl.ExprSensitive, this is a conservative result: it is true if
+// any expression used in the decoding refers to a sensitive value, even if
+// that value does not contribute directly to the result.
+func Sensitive(body hcl.Body, spec Spec, ctx *hcl.EvalContext) bool {
+	return hcl.TraversalsSensitive(Variables(body, spec), ctx)
+}
+
 // ImpliedType returns the value type that should result from decoding the
 // given spec.
 func ImpliedType(spec Spec) cty.Type {
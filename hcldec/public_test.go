@@ -1120,3 +1120,68 @@ b {
 	}
 
 }
+
+func TestSensitive(t *testing.T) {
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"password": cty.StringVal("hunter2"),
+			"username": cty.StringVal("admin"),
+		},
+		Sensitive: []hcl.Traversal{
+			{hcl.TraverseRoot{Name: "password"}},
+		},
+	}
+	spec := &ObjectSpec{
+		"user": &AttrSpec{
+			Name: "user",
+			Type: cty.String,
+		},
+		"login": &BlockSpec{
+			TypeName: "login",
+			Nested: &AttrSpec{
+				Name: "secret",
+				Type: cty.String,
+			},
+		},
+	}
+
+	tests := []struct {
+		config string
+		want   bool
+	}{
+		{
+			``,
+			false,
+		},
+		{
+			`user = username`,
+			false,
+		},
+		{
+			`user = "${username}:${password}"`,
+			true,
+		},
+		{
+			"login {\n  secret = password\n}\n",
+			true,
+		},
+		{
+			"unused = password\n",
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.config, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(test.config), "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Error())
+			}
+
+			got := Sensitive(file.Body, spec, ctx)
+			if got != test.want {
+				t.Errorf("wrong result %#v; want %#v", got, test.want)
+			}
+		})
+	}
+}
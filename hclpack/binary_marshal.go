@@ -0,0 +1,315 @@
+package hclpack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+// binaryMagic begins every binary serialization of a body, so that readers
+// can quickly reject data in some other format.
+var binaryMagic = []byte("HCLP")
+
+// binaryVersion is the version of the binary format written by
+// MarshalBinary. UnmarshalBinary rejects any later version, so this must be
+// incremented whenever the format changes in a way that older readers cannot
+// handle.
+//
+// Version 2 added the source bundle that follows the body.
+const binaryVersion = 2
+
+// MarshalBinary is an implementation of encoding.BinaryMarshaler, producing
+// a compact binary representation of the body that is smaller and faster to
+// produce and consume than the result of MarshalJSON. Use UnmarshalBinary to
+// decode it.
+//
+// The format begins with a version number, and so is suitable for storage or
+// for transmission between programs built with different versions of this
+// package. A reader returns an error if it does not support the version of
+// the data it is given.
+func (b *Body) MarshalBinary() ([]byte, error) {
+	rngs := make(map[hcl.Range]struct{})
+	b.addRanges(rngs)
+
+	fns, posList, posMap := packPositions(rngs)
+	posRaw, err := posList.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := newVLQBuf(len(posRaw) * 2)
+	buf = append(buf, binaryMagic...)
+	buf = buf.AppendInt(binaryVersion)
+	buf = buf.AppendInt(len(fns))
+	for _, fn := range fns {
+		buf = buf.AppendBytes([]byte(fn))
+	}
+	buf = buf.AppendBytes(posRaw)
+	buf = b.appendBinary(buf, posMap)
+
+	srcs, err := b.Sources.pack()
+	if err != nil {
+		return nil, err
+	}
+	buf = buf.AppendBytes(srcs)
+
+	return buf.Bytes(), nil
+}
+
+func (b *Body) appendBinary(buf vlqBuf, pos map[string]map[hcl.Pos]posOfs) vlqBuf {
+	// We write the attributes in name order so that the result is
+	// deterministic.
+	names := make([]string, 0, len(b.Attributes))
+	for name := range b.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	buf = buf.AppendInt(len(names))
+	for _, name := range names {
+		attr := b.Attributes[name]
+		buf = buf.AppendBytes([]byte(name))
+		buf = attr.appendBinary(buf, pos)
+	}
+
+	buf = buf.AppendInt(len(b.ChildBlocks))
+	for i := range b.ChildBlocks {
+		buf = b.ChildBlocks[i].appendBinary(buf, pos)
+	}
+
+	return appendRangeBinary(buf, b.MissingItemRange_, pos)
+}
+
+func (a *Attribute) appendBinary(buf vlqBuf, pos map[string]map[hcl.Pos]posOfs) vlqBuf {
+	buf = buf.AppendRawByte(byte(a.Expr.SourceType))
+	buf = buf.AppendBytes(a.Expr.Source)
+	buf = appendRangeBinary(buf, a.Range, pos)
+	buf = appendRangeBinary(buf, a.NameRange, pos)
+	buf = appendRangeBinary(buf, a.Expr.Range_, pos)
+	return appendRangeBinary(buf, a.Expr.StartRange_, pos)
+}
+
+func (b *Block) appendBinary(buf vlqBuf, pos map[string]map[hcl.Pos]posOfs) vlqBuf {
+	buf = buf.AppendBytes([]byte(b.Type))
+	buf = buf.AppendInt(len(b.Labels))
+	for _, label := range b.Labels {
+		buf = buf.AppendBytes([]byte(label))
+	}
+	buf = appendRangeBinary(buf, b.DefRange, pos)
+	buf = appendRangeBinary(buf, b.TypeRange, pos)
+	buf = buf.AppendInt(len(b.LabelRanges))
+	for _, rng := range b.LabelRanges {
+		buf = appendRangeBinary(buf, rng, pos)
+	}
+	return b.Body.appendBinary(buf, pos)
+}
+
+func appendRangeBinary(buf vlqBuf, rng hcl.Range, pos map[string]map[hcl.Pos]posOfs) vlqBuf {
+	rp := packRange(rng, pos)
+	buf = buf.AppendInt(int(rp.Start)) // intentionally storing these as 1-based offsets
+	return buf.AppendInt(int(rp.End))
+}
+
+// UnmarshalBinary is an implementation of encoding.BinaryUnmarshaler,
+// decoding data produced by MarshalBinary.
+func (b *Body) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, binaryMagic) {
+		return errors.New("data is not a binary-encoded hclpack body")
+	}
+	d := &binaryDecoder{
+		buf: vlqBuf(data[len(binaryMagic):]),
+	}
+
+	version := d.readInt()
+	if d.err == nil && (version < 1 || version > binaryVersion) {
+		return fmt.Errorf("unsupported hclpack binary format version %d", version)
+	}
+
+	fnCount := d.readCount()
+	fns := make([]string, 0, fnCount)
+	for i := 0; i < fnCount; i++ {
+		fns = append(fns, d.readString())
+	}
+	posRaw := d.readBytes()
+	if d.err != nil {
+		return d.err
+	}
+	var posList positionsPacked
+	if err := posList.UnmarshalBinary(posRaw); err != nil {
+		return err
+	}
+	d.fns = fns
+	d.positions = posList.Unpack()
+
+	body := d.readBody()
+	var srcsRaw []byte
+	if version >= 2 {
+		srcsRaw = d.readBytes()
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.buf) != 0 {
+		return errors.New("extraneous data after hclpack body")
+	}
+	srcs, err := unpackSourceBundle(srcsRaw)
+	if err != nil {
+		return err
+	}
+	body.Sources = srcs
+
+	*b = body
+	return nil
+}
+
+// binaryDecoder reads the binary format produced by MarshalBinary. Once an
+// error is encountered it is recorded in err and all subsequent reads return
+// zero values, so callers need check for errors only once they are done.
+type binaryDecoder struct {
+	buf       vlqBuf
+	fns       []string
+	positions []position
+	err       error
+}
+
+func (d *binaryDecoder) readInt() int {
+	if d.err != nil {
+		return 0
+	}
+	var v int
+	v, d.buf, d.err = d.buf.ReadInt()
+	return v
+}
+
+// readCount reads a number of items that will follow. Each item needs at
+// least one byte, so we reject counts that exceed the remaining length in
+// order to avoid huge allocations for corrupt data.
+func (d *binaryDecoder) readCount() int {
+	n := d.readInt()
+	if d.err == nil && (n < 0 || n > len(d.buf)) {
+		d.err = errors.New("invalid item count in hclpack body")
+	}
+	if d.err != nil {
+		return 0
+	}
+	return n
+}
+
+func (d *binaryDecoder) readBytes() []byte {
+	if d.err != nil {
+		return nil
+	}
+	var v []byte
+	v, d.buf, d.err = d.buf.ReadBytes()
+	return v
+}
+
+// readBytesCopy is like readBytes but returns a copy of the bytes, for use
+// where they are retained in the result, since encoding.BinaryUnmarshaler
+// requires that the result not refer to the data being decoded.
+func (d *binaryDecoder) readBytesCopy() []byte {
+	v := d.readBytes()
+	if v == nil {
+		return nil
+	}
+	ret := make([]byte, len(v))
+	copy(ret, v)
+	return ret
+}
+
+func (d *binaryDecoder) readString() string {
+	return string(d.readBytes())
+}
+
+func (d *binaryDecoder) readRange() hcl.Range {
+	start := d.readInt()
+	end := d.readInt()
+	if d.err != nil {
+		return hcl.Range{}
+	}
+	if start < 0 || start > len(d.positions) || end < 0 || end > len(d.positions) {
+		d.err = errors.New("invalid position index in hclpack body")
+		return hcl.Range{}
+	}
+	if start == 0 || end == 0 {
+		return hcl.Range{} // the range was absent when packed
+	}
+	return rangePacked{Start: posOfs(start), End: posOfs(end)}.Unpack(d.fns, d.positions)
+}
+
+func (d *binaryDecoder) readBody() Body {
+	var ret Body
+
+	if n := d.readCount(); n > 0 {
+		ret.Attributes = make(map[string]Attribute, n)
+		for i := 0; i < n; i++ {
+			name := d.readString()
+			ret.Attributes[name] = d.readAttribute()
+		}
+	}
+
+	if n := d.readCount(); n > 0 {
+		ret.ChildBlocks = make([]Block, n)
+		for i := range ret.ChildBlocks {
+			ret.ChildBlocks[i] = d.readBlock()
+		}
+	}
+
+	ret.MissingItemRange_ = d.readRange()
+
+	return ret
+}
+
+func (d *binaryDecoder) readAttribute() Attribute {
+	var ret Attribute
+
+	if d.err == nil {
+		var ty byte
+		ty, d.buf, d.err = d.buf.ReadRawByte()
+		ret.Expr.SourceType = ExprSourceType(ty)
+	}
+	switch ret.Expr.SourceType {
+	case ExprNative, ExprTemplate, ExprLiteralJSON, ExprJSON:
+		// okay
+	default:
+		if d.err == nil {
+			d.err = fmt.Errorf("unsupported expression source type %s in hclpack body", ret.Expr.SourceType)
+		}
+	}
+	ret.Expr.Source = d.readBytesCopy()
+	ret.Range = d.readRange()
+	ret.NameRange = d.readRange()
+	ret.Expr.Range_ = d.readRange()
+	ret.Expr.StartRange_ = d.readRange()
+	if ret.Expr.StartRange_ == (hcl.Range{}) {
+		// If the start range wasn't present then we'll just use the Range
+		ret.Expr.StartRange_ = ret.Expr.Range_
+	}
+
+	return ret
+}
+
+func (d *binaryDecoder) readBlock() Block {
+	var ret Block
+
+	ret.Type = d.readString()
+	if n := d.readCount(); n > 0 {
+		ret.Labels = make([]string, n)
+		for i := range ret.Labels {
+			ret.Labels[i] = d.readString()
+		}
+	}
+	ret.DefRange = d.readRange()
+	ret.TypeRange = d.readRange()
+	if n := d.readCount(); n > 0 {
+		ret.LabelRanges = make([]hcl.Range, n)
+		for i := range ret.LabelRanges {
+			ret.LabelRanges[i] = d.readRange()
+		}
+	}
+	ret.Body = d.readBody()
+
+	return ret
+}
@@ -0,0 +1,130 @@
+package hclpack
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+func TestBodyBinaryRoundTrip(t *testing.T) {
+	src := `
+	service "example" {
+	  priority = 2
+	  platform {
+	    os   = "linux"
+	    arch = "amd64"
+	  }
+	  process "web" {
+	    exec = ["./webapp"]
+	  }
+	  process "worker" {
+	    exec = ["./worker"]
+	  }
+	}
+	`
+
+	startBody, diags := PackNativeFile([]byte(src), "example.svc", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("Failed to parse: %s", diags.Error())
+	}
+
+	bb, err := startBody.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %s", err)
+	}
+
+	endBody := &Body{}
+	err = endBody.UnmarshalBinary(bb)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal: %s", err)
+	}
+
+	if !cmp.Equal(startBody, endBody) {
+		t.Errorf("incorrect result\n%s", cmp.Diff(startBody, endBody))
+	}
+
+	jb, err := startBody.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Failed to marshal JSON: %s", err)
+	}
+	if len(bb) >= len(jb) {
+		t.Errorf("binary encoding is %d bytes, but JSON encoding is only %d bytes", len(bb), len(jb))
+	}
+}
+
+func TestBodyUnmarshalBinaryCopy(t *testing.T) {
+	startBody, diags := PackNativeFile([]byte(`foo = "bar"`), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("Failed to parse: %s", diags.Error())
+	}
+	bb, err := startBody.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %s", err)
+	}
+
+	endBody := &Body{}
+	if err := endBody.UnmarshalBinary(bb); err != nil {
+		t.Fatalf("Failed to unmarshal: %s", err)
+	}
+
+	// The caller may reuse the data once UnmarshalBinary returns, so the
+	// result must not refer to it.
+	for i := range bb {
+		bb[i] = 'x'
+	}
+	if !cmp.Equal(startBody, endBody) {
+		t.Errorf("result changed after overwriting input\n%s", cmp.Diff(startBody, endBody))
+	}
+}
+
+func TestBodyUnmarshalBinaryErrors(t *testing.T) {
+	body, diags := PackNativeFile([]byte(`foo = "bar"`), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("Failed to parse: %s", diags.Error())
+	}
+	valid, err := body.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %s", err)
+	}
+
+	tests := map[string]struct {
+		data []byte
+		want string
+	}{
+		"empty": {
+			nil,
+			"data is not a binary-encoded hclpack body",
+		},
+		"wrong magic": {
+			[]byte(`{"r":{}}`),
+			"data is not a binary-encoded hclpack body",
+		},
+		"unsupported version": {
+			append(append([]byte{}, binaryMagic...), vlqBuf(nil).AppendInt(binaryVersion+1)...),
+			"unsupported hclpack binary format version 3",
+		},
+		"truncated": {
+			valid[:len(valid)-1],
+			"missing expected VLQ value",
+		},
+		"extra data": {
+			append(append([]byte{}, valid...), 0),
+			"extraneous data after hclpack body",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got Body
+			err := got.UnmarshalBinary(test.data)
+			if err == nil {
+				t.Fatalf("unexpected success; want error %q", test.want)
+			}
+			if got := err.Error(); got != test.want {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
@@ -2,13 +2,19 @@
 // structure that can be easily serialized and deserialized for compact
 // transmission (e.g. over a network) without transmitting the full source code.
 //
-// Expressions are retained in native syntax source form so that their
+// Expressions are retained in source form, in either native syntax or JSON
+// syntax depending on the syntax of the original file, so that their
 // evaluation can be delayed until a package structure is decoded by some
 // other system that has enough information to populate the evaluation context.
 //
+// Bodies can be serialized either as JSON, using MarshalJSON, or in a more
+// compact versioned binary format, using MarshalBinary.
+//
 // Packed structures retain source location information but do not retain
-// actual source code. To make sense of source locations returned in diagnostics
-// and via other APIs the caller must somehow gain access to the original source
-// code that the packed representation was built from, which is a problem that
-// must be solved somehow by the calling application.
+// actual source code by default. To make sense of source locations returned in
+// diagnostics and via other APIs the caller must somehow gain access to the
+// original source code that the packed representation was built from. The
+// calling application can either solve that problem itself, or use
+// Body.AddSource to bundle the source code with the packed body, at the
+// expense of a larger result.
 package hclpack
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/hcl2/hcl"
 	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	hclJSON "github.com/hashicorp/hcl2/hcl/json"
 )
 
 // Expression is an implementation of hcl.Expression in terms of some raw
@@ -96,6 +97,8 @@ func (e *Expression) Parse() (hcl.Expression, hcl.Diagnostics) {
 		return hclsyntax.ParseExpression(e.Source, e.Range_.Filename, e.Range_.Start)
 	case ExprTemplate:
 		return hclsyntax.ParseTemplate(e.Source, e.Range_.Filename, e.Range_.Start)
+	case ExprJSON:
+		return hclJSON.ParseExpressionWithStartPos(e.Source, e.Range_.Filename, e.Range_.Start)
 	case ExprLiteralJSON:
 		ty, err := ctyjson.ImpliedType(e.Source)
 		if err != nil {
@@ -157,4 +160,13 @@ const (
 	// treated literally, using cty/json. This can be used when populating
 	// literal attribute values from a non-HCL source.
 	ExprLiteralJSON ExprSourceType = 'L'
+
+	// ExprJSON indicates that an expression must be parsed as a value in
+	// the HCL JSON syntax, with the hcl/json package, and so is interpreted
+	// in the same way as in a JSON-based configuration file, including the
+	// evaluation of templates in its strings.
+	//
+	// An attribute whose expression has this type can alternatively be
+	// interpreted as one or more blocks, as described for PackJSONFile.
+	ExprJSON ExprSourceType = 'J'
 )
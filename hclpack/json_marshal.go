@@ -17,10 +17,16 @@ func (b *Body) MarshalJSON() ([]byte, error) {
 
 	fns, posList, posMap := packPositions(rngs)
 
+	srcs, err := b.Sources.pack()
+	if err != nil {
+		return nil, err
+	}
+
 	head := jsonHeader{
-		Body:    b.forJSON(posMap),
-		Sources: fns,
-		Pos:     posList,
+		Body:       b.forJSON(posMap),
+		Sources:    fns,
+		Pos:        posList,
+		SourceCode: srcs,
 	}
 
 	return json.Marshal(&head)
@@ -58,6 +64,8 @@ func (a *Attribute) forJSON(pos map[string]map[hcl.Pos]posOfs) attrJSON {
 		ret.Syntax = 1
 	case ExprLiteralJSON:
 		ret.Syntax = 2
+	case ExprJSON:
+		ret.Syntax = 3
 	}
 	ret.Ranges = make(rangesPacked, 4)
 	ret.Ranges[0] = packRange(a.Range, pos)
@@ -97,7 +105,13 @@ func (b *Body) UnmarshalJSON(data []byte) error {
 	fns := head.Sources
 	positions := head.Pos.Unpack()
 
+	srcs, err := unpackSourceBundle(head.SourceCode)
+	if err != nil {
+		return err
+	}
+
 	*b = head.Body.decode(fns, positions)
+	b.Sources = srcs
 
 	return nil
 }
@@ -107,6 +121,9 @@ type jsonHeader struct {
 
 	Sources []string        `json:"s,omitempty"`
 	Pos     positionsPacked `json:"p,omitempty"`
+
+	// SourceCode is the packed form of the body's SourceBundle, if any.
+	SourceCode []byte `json:"c,omitempty"`
 }
 
 type bodyJSON struct {
@@ -162,6 +179,8 @@ func (aj *attrJSON) decode(fns []string, positions []position) Attribute {
 		ret.Expr.SourceType = ExprTemplate
 	case 2:
 		ret.Expr.SourceType = ExprLiteralJSON
+	case 3:
+		ret.Expr.SourceType = ExprJSON
 	}
 
 	ret.Range = aj.Ranges.UnpackIdx(fns, positions, 0)
@@ -0,0 +1,171 @@
+package hclpack
+
+import (
+	"fmt"
+
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	hclJSON "github.com/hashicorp/hcl2/hcl/json"
+	"github.com/hashicorp/hcl2/hcldec"
+)
+
+// PackBody packs the content of an arbitrary body into a hclpack Body ready
+// to be marshalled, walking it using the schema implied by the given spec, in
+// the same way as hcldec.Decode. This allows packing bodies that are not
+// backed directly by source code, such as those produced by
+// hcl.MergeBodies or by the extensions in the "ext" directory.
+//
+// Only the attributes and blocks described by the spec are included in the
+// result, and so decoding the result using the same spec produces the same
+// result as decoding the original body. The bodies of any blocks whose
+// nested spec has no attributes or blocks of its own, such as for
+// hcldec.BlockAttrsSpec, are packed using JustAttributes.
+//
+// Where an expression was parsed from the native syntax or the JSON syntax,
+// its source code is retained so that it can be evaluated later. This
+// requires that files include the file that each expression was parsed
+// from, keyed by filename, as returned by hclparse.Parser.Files. Any other
+// expression is evaluated immediately using the given EvalContext and its
+// value is packed as an expression of type ExprLiteralJSON, in which case
+// error diagnostics are returned if the value is not yet known.
+func PackBody(body hcl.Body, spec hcldec.Spec, ctx *hcl.EvalContext, files map[string]*hcl.File) (*Body, hcl.Diagnostics) {
+	p := &bodyPacker{
+		ctx:   ctx,
+		files: files,
+	}
+	return p.packBody(body, spec)
+}
+
+type bodyPacker struct {
+	ctx   *hcl.EvalContext
+	files map[string]*hcl.File
+}
+
+func (p *bodyPacker) packBody(body hcl.Body, spec hcldec.Spec) (*Body, hcl.Diagnostics) {
+	ret := &Body{
+		MissingItemRange_: body.MissingItemRange(),
+	}
+
+	var schema *hcl.BodySchema
+	if spec != nil {
+		schema = hcldec.ImpliedSchema(spec)
+	}
+	if schema == nil || (len(schema.Attributes) == 0 && len(schema.Blocks) == 0) {
+		attrs, diags := body.JustAttributes()
+		for name, attr := range attrs {
+			packed, moreDiags := p.packAttribute(attr)
+			diags = append(diags, moreDiags...)
+			ret.setAttribute(name, packed)
+		}
+		return ret, diags
+	}
+
+	content, diags := body.Content(schema)
+
+	for name, attr := range content.Attributes {
+		packed, moreDiags := p.packAttribute(attr)
+		diags = append(diags, moreDiags...)
+		ret.setAttribute(name, packed)
+	}
+
+	childSpecs := hcldec.ChildBlockTypes(spec)
+	for _, block := range content.Blocks {
+		childBody, moreDiags := p.packBody(block.Body, childSpecs[block.Type])
+		diags = append(diags, moreDiags...)
+		ret.appendBlock(Block{
+			Type:        block.Type,
+			Labels:      block.Labels,
+			Body:        *childBody,
+			DefRange:    block.DefRange,
+			TypeRange:   block.TypeRange,
+			LabelRanges: block.LabelRanges,
+		})
+	}
+
+	return ret, diags
+}
+
+func (p *bodyPacker) packAttribute(attr *hcl.Attribute) (Attribute, hcl.Diagnostics) {
+	expr, diags := p.packExpression(attr.Expr)
+	return Attribute{
+		Expr:      expr,
+		Range:     attr.Range,
+		NameRange: attr.NameRange,
+	}, diags
+}
+
+func (p *bodyPacker) packExpression(expr hcl.Expression) (Expression, hcl.Diagnostics) {
+	rng := expr.Range()
+	ret := Expression{
+		Range_:      rng,
+		StartRange_: expr.StartRange(),
+	}
+
+	switch {
+	case isPackedExpression(expr):
+		return *expr.(*Expression), nil
+	case isNativeExpression(expr):
+		if src := p.exprSource(rng); src != nil {
+			ret.Source = src
+			ret.SourceType = ExprNative
+			return ret, nil
+		}
+	case hclJSON.IsJSONExpression(expr):
+		if src := p.exprSource(rng); src != nil {
+			ret.Source = src
+			ret.SourceType = ExprJSON
+			return ret, nil
+		}
+	}
+
+	// If we get here then we don't have any source code to retain, so we
+	// must evaluate the expression now and retain only its value.
+	val, diags := expr.Value(p.ctx)
+	if diags.HasErrors() {
+		return ret, diags
+	}
+	if !val.IsWhollyKnown() {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unknown value",
+			Detail:   "The value of this expression cannot be determined yet, so it cannot be packed.",
+			Subject:  &rng,
+		})
+		return ret, diags
+	}
+	src, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported value",
+			Detail:   fmt.Sprintf("The value of this expression cannot be packed: %s.", err),
+			Subject:  &rng,
+		})
+		return ret, diags
+	}
+	ret.Source = src
+	ret.SourceType = ExprLiteralJSON
+	return ret, diags
+}
+
+// exprSource returns the source code for the given range, or nil if that
+// source code is not available.
+func (p *bodyPacker) exprSource(rng hcl.Range) []byte {
+	f := p.files[rng.Filename]
+	if f == nil || rng.Start.Byte < 0 || rng.End.Byte > len(f.Bytes) || rng.Start.Byte > rng.End.Byte {
+		return nil
+	}
+	return rng.SliceBytes(f.Bytes)
+}
+
+func isPackedExpression(expr hcl.Expression) bool {
+	_, ok := expr.(*Expression)
+	return ok
+}
+
+func isNativeExpression(expr hcl.Expression) bool {
+	_, ok := expr.(hclsyntax.Expression)
+	return ok
+}
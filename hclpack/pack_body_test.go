@@ -0,0 +1,142 @@
+package hclpack
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/hcl2/ext/dynblock"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcldec"
+	"github.com/hashicorp/hcl2/hclparse"
+)
+
+func TestPackBody(t *testing.T) {
+	spec := hcldec.ObjectSpec{
+		"name": &hcldec.AttrSpec{
+			Name: "name",
+			Type: cty.String,
+		},
+		"port": &hcldec.AttrSpec{
+			Name: "port",
+			Type: cty.Number,
+		},
+		"tags": &hcldec.BlockAttrsSpec{
+			TypeName:    "tags",
+			ElementType: cty.String,
+		},
+		"rules": &hcldec.BlockListSpec{
+			TypeName: "rule",
+			Nested: &hcldec.AttrSpec{
+				Name: "action",
+				Type: cty.String,
+			},
+		},
+	}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"env":     cty.StringVal("prod"),
+			"actions": cty.ListVal([]cty.Value{cty.StringVal("allow"), cty.StringVal("deny")}),
+		},
+	}
+
+	parser := hclparse.NewParser()
+	nativeFile, diags := parser.ParseHCL([]byte(`
+name = "app-${env}"
+tags {
+  owner = "ops"
+}
+dynamic "rule" {
+  for_each = actions
+  content {
+    action = rule.value
+  }
+}
+`), "main.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	jsonFile, diags := parser.ParseJSON([]byte(`{"port": "${8000 + 80}"}`), "port.hcl.json")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	body := dynblock.Expand(hcl.MergeFiles([]*hcl.File{nativeFile, jsonFile}), ctx)
+
+	packed, diags := PackBody(body, spec, ctx, parser.Files())
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	// Expressions should be retained as source code where possible.
+	if got, want := packed.Attributes["name"].Expr.SourceType, ExprNative; got != want {
+		t.Errorf("wrong source type for name %s; want %s", got, want)
+	}
+	if got, want := packed.Attributes["port"].Expr.SourceType, ExprJSON; got != want {
+		t.Errorf("wrong source type for port %s; want %s", got, want)
+	}
+	if got, want := packed.ChildBlocks[0].Body.Attributes["owner"].Expr.SourceType, ExprNative; got != want {
+		t.Errorf("wrong source type for tags.owner %s; want %s", got, want)
+	}
+	// The expressions in the dynamic blocks depend on the iterator, and so
+	// must be packed as values.
+	if got, want := packed.ChildBlocks[1].Body.Attributes["action"].Expr.SourceType, ExprLiteralJSON; got != want {
+		t.Errorf("wrong source type for rule.action %s; want %s", got, want)
+	}
+
+	jb, err := packed.MarshalJSON()
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	unpacked := &Body{}
+	if err := unpacked.UnmarshalJSON(jb); err != nil {
+		t.Fatalf("failed to unmarshal: %s", err)
+	}
+
+	want, diags := hcldec.Decode(body, spec, ctx)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors decoding original: %s", diags.Error())
+	}
+	got, diags := hcldec.Decode(unpacked, spec, ctx)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors decoding packed: %s", diags.Error())
+	}
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestPackBodyUnknown(t *testing.T) {
+	spec := &hcldec.BlockListSpec{
+		TypeName: "rule",
+		Nested: &hcldec.AttrSpec{
+			Name: "action",
+			Type: cty.String,
+		},
+	}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"actions": cty.ListVal([]cty.Value{cty.UnknownVal(cty.String)}),
+		},
+	}
+
+	parser := hclparse.NewParser()
+	f, diags := parser.ParseHCL([]byte(`
+dynamic "rule" {
+  for_each = actions
+  content {
+    action = rule.value
+  }
+}
+`), "main.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	_, diags = PackBody(dynblock.Expand(f.Body, ctx), spec, ctx, parser.Files())
+	if !diags.HasErrors() {
+		t.Fatalf("unexpected success; want error for unknown value")
+	}
+	if got, want := diags[0].Summary, "Unknown value"; got != want {
+		t.Errorf("wrong error summary %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,409 @@
+package hclpack
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl2/hcl"
+	hclJSON "github.com/hashicorp/hcl2/hcl/json"
+)
+
+// PackJSONFile parses the given source code as HCL JSON syntax and packs it
+// into a hclpack Body ready to be marshalled.
+//
+// The JSON syntax cannot distinguish arguments from blocks without a schema,
+// so each property of the body is packed as an attribute whose expression
+// has source type ExprJSON. If Content or PartialContent is later called
+// with a schema that expects blocks of the same type as the attribute's name,
+// the attribute is instead interpreted as zero or more blocks in the same way
+// as the hcl/json package would, so that decoding the packed body produces
+// the same result as decoding the original.
+//
+// The JSON syntax also allows a body to define the same property more than
+// once, in order to define multiple blocks of the same type. A packed body
+// can retain only one attribute of each name, so all of the definitions of
+// such a property are packed together as a single attribute whose expression
+// is the entire JSON body, from which the definitions are recovered when the
+// body is decoded. Decoding such an attribute as an argument produces
+// diagnostics about the duplicate definitions, as for the hcl/json package.
+//
+// If the given source code contains syntax errors then error diagnostics will
+// be returned. A non-nil body might still be returned in this case, which
+// allows a cautious caller to still do certain analyses on the result.
+func PackJSONFile(src []byte, filename string, start hcl.Pos) (*Body, hcl.Diagnostics) {
+	expr, diags := hclJSON.ParseExpressionWithStartPos(src, filename, start)
+	if diags.HasErrors() {
+		return &Body{}, diags
+	}
+	root := jsonValue{expr: expr, src: src}
+
+	switch root.kind() {
+	case '{', '[':
+		// okay
+	default:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Root value must be object",
+			Detail:   "The root value in a JSON-based configuration must be either a JSON object or a JSON array of objects.",
+			Subject:  expr.StartRange().Ptr(),
+		})
+		return &Body{}, diags
+	}
+
+	body, moreDiags := packJSONBody(root)
+	diags = append(diags, moreDiags...)
+	return body, diags
+}
+
+// packJSONBody packs the given JSON object, or array of objects, as a body
+// whose attributes all have expressions of source type ExprJSON.
+func packJSONBody(v jsonValue) (*Body, hcl.Diagnostics) {
+	ret := &Body{}
+
+	props, diags := v.deepProperties(nil)
+	for _, prop := range props {
+		if prop.Name == "//" {
+			// Ignore "//" keys in objects representing bodies, to allow
+			// their use as comments.
+			continue
+		}
+
+		if existing, exists := ret.Attributes[prop.Name]; exists {
+			// All of the definitions are retained in the source of the
+			// body itself, as described in jsonDefinitions.
+			existing.Expr = Expression{
+				Source:     v.src,
+				SourceType: ExprJSON,
+
+				Range_:      v.expr.Range(),
+				StartRange_: v.expr.StartRange(),
+			}
+			ret.setAttribute(prop.Name, existing)
+			continue
+		}
+
+		ret.setAttribute(prop.Name, prop.attribute())
+	}
+
+	rng := v.expr.Range()
+	switch v.kind() {
+	case '{':
+		// The closing brace is always the last byte of an object.
+		ret.MissingItemRange_ = hcl.Range{
+			Filename: rng.Filename,
+			Start: hcl.Pos{
+				Line:   rng.End.Line,
+				Column: rng.End.Column - 1,
+				Byte:   rng.End.Byte - 1,
+			},
+			End: rng.End,
+		}
+	default:
+		ret.MissingItemRange_ = v.expr.StartRange()
+	}
+
+	return ret, diags
+}
+
+// jsonBlocks interprets the receiving attribute, which must have an
+// expression of source type ExprJSON, as a definition of zero or more blocks
+// of the given type, in the same way as the hcl/json package.
+func (a *Attribute) jsonBlocks(typeName string, labelNames []string) ([]Block, hcl.Diagnostics) {
+	defs, diags := a.jsonDefinitions(typeName)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var blocks []Block
+	for _, def := range defs {
+		diags = append(diags, unpackJSONBlock(def.Value, typeName, def.NameRange, labelNames, nil, nil, &blocks)...)
+	}
+	return blocks, diags
+}
+
+// asArgument is like asHCLAttribute, except that if the receiving attribute
+// has more than one definition, as described for jsonDefinitions, then the
+// first is returned along with an error diagnostic with the given summary for
+// each of the others, in the same way as the hcl/json package.
+func (a *Attribute) asArgument(name string, dupSummary string) (*hcl.Attribute, hcl.Diagnostics) {
+	if !a.jsonGrouped() {
+		return a.asHCLAttribute(name), nil
+	}
+
+	defs, diags := a.jsonDefinitions(name)
+	if len(defs) == 0 {
+		// Should never happen for a body produced by PackJSONFile, but we'll
+		// let the expression report the problem when it is evaluated.
+		return a.asHCLAttribute(name), diags
+	}
+
+	first := defs[0].attribute()
+	for _, def := range defs[1:] {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  dupSummary,
+			Detail:   fmt.Sprintf("The argument %q was already set at %s.", name, first.Range),
+			Subject:  def.NameRange.Ptr(),
+		})
+	}
+	return first.asHCLAttribute(name), diags
+}
+
+// jsonDefinitions returns each definition of the receiving attribute, which
+// must have an expression of source type ExprJSON.
+//
+// An attribute that PackJSONFile produced from a property defined more than
+// once in the same body has the entire body as its expression, from which
+// this method recovers each definition of the property with the given name.
+// Such an attribute is recognized by its name being within the range of its
+// expression, whereas the name of any other attribute precedes it.
+func (a *Attribute) jsonDefinitions(name string) ([]jsonProperty, hcl.Diagnostics) {
+	expr, diags := a.Expr.Parse()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	v := jsonValue{expr: expr, src: a.Expr.Source}
+	if !a.jsonGrouped() {
+		return []jsonProperty{
+			{
+				Name:      name,
+				NameRange: a.NameRange,
+				Value:     v,
+			},
+		}, diags
+	}
+
+	props, moreDiags := v.deepProperties(nil)
+	diags = append(diags, moreDiags...)
+	var defs []jsonProperty
+	for _, prop := range props {
+		if prop.Name == name {
+			defs = append(defs, prop)
+		}
+	}
+	return defs, diags
+}
+
+// jsonGrouped returns true if the receiving attribute has more than one
+// definition, as described for jsonDefinitions.
+func (a *Attribute) jsonGrouped() bool {
+	if a.Expr.SourceType != ExprJSON {
+		return false
+	}
+	rng := a.Expr.Range_
+	return rng.Filename == a.NameRange.Filename && rng.ContainsOffset(a.NameRange.Start.Byte)
+}
+
+func unpackJSONBlock(v jsonValue, typeName string, typeRange hcl.Range, labelsLeft []string, labelsUsed []string, labelRanges []hcl.Range, blocks *[]Block) hcl.Diagnostics {
+	if len(labelsLeft) > 0 {
+		labelName := labelsLeft[0]
+		props, diags := v.deepProperties(&labelName)
+		if len(props) == 0 {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing block label",
+				Detail:   fmt.Sprintf("At least one object property is required, whose name represents the %s block's %s.", typeName, labelName),
+				Subject:  v.expr.StartRange().Ptr(),
+			})
+			return diags
+		}
+		labelsUsed := append(labelsUsed, "")
+		labelRanges := append(labelRanges, hcl.Range{})
+		for _, prop := range props {
+			labelsUsed[len(labelsUsed)-1] = prop.Name
+			labelRanges[len(labelRanges)-1] = prop.NameRange
+			diags = append(diags, unpackJSONBlock(prop.Value, typeName, typeRange, labelsLeft[1:], labelsUsed, labelRanges, blocks)...)
+		}
+		return diags
+	}
+
+	// By the time we get here, we've peeled off all the labels and we're ready
+	// to deal with the block's actual content.
+
+	// need to copy the label slices because their underlying arrays will
+	// continue to be mutated after we return.
+	labels := make([]string, len(labelsUsed))
+	copy(labels, labelsUsed)
+	labelR := make([]hcl.Range, len(labelRanges))
+	copy(labelR, labelRanges)
+
+	var diags hcl.Diagnostics
+	switch v.kind() {
+	case 'n':
+		// There is no block content, e.g the value is null.
+	case '{':
+		// Single instance of the block
+		body, bodyDiags := packJSONBody(v)
+		diags = append(diags, bodyDiags...)
+		*blocks = append(*blocks, Block{
+			Type:   typeName,
+			Labels: labels,
+			Body:   *body,
+
+			DefRange:    v.expr.StartRange(),
+			TypeRange:   typeRange,
+			LabelRanges: labelR,
+		})
+	case '[':
+		// Multiple instances of the block
+		for _, elem := range v.elements() {
+			body, bodyDiags := packJSONBody(elem)
+			diags = append(diags, bodyDiags...)
+			*blocks = append(*blocks, Block{
+				Type:   typeName,
+				Labels: labels,
+				Body:   *body,
+
+				DefRange:    v.expr.StartRange(),
+				TypeRange:   typeRange,
+				LabelRanges: labelR,
+			})
+		}
+	default:
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Incorrect JSON value type",
+			Detail:   fmt.Sprintf("Either a JSON object or a JSON array is required, representing the contents of one or more %q blocks.", typeName),
+			Subject:  v.expr.StartRange().Ptr(),
+		})
+	}
+	return diags
+}
+
+// jsonValue is a value within some JSON source code that has already been
+// parsed by the hcl/json package, used to walk the structure of JSON bodies
+// and blocks.
+type jsonValue struct {
+	expr hcl.Expression
+	src  []byte // the source code of just this value
+}
+
+// jsonProperty is a property of a JSON object, as returned by
+// jsonValue.deepProperties.
+type jsonProperty struct {
+	Name      string
+	NameRange hcl.Range
+	Value     jsonValue
+}
+
+// attribute returns the property as an attribute whose expression has
+// source type ExprJSON.
+func (p jsonProperty) attribute() Attribute {
+	valRange := p.Value.expr.Range()
+	return Attribute{
+		Expr: Expression{
+			Source:     p.Value.src,
+			SourceType: ExprJSON,
+
+			Range_:      valRange,
+			StartRange_: p.Value.expr.StartRange(),
+		},
+		Range:     hcl.RangeBetween(p.NameRange, valRange),
+		NameRange: p.NameRange,
+	}
+}
+
+// kind returns the first byte of the value's source code, which is enough to
+// distinguish objects, arrays and null from the other kinds of value.
+func (v jsonValue) kind() byte {
+	if len(v.src) == 0 {
+		return 0
+	}
+	return v.src[0]
+}
+
+// child returns the value nested inside the receiver that is represented by
+// the given expression.
+func (v jsonValue) child(expr hcl.Expression) jsonValue {
+	base := v.expr.Range().Start.Byte
+	rng := expr.Range()
+	return jsonValue{
+		expr: expr,
+		src:  v.src[rng.Start.Byte-base : rng.End.Byte-base],
+	}
+}
+
+func (v jsonValue) elements() []jsonValue {
+	exprs, _ := hcl.ExprList(v.expr)
+	ret := make([]jsonValue, len(exprs))
+	for i, expr := range exprs {
+		ret[i] = v.child(expr)
+	}
+	return ret
+}
+
+func (v jsonValue) properties() []jsonProperty {
+	pairs, _ := hcl.ExprMap(v.expr)
+	ret := make([]jsonProperty, len(pairs))
+	for i, pair := range pairs {
+		// With no EvalContext, the key is the literal property name.
+		name, _ := pair.Key.Value(nil)
+		ret[i] = jsonProperty{
+			Name:      name.AsString(),
+			NameRange: pair.Key.Range(),
+			Value:     v.child(pair.Value),
+		}
+	}
+	return ret
+}
+
+// deepProperties takes either a single object or an array of objects and
+// flattens it into a list of object properties, in the same way as the
+// hcl/json package does for objects representing bodies or block labels.
+//
+// The labelName argument, if non-nil, is used to tailor returned error
+// messages to refer to block labels rather than attributes and child blocks.
+// It has no other effect.
+func (v jsonValue) deepProperties(labelName *string) ([]jsonProperty, hcl.Diagnostics) {
+	var props []jsonProperty
+	var diags hcl.Diagnostics
+
+	switch v.kind() {
+	case 'n':
+		// If a value is null, then we don't return any properties or return an error.
+
+	case '{':
+		props = append(props, v.properties()...)
+
+	case '[':
+		for _, elem := range v.elements() {
+			if elem.kind() == '{' {
+				props = append(props, elem.properties()...)
+				continue
+			}
+			if labelName != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Incorrect JSON value type",
+					Detail:   fmt.Sprintf("A JSON object is required here, to specify %s labels for this block.", *labelName),
+					Subject:  elem.expr.StartRange().Ptr(),
+				})
+			} else {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Incorrect JSON value type",
+					Detail:   "A JSON object is required here, to define arguments and child blocks.",
+					Subject:  elem.expr.StartRange().Ptr(),
+				})
+			}
+		}
+
+	default:
+		if labelName != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Incorrect JSON value type",
+				Detail:   fmt.Sprintf("Either a JSON object or JSON array of objects is required here, to specify %s labels for this block.", *labelName),
+				Subject:  v.expr.StartRange().Ptr(),
+			})
+		} else {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Incorrect JSON value type",
+				Detail:   "Either a JSON object or JSON array of objects is required here, to define arguments and child blocks.",
+				Subject:  v.expr.StartRange().Ptr(),
+			})
+		}
+	}
+
+	return props, diags
+}
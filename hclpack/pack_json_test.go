@@ -0,0 +1,307 @@
+package hclpack
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/hcl2/hcl"
+	hclJSON "github.com/hashicorp/hcl2/hcl/json"
+	"github.com/hashicorp/hcl2/hcldec"
+)
+
+func TestPackJSONFile(t *testing.T) {
+	spec := hcldec.ObjectSpec{
+		"name": &hcldec.AttrSpec{
+			Name: "name",
+			Type: cty.String,
+		},
+		"tags": &hcldec.AttrSpec{
+			Name: "tags",
+			Type: cty.Map(cty.String),
+		},
+		"services": &hcldec.BlockMapSpec{
+			TypeName:   "service",
+			LabelNames: []string{"name"},
+			Nested: hcldec.ObjectSpec{
+				"port": &hcldec.AttrSpec{
+					Name: "port",
+					Type: cty.Number,
+				},
+				"checks": &hcldec.BlockListSpec{
+					TypeName: "check",
+					Nested: &hcldec.AttrSpec{
+						Name: "path",
+						Type: cty.String,
+					},
+				},
+			},
+		},
+		"rules": &hcldec.BlockListSpec{
+			TypeName: "rule",
+			Nested: &hcldec.AttrSpec{
+				Name: "action",
+				Type: cty.String,
+			},
+		},
+	}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"env": cty.StringVal("prod"),
+		},
+	}
+
+	tests := map[string]struct {
+		src       string
+		wantError bool
+	}{
+		"empty": {
+			`{}`,
+			false,
+		},
+		"attributes": {
+			`{"//": "comment", "name": "app-${env}", "tags": {"${env}": "yes"}}`,
+			false,
+		},
+		"blocks": {
+			`{
+  "name": "app",
+  "service": {
+    "web": {"port": 80, "check": [{"path": "/a"}, {"path": "/b"}]},
+    "db": {"port": "${5432}"}
+  },
+  "rule": [{"action": "allow"}, null, {"action": "deny"}]
+}`,
+			false,
+		},
+		"array root": {
+			`[{"name": "app"}, {"rule": {"action": "allow"}}, {"service": [{"web": {}}, {"db": null}]}]`,
+			false,
+		},
+		"repeated blocks": {
+			`{
+  "rule": {"action": "allow"},
+  "service": {"web": {"port": 80}},
+  "name": "app",
+  "rule": [{"action": "log"}, {"action": "deny"}],
+  "service": {"db": {"port": 5432}},
+  "rule": {"action": "audit"}
+}`,
+			false,
+		},
+		"repeated blocks in array root": {
+			`[{"rule": {"action": "allow"}, "name": "app"}, {"rule": {"action": "deny"}}]`,
+			false,
+		},
+		"repeated attribute": {
+			`{"name": "a", "name": "b"}`,
+			true,
+		},
+		"missing label": {
+			`{"service": {}}`,
+			true,
+		},
+		"wrong block type": {
+			`{"rule": "allow"}`,
+			true,
+		},
+		"wrong attribute type": {
+			`{"tags": ["a"]}`,
+			true,
+		},
+		"unknown property": {
+			`{"nope": true}`,
+			true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, diags := hclJSON.Parse([]byte(test.src), "test.hcl.json")
+			if diags.HasErrors() {
+				t.Fatalf("unexpected parse errors: %s", diags.Error())
+			}
+			wantVal, wantDiags := hcldec.Decode(f.Body, spec, ctx)
+
+			packed, diags := PackJSONFile([]byte(test.src), "test.hcl.json", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected pack errors: %s", diags.Error())
+			}
+
+			// The packed body must also survive serialization.
+			jb, err := packed.MarshalJSON()
+			if err != nil {
+				t.Fatalf("failed to marshal: %s", err)
+			}
+			unpacked := &Body{}
+			if err := unpacked.UnmarshalJSON(jb); err != nil {
+				t.Fatalf("failed to unmarshal: %s", err)
+			}
+
+			gotVal, gotDiags := hcldec.Decode(unpacked, spec, ctx)
+
+			if got, want := gotDiags.HasErrors(), wantDiags.HasErrors(); got != want || got != test.wantError {
+				t.Fatalf("wrong error status\ngot:  %s\nwant: %s", gotDiags.Error(), wantDiags.Error())
+			}
+			if test.wantError {
+				return
+			}
+			if !gotVal.RawEquals(wantVal) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", gotVal, wantVal)
+			}
+		})
+	}
+}
+
+func TestPackJSONFileRanges(t *testing.T) {
+	src := `{
+  "foo": "bar",
+  "thing": {
+    "a": {"baz": 1},
+    "b": [{"baz": 2}]
+  },
+  "thing": {
+    "c": {"baz": 3}
+  }
+}`
+	schema := &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "foo"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "thing", LabelNames: []string{"name"}},
+		},
+	}
+
+	f, diags := hclJSON.Parse([]byte(src), "test.hcl.json")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", diags.Error())
+	}
+	packed, diags := PackJSONFile([]byte(src), "test.hcl.json", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected pack errors: %s", diags.Error())
+	}
+
+	want, diags := f.Body.Content(schema)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors from original body: %s", diags.Error())
+	}
+	got, diags := packed.Content(schema)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors from packed body: %s", diags.Error())
+	}
+
+	if got, want := got.MissingItemRange, want.MissingItemRange; got != want {
+		t.Errorf("wrong MissingItemRange\ngot:  %#v\nwant: %#v", got, want)
+	}
+	for name, wantAttr := range want.Attributes {
+		gotAttr := got.Attributes[name]
+		if gotAttr == nil {
+			t.Errorf("missing attribute %q", name)
+			continue
+		}
+		gotRanges := []hcl.Range{gotAttr.Range, gotAttr.NameRange, gotAttr.Expr.Range(), gotAttr.Expr.StartRange()}
+		wantRanges := []hcl.Range{wantAttr.Range, wantAttr.NameRange, wantAttr.Expr.Range(), wantAttr.Expr.StartRange()}
+		if !cmp.Equal(gotRanges, wantRanges) {
+			t.Errorf("wrong ranges for attribute %q\n%s", name, cmp.Diff(wantRanges, gotRanges))
+		}
+	}
+	if len(got.Blocks) != len(want.Blocks) {
+		t.Fatalf("wrong number of blocks %d; want %d", len(got.Blocks), len(want.Blocks))
+	}
+	for i, wantBlock := range want.Blocks {
+		gotBlock := got.Blocks[i]
+		if !cmp.Equal(gotBlock.Labels, wantBlock.Labels) {
+			t.Errorf("wrong labels for block %d\n%s", i, cmp.Diff(wantBlock.Labels, gotBlock.Labels))
+		}
+		gotRanges := append([]hcl.Range{gotBlock.DefRange, gotBlock.TypeRange, gotBlock.Body.MissingItemRange()}, gotBlock.LabelRanges...)
+		wantRanges := append([]hcl.Range{wantBlock.DefRange, wantBlock.TypeRange, wantBlock.Body.MissingItemRange()}, wantBlock.LabelRanges...)
+		if !cmp.Equal(gotRanges, wantRanges) {
+			t.Errorf("wrong ranges for block %d\n%s", i, cmp.Diff(wantRanges, gotRanges))
+		}
+	}
+}
+
+func TestPackJSONFileDuplicateArgument(t *testing.T) {
+	src := `{"foo": "a", "bar": 1, "foo": "b"}`
+	schema := &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "foo"},
+			{Name: "bar"},
+		},
+	}
+
+	f, diags := hclJSON.Parse([]byte(src), "test.hcl.json")
+	if diags.HasErrors() {
+		t.Fatalf("unexpected parse errors: %s", diags.Error())
+	}
+	packed, diags := PackJSONFile([]byte(src), "test.hcl.json", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("unexpected pack errors: %s", diags.Error())
+	}
+
+	// The packed body must report the duplicate in the same way as the
+	// original, and otherwise use the first definition.
+	want, wantDiags := f.Body.Content(schema)
+	got, gotDiags := packed.Content(schema)
+	if len(gotDiags) != 1 || len(wantDiags) != 1 {
+		t.Fatalf("wrong diagnostics\ngot:  %s\nwant: %s", gotDiags.Error(), wantDiags.Error())
+	}
+	if got, want := gotDiags[0].Summary, wantDiags[0].Summary; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+	if got, want := gotDiags[0].Detail, wantDiags[0].Detail; got != want {
+		t.Errorf("wrong detail %q; want %q", got, want)
+	}
+	if got, want := *gotDiags[0].Subject, *wantDiags[0].Subject; got != want {
+		t.Errorf("wrong subject %#v; want %#v", got, want)
+	}
+	for _, name := range []string{"foo", "bar"} {
+		gotVal, _ := got.Attributes[name].Expr.Value(nil)
+		wantVal, _ := want.Attributes[name].Expr.Value(nil)
+		if !gotVal.RawEquals(wantVal) {
+			t.Errorf("wrong value for %s %#v; want %#v", name, gotVal, wantVal)
+		}
+		if got, want := got.Attributes[name].Range, want.Attributes[name].Range; got != want {
+			t.Errorf("wrong range for %s %#v; want %#v", name, got, want)
+		}
+	}
+
+	_, gotDiags = packed.JustAttributes()
+	_, wantDiags = f.Body.JustAttributes()
+	if len(gotDiags) != 1 || len(wantDiags) != 1 {
+		t.Fatalf("wrong diagnostics\ngot:  %s\nwant: %s", gotDiags.Error(), wantDiags.Error())
+	}
+	if got, want := gotDiags[0].Summary, wantDiags[0].Summary; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+}
+
+func TestPackJSONFileErrors(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		want string
+	}{
+		"syntax error": {
+			`{"foo": }`,
+			"Missing JSON value",
+		},
+		"root not object": {
+			`"foo"`,
+			"Root value must be object",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, diags := PackJSONFile([]byte(test.src), "test.hcl.json", hcl.Pos{Line: 1, Column: 1})
+			if !diags.HasErrors() {
+				t.Fatalf("unexpected success; want %q error", test.want)
+			}
+			if got := diags[0].Summary; got != test.want {
+				t.Errorf("wrong error summary\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package hclpack
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+// SourceBundle is a set of source files, keyed by filename, that can be
+// serialized along with a packed body so that a recipient can render
+// diagnostics that include snippets of the original source code, without
+// needing access to the original files.
+//
+// When serialized, the contents of files that are identical are stored only
+// once and the result is compressed.
+type SourceBundle map[string][]byte
+
+// AddSource records the given source code for the file with the given name
+// in the body's Sources, so that it will be serialized along with the body.
+//
+// The same source code that was given to PackNativeFile or PackJSONFile
+// should be used, so that the source ranges in the body are correct.
+func (b *Body) AddSource(filename string, src []byte) {
+	if b.Sources == nil {
+		b.Sources = make(SourceBundle)
+	}
+	b.Sources[filename] = src
+}
+
+// Files returns an hcl.File for each file in the bundle, suitable for use with
+// hcl.NewDiagnosticTextWriter. Only the Bytes field of each file is populated.
+func (sb SourceBundle) Files() map[string]*hcl.File {
+	ret := make(map[string]*hcl.File, len(sb))
+	for fn, src := range sb {
+		ret[fn] = &hcl.File{
+			Bytes: src,
+		}
+	}
+	return ret
+}
+
+// pack serializes the bundle in a compact form, returning nil if the bundle
+// is empty.
+//
+// The result is a compressed sequence of the filenames in lexical order,
+// each followed by an index into the list of distinct file contents that
+// then follows.
+func (sb SourceBundle) pack() ([]byte, error) {
+	if len(sb) == 0 {
+		return nil, nil
+	}
+
+	fns := make([]string, 0, len(sb))
+	for fn := range sb {
+		fns = append(fns, fn)
+	}
+	sort.Strings(fns)
+
+	var contents [][]byte
+	contentIdx := make(map[string]int)
+	buf := newVLQBuf(len(fns) * 16)
+	buf = buf.AppendInt(len(fns))
+	for _, fn := range fns {
+		src := sb[fn]
+		idx, exists := contentIdx[string(src)]
+		if !exists {
+			idx = len(contents)
+			contents = append(contents, src)
+			contentIdx[string(src)] = idx
+		}
+		buf = buf.AppendBytes([]byte(fn))
+		buf = buf.AppendInt(idx)
+	}
+	buf = buf.AppendInt(len(contents))
+	for _, src := range contents {
+		buf = buf.AppendBytes(src)
+	}
+
+	var compressed bytes.Buffer
+	w, err := flate.NewWriter(&compressed, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return compressed.Bytes(), nil
+}
+
+// maxSourceBundleSize is the largest size of the uncompressed form of a
+// source bundle that unpackSourceBundle will accept, so that a small amount
+// of maliciously-crafted compressed data cannot exhaust the available memory.
+var maxSourceBundleSize = 64 << 20 // 64MiB
+
+// unpackSourceBundle is the opposite of SourceBundle.pack, returning a nil
+// bundle if the given data is empty.
+func unpackSourceBundle(data []byte) (SourceBundle, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	r := flate.NewReader(bytes.NewReader(data))
+	raw, err := ioutil.ReadAll(io.LimitReader(r, int64(maxSourceBundleSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > maxSourceBundleSize {
+		return nil, fmt.Errorf("source bundle is larger than the maximum of %d bytes", maxSourceBundleSize)
+	}
+	buf := vlqBuf(raw)
+
+	fnCount, buf, err := buf.ReadInt()
+	if err != nil {
+		return nil, err
+	}
+	if fnCount < 0 || fnCount > len(buf) {
+		return nil, errors.New("invalid file count in source bundle")
+	}
+	fns := make([]string, fnCount)
+	idxs := make([]int, fnCount)
+	for i := range fns {
+		var fn []byte
+		fn, buf, err = buf.ReadBytes()
+		if err != nil {
+			return nil, err
+		}
+		fns[i] = string(fn)
+		idxs[i], buf, err = buf.ReadInt()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	contentCount, buf, err := buf.ReadInt()
+	if err != nil {
+		return nil, err
+	}
+	if contentCount < 0 || contentCount > len(buf) {
+		return nil, errors.New("invalid content count in source bundle")
+	}
+	contents := make([][]byte, contentCount)
+	for i := range contents {
+		contents[i], buf, err = buf.ReadBytes()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(buf) != 0 {
+		return nil, errors.New("extraneous data after source bundle")
+	}
+
+	ret := make(SourceBundle, fnCount)
+	for i, fn := range fns {
+		idx := idxs[i]
+		if idx < 0 || idx >= len(contents) {
+			return nil, errors.New("invalid content index in source bundle")
+		}
+		ret[fn] = contents[idx]
+	}
+	return ret, nil
+}
@@ -0,0 +1,178 @@
+package hclpack
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+func TestSourceBundleRoundTrip(t *testing.T) {
+	src := []byte(`
+service "example" {
+  priority = 2
+}
+`)
+	body, diags := PackNativeFile(src, "example.svc", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("Failed to parse: %s", diags.Error())
+	}
+	body.AddSource("example.svc", src)
+	body.AddSource("copy.svc", src)
+	body.AddSource("empty.svc", []byte{})
+
+	tests := map[string]struct {
+		marshal   func() ([]byte, error)
+		unmarshal func(*Body, []byte) error
+	}{
+		"JSON": {
+			body.MarshalJSON,
+			(*Body).UnmarshalJSON,
+		},
+		"binary": {
+			body.MarshalBinary,
+			(*Body).UnmarshalBinary,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := test.marshal()
+			if err != nil {
+				t.Fatalf("Failed to marshal: %s", err)
+			}
+			got := &Body{}
+			if err := test.unmarshal(got, data); err != nil {
+				t.Fatalf("Failed to unmarshal: %s", err)
+			}
+			if !cmp.Equal(body, got) {
+				t.Errorf("incorrect result\n%s", cmp.Diff(body, got))
+			}
+		})
+	}
+}
+
+func TestSourceBundlePack(t *testing.T) {
+	src := bytes.Repeat([]byte("foo = \"bar\"\n"), 100)
+	one, err := SourceBundle{"a.hcl": src}.pack()
+	if err != nil {
+		t.Fatalf("Failed to pack: %s", err)
+	}
+	two, err := SourceBundle{"a.hcl": src, "b.hcl": src}.pack()
+	if err != nil {
+		t.Fatalf("Failed to pack: %s", err)
+	}
+
+	if len(one) >= len(src) {
+		t.Errorf("packed bundle is %d bytes, but the source is only %d bytes", len(one), len(src))
+	}
+	// The second file has the same contents as the first, so it should add
+	// only its filename and index.
+	if len(two)-len(one) > 10 {
+		t.Errorf("second copy of the source added %d bytes", len(two)-len(one))
+	}
+
+	empty, err := SourceBundle{}.pack()
+	if err != nil {
+		t.Fatalf("Failed to pack: %s", err)
+	}
+	if empty != nil {
+		t.Errorf("empty bundle packed as %#v; want nil", empty)
+	}
+}
+
+func TestSourceBundleUnpackTooLarge(t *testing.T) {
+	defer func(max int) {
+		maxSourceBundleSize = max
+	}(maxSourceBundleSize)
+	maxSourceBundleSize = 1000
+
+	// The repeated content compresses to far less than the limit, so only
+	// its uncompressed size can be checked.
+	packed, err := SourceBundle{"a.hcl": bytes.Repeat([]byte{'#'}, 2000)}.pack()
+	if err != nil {
+		t.Fatalf("Failed to pack: %s", err)
+	}
+	if len(packed) >= maxSourceBundleSize {
+		t.Fatalf("packed bundle is %d bytes; want less than the limit", len(packed))
+	}
+
+	_, err = unpackSourceBundle(packed)
+	if err == nil {
+		t.Fatalf("unexpected success; want error for oversized bundle")
+	}
+	if got, want := err.Error(), "source bundle is larger than the maximum of 1000 bytes"; got != want {
+		t.Errorf("wrong error %q; want %q", got, want)
+	}
+
+	packed, err = SourceBundle{"a.hcl": bytes.Repeat([]byte{'#'}, 900)}.pack()
+	if err != nil {
+		t.Fatalf("Failed to pack: %s", err)
+	}
+	if _, err := unpackSourceBundle(packed); err != nil {
+		t.Errorf("unexpected error for bundle within the limit: %s", err)
+	}
+}
+
+func TestSourceBundleFiles(t *testing.T) {
+	src := []byte("foo = bar\n")
+	body, diags := PackNativeFile(src, "test.hcl", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("Failed to parse: %s", diags.Error())
+	}
+	body.AddSource("test.hcl", src)
+
+	data, err := body.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %s", err)
+	}
+	got := &Body{}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Failed to unmarshal: %s", err)
+	}
+
+	attrs, diags := got.JustAttributes()
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	_, diags = attrs["foo"].Expr.Value(nil)
+	if !diags.HasErrors() {
+		t.Fatalf("unexpected success; want error for variable with no context")
+	}
+
+	var buf bytes.Buffer
+	wr := hcl.NewDiagnosticTextWriter(&buf, got.Sources.Files(), 78, false)
+	if err := wr.WriteDiagnostics(diags); err != nil {
+		t.Fatalf("Failed to write diagnostics: %s", err)
+	}
+	if got, want := buf.String(), "1: foo = bar"; !strings.Contains(got, want) {
+		t.Errorf("diagnostics do not include the source snippet %q\n%s", want, got)
+	}
+}
+
+func TestBodyUnmarshalBinaryVersion1(t *testing.T) {
+	body, diags := PackNativeFile([]byte(`foo = "bar"`), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		t.Fatalf("Failed to parse: %s", diags.Error())
+	}
+	data, err := body.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal: %s", err)
+	}
+
+	// Version 1 is the same as version 2 except that it has no source bundle,
+	// which in this case is just a zero length at the end.
+	v1 := append([]byte{}, data[:len(data)-1]...)
+	copy(v1[len(binaryMagic):], vlqBuf(nil).AppendInt(1))
+
+	got := &Body{}
+	if err := got.UnmarshalBinary(v1); err != nil {
+		t.Fatalf("Failed to unmarshal: %s", err)
+	}
+	if !cmp.Equal(body, got) {
+		t.Errorf("incorrect result\n%s", cmp.Diff(body, got))
+	}
+}
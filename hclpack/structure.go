@@ -2,6 +2,7 @@ package hclpack
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/hashicorp/hcl2/hcl"
 )
@@ -12,6 +13,15 @@ type Body struct {
 	ChildBlocks []Block
 
 	MissingItemRange_ hcl.Range
+
+	// Sources optionally holds the source code of the files that the body
+	// was packed from, which is then serialized along with the body so that
+	// the recipient can include source code snippets in diagnostics. See
+	// AddSource.
+	//
+	// Sources is used only on the body being serialized, and is ignored for
+	// the bodies of its child blocks.
+	Sources SourceBundle
 }
 
 var _ hcl.Body = (*Body)(nil)
@@ -70,14 +80,28 @@ func (b *Body) content(schema *hcl.BodySchema, remain *Body) (*hcl.BodyContent,
 			continue
 		}
 
-		attrs[name] = attr.asHCLAttribute(name)
+		hclAttr, attrDiags := attr.asArgument(name, "Duplicate argument")
+		diags = append(diags, attrDiags...)
+		attrs[name] = hclAttr
 		attrUsed[name] = struct{}{}
 	}
 
+	blocksWanted := make(map[string]hcl.BlockHeaderSchema)
+	for _, blockS := range schema.Blocks {
+		blocksWanted[blockS.Type] = blockS
+	}
+
+	var jsonBlockAttrs []string
 	for name, attr := range b.Attributes {
 		if _, used := attrUsed[name]; used {
 			continue
 		}
+		if _, wanted := blocksWanted[name]; wanted && attr.Expr.SourceType == ExprJSON {
+			// This attribute is actually a definition of blocks, as
+			// described for PackJSONFile. We'll deal with it below.
+			jsonBlockAttrs = append(jsonBlockAttrs, name)
+			continue
+		}
 		if remain != nil {
 			remain.setAttribute(name, attr)
 			continue
@@ -110,11 +134,6 @@ func (b *Body) content(schema *hcl.BodySchema, remain *Body) (*hcl.BodyContent,
 		})
 	}
 
-	blocksWanted := make(map[string]hcl.BlockHeaderSchema)
-	for _, blockS := range schema.Blocks {
-		blocksWanted[blockS.Type] = blockS
-	}
-
 	var blocks []*hcl.Block
 	for _, block := range b.ChildBlocks {
 		// Redeclare block on stack so the pointer to the body is set on the
@@ -183,6 +202,27 @@ func (b *Body) content(schema *hcl.BodySchema, remain *Body) (*hcl.BodyContent,
 		blocks = append(blocks, block.asHCLBlock())
 	}
 
+	// The blocks defined by JSON attributes are returned in the order they
+	// were defined in the source code, as the hcl/json package would. The
+	// definitions of an attribute defined more than once may be interleaved
+	// with those of others, so we must sort the blocks themselves.
+	sort.Slice(jsonBlockAttrs, func(i, j int) bool {
+		return b.Attributes[jsonBlockAttrs[i]].NameRange.Start.Byte < b.Attributes[jsonBlockAttrs[j]].NameRange.Start.Byte
+	})
+	var jsonBlocks []Block
+	for _, name := range jsonBlockAttrs {
+		attr := b.Attributes[name]
+		moreBlocks, blockDiags := attr.jsonBlocks(name, blocksWanted[name].LabelNames)
+		diags = append(diags, blockDiags...)
+		jsonBlocks = append(jsonBlocks, moreBlocks...)
+	}
+	sort.SliceStable(jsonBlocks, func(i, j int) bool {
+		return jsonBlocks[i].TypeRange.Start.Byte < jsonBlocks[j].TypeRange.Start.Byte
+	})
+	for i := range jsonBlocks {
+		blocks = append(blocks, jsonBlocks[i].asHCLBlock())
+	}
+
 	return &hcl.BodyContent{
 		Attributes:       attrs,
 		Blocks:           blocks,
@@ -212,7 +252,9 @@ func (b *Body) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
 
 	ret := make(hcl.Attributes, len(b.Attributes))
 	for n, a := range b.Attributes {
-		ret[n] = a.asHCLAttribute(n)
+		attr, attrDiags := a.asArgument(n, "Duplicate attribute definition")
+		diags = append(diags, attrDiags...)
+		ret[n] = attr
 	}
 	return ret, diags
 }
@@ -48,3 +48,28 @@ func (b vlqBuf) AppendRawByte(by byte) vlqBuf {
 func (b vlqBuf) Bytes() []byte {
 	return []byte(b)
 }
+
+// AppendBytes appends the given bytes prefixed by their length, so that they
+// can be read back with ReadBytes.
+func (b vlqBuf) AppendBytes(bs []byte) vlqBuf {
+	b = b.AppendInt(len(bs))
+	return append(b, bs...)
+}
+
+func (b vlqBuf) ReadBytes() ([]byte, vlqBuf, error) {
+	l, b, err := b.ReadInt()
+	if err != nil {
+		return nil, b, err
+	}
+	if l < 0 || l > len(b) {
+		return nil, b, errors.New("invalid length for byte sequence")
+	}
+	return []byte(b[:l]), b[l:], nil
+}
+
+func (b vlqBuf) ReadRawByte() (byte, vlqBuf, error) {
+	if len(b) == 0 {
+		return 0, b, errors.New("missing expected byte")
+	}
+	return b[0], b[1:], nil
+}
@@ -0,0 +1,226 @@
+package hclparse
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hcl/json"
+)
+
+// DirOptions customizes the behavior of Parser.ParseDir. The zero value
+// selects the default behavior.
+type DirOptions struct {
+	// NativeExtensions and JSONExtensions are the filename suffixes that
+	// identify files to be parsed as native syntax and as JSON respectively.
+	// If a filename matches extensions of both kinds then the longest match
+	// wins, so that e.g. ".hcl.json" takes priority over ".hcl".
+	//
+	// If both are empty, the defaults are ".hcl" for native syntax and
+	// ".hcl.json" for JSON.
+	NativeExtensions []string
+	JSONExtensions   []string
+
+	// OverrideSuffix, if set, identifies override files, whose names end
+	// with this suffix before their extension. For example, the suffix
+	// "_override" identifies both "foo_override.hcl" and
+	// "bar_override.hcl.json".
+	//
+	// The content of an override file takes precedence over the content of
+	// the other files, rather than being merged with it. Each attribute in an
+	// override file replaces any attribute of the same name, and each block
+	// is merged into any existing blocks of the same type and with the same
+	// labels using the same rules, or is added if there are no such blocks.
+	OverrideSuffix string
+
+	// JSONOptions are passed to the JSON parser. See json.ParseOptions.
+	JSONOptions json.ParseOptions
+}
+
+// dirCacheEntry is the result of parsing a file in ParseDir, retained so that
+// it can be reused if the file has not changed when it is loaded again.
+type dirCacheEntry struct {
+	hash  [sha256.Size]byte
+	json  bool
+	opts  json.ParseOptions
+	file  *hcl.File
+	diags hcl.Diagnostics
+}
+
+// ParseDir parses all of the configuration files in the given directory, as
+// identified by the extensions in the given options, and returns them along
+// with a body that merges all of their content.
+//
+// Files are parsed in lexical order of their names, except that any override
+// files, as described in DirOptions, are ordered after all of the others.
+// The returned files are in the same order. Files whose names begin with a
+// period or a "#", or end with a "~", are ignored, since they are often
+// hidden files or temporary files created by text editors. Subdirectories
+// are not searched.
+//
+// Unlike the other methods of Parser, ParseDir reads each file again on every
+// call, so that it can be used to reload a directory that has changed. To
+// avoid the cost of parsing files that have not changed since the previous
+// call, the parser retains the result of parsing each file along with a hash
+// of its content, and returns the same file and diagnostics for a file whose
+// content has not changed. The parser's registry of files is updated with
+// the latest version of each file that has changed, and files that were
+// loaded from the directory before but no longer exist are removed from it.
+func (p *Parser) ParseDir(dir string, opts DirOptions) ([]*hcl.File, hcl.Body, hcl.Diagnostics) {
+	nativeExts, jsonExts := opts.NativeExtensions, opts.JSONExtensions
+	if len(nativeExts) == 0 && len(jsonExts) == 0 {
+		nativeExts = []string{".hcl"}
+		jsonExts = []string{".hcl.json"}
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, hcl.EmptyBody(), hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to read directory",
+				Detail:   fmt.Sprintf("The configuration directory %q could not be read.", dir),
+			},
+		}
+	}
+
+	type dirFile struct {
+		filename string
+		json     bool
+	}
+	var primary, override []dirFile
+	for _, info := range infos { // ReadDir returns the entries sorted by name
+		name := info.Name()
+		if info.IsDir() || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "#") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		nativeExt := longestSuffix(name, nativeExts)
+		jsonExt := longestSuffix(name, jsonExts)
+		if nativeExt == "" && jsonExt == "" {
+			continue
+		}
+		ext := nativeExt
+		isJSON := len(jsonExt) > len(nativeExt)
+		if isJSON {
+			ext = jsonExt
+		}
+
+		f := dirFile{
+			filename: filepath.Join(dir, name),
+			json:     isJSON,
+		}
+		if opts.OverrideSuffix != "" && strings.HasSuffix(strings.TrimSuffix(name, ext), opts.OverrideSuffix) {
+			override = append(override, f)
+		} else {
+			primary = append(primary, f)
+		}
+	}
+
+	seen := make(map[string]struct{}, len(primary)+len(override))
+	for _, fs := range [][]dirFile{primary, override} {
+		for _, f := range fs {
+			seen[f.filename] = struct{}{}
+		}
+	}
+	defer p.pruneDirCache(filepath.Clean(dir), seen)
+
+	var diags hcl.Diagnostics
+	var files []*hcl.File
+	for _, f := range primary {
+		file, fileDiags := p.parseDirFile(f.filename, f.json, opts.JSONOptions)
+		diags = append(diags, fileDiags...)
+		if file != nil {
+			files = append(files, file)
+		}
+	}
+	body := hcl.MergeFiles(files)
+	for _, f := range override {
+		file, fileDiags := p.parseDirFile(f.filename, f.json, opts.JSONOptions)
+		diags = append(diags, fileDiags...)
+		if file != nil {
+			files = append(files, file)
+			body = overrideBody{
+				base:     body,
+				override: file.Body,
+			}
+		}
+	}
+
+	return files, body, diags
+}
+
+// parseDirFile reads and parses the given file for ParseDir, reusing the
+// previous result if the file has not changed.
+func (p *Parser) parseDirFile(filename string, isJSON bool, opts json.ParseOptions) (*hcl.File, hcl.Diagnostics) {
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, hcl.Diagnostics{
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Failed to read file",
+				Detail:   fmt.Sprintf("The configuration file %q could not be read.", filename),
+			},
+		}
+	}
+
+	if !isJSON {
+		opts = json.ParseOptions{} // irrelevant for native syntax
+	}
+	hash := sha256.Sum256(src)
+	if entry, cached := p.dirCache[filename]; cached {
+		if entry.hash == hash && entry.json == isJSON && entry.opts == opts {
+			return entry.file, entry.diags
+		}
+	}
+
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if isJSON {
+		file, diags = json.ParseWithOptions(src, filename, opts)
+	} else {
+		file, diags = hclsyntax.ParseConfig(src, filename, hcl.Pos{Byte: 0, Line: 1, Column: 1})
+	}
+
+	p.dirCache[filename] = dirCacheEntry{
+		hash:  hash,
+		json:  isJSON,
+		opts:  opts,
+		file:  file,
+		diags: diags,
+	}
+	p.files[filename] = file
+	return file, diags
+}
+
+// pruneDirCache forgets the files in the given directory that ParseDir
+// previously parsed but did not find on its latest call, because they have
+// since been deleted or no longer match the options.
+func (p *Parser) pruneDirCache(dir string, seen map[string]struct{}) {
+	for filename, entry := range p.dirCache {
+		if _, ok := seen[filename]; ok || filepath.Dir(filename) != dir {
+			continue
+		}
+		delete(p.dirCache, filename)
+		// The registry may have since been updated with a file of the same
+		// name parsed by some other means, which we must retain.
+		if p.files[filename] == entry.file {
+			delete(p.files, filename)
+		}
+	}
+}
+
+// longestSuffix returns the longest of the given suffixes that the given name
+// ends with, or the empty string if there is none.
+func longestSuffix(name string, suffixes []string) string {
+	var ret string
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) && len(suffix) > len(ret) {
+			ret = suffix
+		}
+	}
+	return ret
+}
@@ -0,0 +1,202 @@
+package hclparse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+func TestParseDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hclparse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name, src string) {
+		t.Helper()
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("b.hcl", `
+name = "example"
+service "web" {
+  port = 80
+}
+`)
+	writeFile("a.hcl.json", `{"owner": "ops"}`)
+	writeFile("c_override.hcl", `
+name = "overridden"
+service "web" {
+  port = 8080
+}
+service "db" {
+  port = 5432
+}
+`)
+	writeFile("notes.txt", `not = "config"`)
+	writeFile(".hidden.hcl", `hidden = true`)
+	writeFile("b.hcl~", `backup = true`)
+
+	schema := &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "name", Required: true},
+			{Name: "owner"},
+		},
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "service", LabelNames: []string{"name"}},
+		},
+	}
+	serviceSchema := &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{
+			{Name: "port", Required: true},
+		},
+	}
+
+	p := NewParser()
+	opts := DirOptions{OverrideSuffix: "_override"}
+	files, body, diags := p.ParseDir(dir, opts)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+
+	var gotNames []string
+	for _, f := range files {
+		gotNames = append(gotNames, f.Body.MissingItemRange().Filename)
+	}
+	wantNames := []string{
+		filepath.Join(dir, "a.hcl.json"),
+		filepath.Join(dir, "b.hcl"),
+		filepath.Join(dir, "c_override.hcl"),
+	}
+	if len(gotNames) != len(wantNames) {
+		t.Fatalf("wrong files\ngot:  %#v\nwant: %#v", gotNames, wantNames)
+	}
+	for i := range gotNames {
+		if gotNames[i] != wantNames[i] {
+			t.Fatalf("wrong files\ngot:  %#v\nwant: %#v", gotNames, wantNames)
+		}
+	}
+
+	content, diags := body.Content(schema)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	for name, want := range map[string]cty.Value{
+		"name":  cty.StringVal("overridden"),
+		"owner": cty.StringVal("ops"),
+	} {
+		got, diags := content.Attributes[name].Expr.Value(nil)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Error())
+		}
+		if !got.RawEquals(want) {
+			t.Errorf("wrong value for %s %#v; want %#v", name, got, want)
+		}
+	}
+
+	wantPorts := map[string]cty.Value{
+		"web": cty.NumberIntVal(8080),
+		"db":  cty.NumberIntVal(5432),
+	}
+	if len(content.Blocks) != len(wantPorts) {
+		t.Fatalf("got %d blocks; want %d", len(content.Blocks), len(wantPorts))
+	}
+	for i, block := range content.Blocks {
+		blockContent, diags := block.Body.Content(serviceSchema)
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Error())
+		}
+		got, _ := blockContent.Attributes["port"].Expr.Value(nil)
+		if want := wantPorts[block.Labels[0]]; !got.RawEquals(want) {
+			t.Errorf("wrong port for block %d %q %#v; want %#v", i, block.Labels[0], got, want)
+		}
+	}
+
+	// Loading the directory again should reuse the files that haven't
+	// changed, and parse the others again.
+	writeFile("b.hcl", `
+name = "changed"
+service "web" {
+  port = 80
+}
+`)
+	files2, _, diags := p.ParseDir(dir, opts)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	if files2[0] != files[0] {
+		t.Errorf("unchanged file a.hcl.json was parsed again")
+	}
+	if files2[1] == files[1] {
+		t.Errorf("changed file b.hcl was not parsed again")
+	}
+	if files2[2] != files[2] {
+		t.Errorf("unchanged file c_override.hcl was parsed again")
+	}
+	if got, want := p.Files()[filepath.Join(dir, "b.hcl")], files2[1]; got != want {
+		t.Errorf("parser registry has the old version of the changed file")
+	}
+
+	// Files that have been removed from the directory should be forgotten,
+	// but not files in other directories.
+	other := filepath.Join(dir, "other")
+	if err := os.Mkdir(other, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(filepath.Join("other", "d.hcl"), `name = "other"`)
+	if _, _, diags := p.ParseDir(other, opts); diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	if err := os.Remove(filepath.Join(dir, "c_override.hcl")); err != nil {
+		t.Fatal(err)
+	}
+	files3, _, diags := p.ParseDir(dir, opts)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Error())
+	}
+	if len(files3) != 2 {
+		t.Fatalf("got %d files; want 2", len(files3))
+	}
+	if _, exists := p.Files()[filepath.Join(dir, "c_override.hcl")]; exists {
+		t.Errorf("parser registry still has the removed file")
+	}
+	if _, exists := p.dirCache[filepath.Join(dir, "c_override.hcl")]; exists {
+		t.Errorf("parser cache still has the removed file")
+	}
+	if _, exists := p.Files()[filepath.Join(other, "d.hcl")]; !exists {
+		t.Errorf("parser registry is missing the file from the other directory")
+	}
+}
+
+func TestParseDirDiagnostics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hclparse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "bad.hcl"), []byte(`foo = `), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewParser()
+	for i := 0; i < 2; i++ {
+		// Cached files must produce the same diagnostics each time.
+		_, _, diags := p.ParseDir(dir, DirOptions{})
+		if !diags.HasErrors() {
+			t.Errorf("load %d: unexpected success; want syntax error", i)
+		}
+	}
+
+	_, _, diags := p.ParseDir(filepath.Join(dir, "nonexistent"), DirOptions{})
+	if !diags.HasErrors() {
+		t.Errorf("unexpected success for missing directory")
+	}
+}
@@ -0,0 +1,139 @@
+package hclparse
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+// overrideBody is a body whose content is that of base, except where it is
+// overridden by the content of override.
+//
+// Each attribute in the override body replaces any attribute of the same name
+// in the base body. Each block in the override body is merged into every
+// block in the base body of the same type and with the same labels, using
+// the same rules recursively, or is added after the base body's blocks if
+// there are no such blocks.
+type overrideBody struct {
+	base     hcl.Body
+	override hcl.Body
+}
+
+func (b overrideBody) Content(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Diagnostics) {
+	content, _, diags := b.content(schema, false)
+	return content, diags
+}
+
+func (b overrideBody) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Body, hcl.Diagnostics) {
+	return b.content(schema, true)
+}
+
+func (b overrideBody) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
+	attrs, diags := b.base.JustAttributes()
+	overrideAttrs, moreDiags := b.override.JustAttributes()
+	diags = append(diags, moreDiags...)
+
+	if attrs == nil {
+		attrs = make(hcl.Attributes)
+	}
+	for name, attr := range overrideAttrs {
+		attrs[name] = attr
+	}
+	return attrs, diags
+}
+
+func (b overrideBody) MissingItemRange() hcl.Range {
+	return b.base.MissingItemRange()
+}
+
+func (b overrideBody) content(schema *hcl.BodySchema, partial bool) (*hcl.BodyContent, hcl.Body, hcl.Diagnostics) {
+	// Either body can contribute a required attribute, so we'll check
+	// required attributes separately at the end, as for hcl.MergeBodies.
+	innerSchema := &hcl.BodySchema{
+		Blocks: schema.Blocks,
+	}
+	for _, attrS := range schema.Attributes {
+		attrS.Required = false
+		innerSchema.Attributes = append(innerSchema.Attributes, attrS)
+	}
+
+	var baseContent, overrideContent *hcl.BodyContent
+	var baseRemain, overrideRemain hcl.Body
+	var diags, moreDiags hcl.Diagnostics
+	if partial {
+		baseContent, baseRemain, diags = b.base.PartialContent(innerSchema)
+		overrideContent, overrideRemain, moreDiags = b.override.PartialContent(innerSchema)
+	} else {
+		baseContent, diags = b.base.Content(innerSchema)
+		overrideContent, moreDiags = b.override.Content(innerSchema)
+	}
+	diags = append(diags, moreDiags...)
+
+	content := &hcl.BodyContent{
+		Attributes:       map[string]*hcl.Attribute{},
+		MissingItemRange: baseContent.MissingItemRange,
+	}
+	for name, attr := range baseContent.Attributes {
+		content.Attributes[name] = attr
+	}
+	for name, attr := range overrideContent.Attributes {
+		content.Attributes[name] = attr
+	}
+
+	overrideUsed := make([]bool, len(overrideContent.Blocks))
+	for _, block := range baseContent.Blocks {
+		for i, overrideBlock := range overrideContent.Blocks {
+			if !sameBlockHeader(block, overrideBlock) {
+				continue
+			}
+			newBlock := *block
+			newBlock.Body = overrideBody{
+				base:     block.Body,
+				override: overrideBlock.Body,
+			}
+			block = &newBlock
+			overrideUsed[i] = true
+		}
+		content.Blocks = append(content.Blocks, block)
+	}
+	for i, block := range overrideContent.Blocks {
+		if !overrideUsed[i] {
+			content.Blocks = append(content.Blocks, block)
+		}
+	}
+
+	for _, attrS := range schema.Attributes {
+		if !attrS.Required {
+			continue
+		}
+		if content.Attributes[attrS.Name] == nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing required argument",
+				Detail:   fmt.Sprintf("The argument %q is required, but no definition was found.", attrS.Name),
+				Subject:  content.MissingItemRange.Ptr(),
+			})
+		}
+	}
+
+	var remain hcl.Body
+	if partial {
+		remain = overrideBody{
+			base:     baseRemain,
+			override: overrideRemain,
+		}
+	}
+	return content, remain, diags
+}
+
+func sameBlockHeader(a, b *hcl.Block) bool {
+	if a.Type != b.Type || len(a.Labels) != len(b.Labels) {
+		return false
+	}
+	for i := range a.Labels {
+		if a.Labels[i] != b.Labels[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -25,12 +25,17 @@ import (
 // multiple times would create a confusing result.
 type Parser struct {
 	files map[string]*hcl.File
+
+	// dirCache is used by ParseDir to avoid parsing files that have not
+	// changed since they were last loaded.
+	dirCache map[string]dirCacheEntry
 }
 
 // NewParser creates a new parser, ready to parse configuration files.
 func NewParser() *Parser {
 	return &Parser{
-		files: map[string]*hcl.File{},
+		files:    map[string]*hcl.File{},
+		dirCache: map[string]dirCacheEntry{},
 	}
 }
 
@@ -93,6 +98,31 @@ func (p *Parser) ParseJSONFile(filename string) (*hcl.File, hcl.Diagnostics) {
 	return file, diags
 }
 
+// ParseJSONWithOptions is like ParseJSON but passes the given options to the
+// JSON parser, such as to accept the relaxed dialect of JSON that permits
+// comments and trailing commas. See json.ParseOptions for details.
+func (p *Parser) ParseJSONWithOptions(src []byte, filename string, opts json.ParseOptions) (*hcl.File, hcl.Diagnostics) {
+	if existing := p.files[filename]; existing != nil {
+		return existing, nil
+	}
+
+	file, diags := json.ParseWithOptions(src, filename, opts)
+	p.files[filename] = file
+	return file, diags
+}
+
+// ParseJSONFileWithOptions is like ParseJSONFile but passes the given options
+// to the JSON parser, similarly to ParseJSONWithOptions.
+func (p *Parser) ParseJSONFileWithOptions(filename string, opts json.ParseOptions) (*hcl.File, hcl.Diagnostics) {
+	if existing := p.files[filename]; existing != nil {
+		return existing, nil
+	}
+
+	file, diags := json.ParseFileWithOptions(filename, opts)
+	p.files[filename] = file
+	return file, diags
+}
+
 // AddFile allows a caller to record in a parser a file that was parsed some
 // other way, thus allowing it to be included in the registry of sources.
 func (p *Parser) AddFile(filename string, file *hcl.File) {
@@ -124,6 +124,28 @@ func (b *Body) SetAttributeTraversal(name string, traversal hcl.Traversal) *Attr
 	return attr
 }
 
+// SetAttributeRaw either replaces the expression of an existing attribute
+// of the given name or adds a new attribute definition to the end of the body.
+//
+// The new expression is given as a sequence of tokens, such as those returned
+// by TokensForExpression, which are used verbatim. The caller must ensure
+// that the tokens represent a valid expression.
+//
+// The return value is the attribute that was either modified in-place or
+// created.
+func (b *Body) SetAttributeRaw(name string, tokens Tokens) *Attribute {
+	attr := b.GetAttribute(name)
+	expr := NewExpressionRaw(tokens)
+	if attr != nil {
+		attr.expr = attr.expr.ReplaceWith(expr)
+	} else {
+		attr = newAttribute()
+		attr.init(name, expr)
+		b.appendItem(attr)
+	}
+	return attr
+}
+
 // AppendBlock appends an existing block (which must not be already attached
 // to a body) to the end of the receiving body.
 func (b *Body) AppendBlock(block *Block) *Block {
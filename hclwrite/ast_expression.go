@@ -41,6 +41,24 @@ func NewExpressionLiteral(val cty.Value) *Expression {
 	return expr
 }
 
+// NewExpressionRaw constructs an expression containing the given raw tokens.
+//
+// There is no automatic validation that the given tokens produce a valid
+// expression. Callers of this function must take care to produce valid
+// expression tokens. Where possible, use the higher-level functions
+// NewExpressionLiteral or NewExpressionAbsTraversal instead.
+//
+// Because NewExpressionRaw does not interpret the given tokens in any way,
+// an expression created by NewExpressionRaw will produce an empty result
+// for calls to its method Variables, even if the given token sequence
+// contains a subslice that would normally be interpreted as a traversal under
+// parsing.
+func NewExpressionRaw(tokens Tokens) *Expression {
+	expr := newExpression()
+	expr.children.AppendUnstructuredTokens(tokens)
+	return expr
+}
+
 // NewExpressionAbsTraversal constructs an expression that represents the
 // given traversal, which must be absolute or this function will panic.
 func NewExpressionAbsTraversal(traversal hcl.Traversal) *Expression {
@@ -66,10 +84,7 @@ func NewExpressionAbsTraversal(traversal hcl.Traversal) *Expression {
 		case hcl.TraverseAttr:
 			tn := newTraverseName()
 			tn.children.AppendUnstructuredTokens(Tokens{
-				{
-					Type:  hclsyntax.TokenDot,
-					Bytes: []byte{'.'},
-				},
+				attrAccessToken(ts),
 			})
 			tn.name = tn.children.Append(newIdentifier(&Token{
 				Type:  hclsyntax.TokenIdent,
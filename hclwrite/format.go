@@ -235,7 +235,7 @@ func spaceAfterToken(subject, before, after *Token) bool {
 		// Don't split a function name from open paren in a call
 		return false
 
-	case subject.Type == hclsyntax.TokenDot || after.Type == hclsyntax.TokenDot:
+	case subject.Type == hclsyntax.TokenDot || after.Type == hclsyntax.TokenDot || subject.Type == hclsyntax.TokenQuestionDot || after.Type == hclsyntax.TokenQuestionDot:
 		// Don't use spaces around attribute access dots
 		return false
 
@@ -276,7 +276,7 @@ func spaceAfterToken(subject, before, after *Token) bool {
 			// Minus at the start of input must be a negation
 			return false
 
-		case hclsyntax.TokenOParen, hclsyntax.TokenOBrace, hclsyntax.TokenOBrack, hclsyntax.TokenEqual, hclsyntax.TokenColon, hclsyntax.TokenComma, hclsyntax.TokenQuestion:
+		case hclsyntax.TokenOParen, hclsyntax.TokenOBrace, hclsyntax.TokenOBrack, hclsyntax.TokenEqual, hclsyntax.TokenColon, hclsyntax.TokenComma, hclsyntax.TokenQuestion, hclsyntax.TokenFatArrow:
 			// Minus immediately after an opening bracket or separator must be a negation.
 			return false
 
@@ -288,7 +288,7 @@ func spaceAfterToken(subject, before, after *Token) bool {
 			// Minus immediately after another comparison operator must be negation.
 			return false
 
-		case hclsyntax.TokenAnd, hclsyntax.TokenOr, hclsyntax.TokenBang:
+		case hclsyntax.TokenAnd, hclsyntax.TokenOr, hclsyntax.TokenBang, hclsyntax.TokenDoubleQuestion:
 			// Minus immediately after logical operator doesn't make sense but probably intended as negation.
 			return false
 
@@ -296,6 +296,10 @@ func spaceAfterToken(subject, before, after *Token) bool {
 			return true
 		}
 
+	case subject.Type == hclsyntax.TokenBang:
+		// The logical NOT operator is always unary, so it has no space after it
+		return false
+
 	case subject.Type == hclsyntax.TokenOBrace || after.Type == hclsyntax.TokenCBrace:
 		// Unlike other bracket types, braces have spaces on both sides of them,
 		// both in single-line nested blocks foo { bar = baz } and in object
@@ -27,6 +27,18 @@ func TestFormat(t *testing.T) {
 			`a=b.c`,
 			`a = b.c`,
 		},
+		{
+			`a=b?.c.d`,
+			`a = b?.c.d`,
+		},
+		{
+			`a=b??c`,
+			`a = b ?? c`,
+		},
+		{
+			`a=0xFF+1_000*0o7`,
+			`a = 0xFF + 1_000 * 0o7`,
+		},
 		{
 			`a=b[c]`,
 			`a = b[c]`,
@@ -159,12 +159,12 @@ func appendTokensForValue(val cty.Value, toks Tokens) Tokens {
 
 func appendTokensForTraversal(traversal hcl.Traversal, toks Tokens) Tokens {
 	for _, step := range traversal {
-		appendTokensForTraversalStep(step, toks)
+		toks = appendTokensForTraversalStep(step, toks)
 	}
 	return toks
 }
 
-func appendTokensForTraversalStep(step hcl.Traverser, toks Tokens) {
+func appendTokensForTraversalStep(step hcl.Traverser, toks Tokens) Tokens {
 	switch ts := step.(type) {
 	case hcl.TraverseRoot:
 		toks = append(toks, &Token{
@@ -174,10 +174,7 @@ func appendTokensForTraversalStep(step hcl.Traverser, toks Tokens) {
 	case hcl.TraverseAttr:
 		toks = append(
 			toks,
-			&Token{
-				Type:  hclsyntax.TokenDot,
-				Bytes: []byte{'.'},
-			},
+			attrAccessToken(ts),
 			&Token{
 				Type:  hclsyntax.TokenIdent,
 				Bytes: []byte(ts.Name),
@@ -188,7 +185,7 @@ func appendTokensForTraversalStep(step hcl.Traverser, toks Tokens) {
 			Type:  hclsyntax.TokenOBrack,
 			Bytes: []byte{'['},
 		})
-		appendTokensForValue(ts.Key, toks)
+		toks = appendTokensForValue(ts.Key, toks)
 		toks = append(toks, &Token{
 			Type:  hclsyntax.TokenCBrack,
 			Bytes: []byte{']'},
@@ -196,6 +193,7 @@ func appendTokensForTraversalStep(step hcl.Traverser, toks Tokens) {
 	default:
 		panic(fmt.Sprintf("unsupported traversal step type %T", step))
 	}
+	return toks
 }
 
 func escapeQuotedStringLit(s string) []byte {
@@ -248,3 +246,19 @@ func appendRune(b []byte, r rune) []byte {
 	utf8.EncodeRune(ch, r)
 	return b
 }
+
+// attrAccessToken returns the token that introduces the given attribute
+// access step: either a dot or, for an optional attribute, the
+// safe-navigation operator "?.".
+func attrAccessToken(ts hcl.TraverseAttr) *Token {
+	if ts.Optional {
+		return &Token{
+			Type:  hclsyntax.TokenQuestionDot,
+			Bytes: []byte{'?', '.'},
+		}
+	}
+	return &Token{
+		Type:  hclsyntax.TokenDot,
+		Bytes: []byte{'.'},
+	}
+}
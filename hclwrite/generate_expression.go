@@ -0,0 +1,423 @@
+package hclwrite
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TokensForExpression returns a sequence of tokens that represents the given
+// native syntax expression, which may have been produced by the parser or
+// constructed programmatically.
+//
+// Parentheses are inserted only where they are needed to preserve the
+// structure of the expression, given the precedence of its operators, so
+// parsing the result produces an expression that is equivalent to the one
+// given. The source ranges recorded in the given expression are ignored.
+//
+// Any literal values in the expression must be valid for TokensForValue, and
+// this function will panic if given a hclsyntax.BadExpr or an expression type
+// from another package.
+func TokensForExpression(expr hclsyntax.Expression) Tokens {
+	toks := appendTokensForExpression(expr, nil)
+	format(toks) // fiddle with the SpacesBefore field to get canonical spacing
+	return toks
+}
+
+// Precedence levels used to decide where parentheses are required. The
+// binary operators occupy the levels between exprPrecLowest and
+// exprPrecUnary, as given in binaryOpSyntax.
+const (
+	// exprPrecLowest is for expressions that extend as far to the right as
+	// possible, and so can appear without parentheses only where a whole
+	// expression is expected.
+	exprPrecLowest = 0
+
+	// exprPrecSplat is for splat expressions, which must be enclosed in
+	// parentheses before applying any further traversal steps to their
+	// result, because otherwise the steps would apply to each element.
+	exprPrecSplat = 90
+
+	exprPrecUnary = 100
+	exprPrecTerm  = 110
+)
+
+// binaryOpSyntax describes the syntax of each of the binary operators,
+// mirroring the operator precedence table in the native syntax parser.
+var binaryOpSyntax = map[*hclsyntax.Operation]struct {
+	Level int
+	Type  hclsyntax.TokenType
+	Bytes string
+}{
+	hclsyntax.OpNullCoalesce: {1, hclsyntax.TokenDoubleQuestion, "??"},
+
+	hclsyntax.OpLogicalOr: {2, hclsyntax.TokenOr, "||"},
+
+	hclsyntax.OpLogicalAnd: {3, hclsyntax.TokenAnd, "&&"},
+
+	hclsyntax.OpEqual:    {4, hclsyntax.TokenEqualOp, "=="},
+	hclsyntax.OpNotEqual: {4, hclsyntax.TokenNotEqual, "!="},
+
+	hclsyntax.OpGreaterThan:        {5, hclsyntax.TokenGreaterThan, ">"},
+	hclsyntax.OpGreaterThanOrEqual: {5, hclsyntax.TokenGreaterThanEq, ">="},
+	hclsyntax.OpLessThan:           {5, hclsyntax.TokenLessThan, "<"},
+	hclsyntax.OpLessThanOrEqual:    {5, hclsyntax.TokenLessThanEq, "<="},
+
+	hclsyntax.OpAdd:      {6, hclsyntax.TokenPlus, "+"},
+	hclsyntax.OpSubtract: {6, hclsyntax.TokenMinus, "-"},
+
+	hclsyntax.OpMultiply: {7, hclsyntax.TokenStar, "*"},
+	hclsyntax.OpDivide:   {7, hclsyntax.TokenSlash, "/"},
+	hclsyntax.OpModulo:   {7, hclsyntax.TokenPercent, "%"},
+}
+
+// exprPrecedence returns the precedence level of the given expression, which
+// determines whether it must be enclosed in parentheses when used as an
+// operand of another expression.
+func exprPrecedence(expr hclsyntax.Expression) int {
+	switch e := expr.(type) {
+	case *hclsyntax.ConditionalExpr, *hclsyntax.LetExpr:
+		return exprPrecLowest
+	case *hclsyntax.BinaryOpExpr:
+		return binaryOpSyntax[e.Op].Level
+	case *hclsyntax.UnaryOpExpr:
+		return exprPrecUnary
+	case *hclsyntax.SplatExpr:
+		return exprPrecSplat
+	case *hclsyntax.LiteralValueExpr:
+		if e.Val.Type() == cty.Number && e.Val.IsKnown() && !e.Val.IsNull() && e.Val.AsBigFloat().Sign() < 0 {
+			// A negative number is written using the negation operator.
+			return exprPrecUnary
+		}
+		return exprPrecTerm
+	default:
+		return exprPrecTerm
+	}
+}
+
+// appendTokensForOperand appends the tokens for the given expression,
+// enclosing them in parentheses if the expression's precedence is lower than
+// the given minimum.
+func appendTokensForOperand(expr hclsyntax.Expression, minPrec int, toks Tokens) Tokens {
+	if exprPrecedence(expr) >= minPrec {
+		return appendTokensForExpression(expr, toks)
+	}
+	toks = append(toks, newToken(hclsyntax.TokenOParen, "("))
+	toks = appendTokensForExpression(expr, toks)
+	return append(toks, newToken(hclsyntax.TokenCParen, ")"))
+}
+
+func appendTokensForExpression(expr hclsyntax.Expression, toks Tokens) Tokens {
+	switch e := expr.(type) {
+
+	case *hclsyntax.LiteralValueExpr:
+		return appendTokensForValue(e.Val, toks)
+
+	case *hclsyntax.ScopeTraversalExpr:
+		return appendTokensForTraversal(e.Traversal, toks)
+
+	case *hclsyntax.RelativeTraversalExpr:
+		toks = appendTokensForOperand(e.Source, exprPrecTerm, toks)
+		return appendTokensForTraversal(e.Traversal, toks)
+
+	case *hclsyntax.IndexExpr:
+		toks = appendTokensForOperand(e.Collection, exprPrecTerm, toks)
+		toks = append(toks, newToken(hclsyntax.TokenOBrack, "["))
+		toks = appendTokensForExpression(e.Key, toks)
+		return append(toks, newToken(hclsyntax.TokenCBrack, "]"))
+
+	case *hclsyntax.SplatExpr:
+		// We always use the "full splat" syntax here, since it can represent
+		// all of the traversals that the "attribute-only" syntax can.
+		toks = appendTokensForOperand(e.Source, exprPrecTerm, toks)
+		toks = append(
+			toks,
+			newToken(hclsyntax.TokenOBrack, "["),
+			newToken(hclsyntax.TokenStar, "*"),
+			newToken(hclsyntax.TokenCBrack, "]"),
+		)
+		return appendTokensForExpression(e.Each, toks)
+
+	case *hclsyntax.AnonSymbolExpr:
+		// This represents the current item in the "Each" expression of a
+		// splat expression, which is implied by the splat syntax.
+		return toks
+
+	case *hclsyntax.FunctionCallExpr:
+		toks = append(
+			toks,
+			newToken(hclsyntax.TokenIdent, e.Name),
+			newToken(hclsyntax.TokenOParen, "("),
+		)
+		for i, arg := range e.Args {
+			if i > 0 {
+				toks = append(toks, newToken(hclsyntax.TokenComma, ","))
+			}
+			toks = appendTokensForExpression(arg, toks)
+		}
+		if e.ExpandFinal && len(e.Args) > 0 {
+			toks = append(toks, newToken(hclsyntax.TokenEllipsis, "..."))
+		}
+		return append(toks, newToken(hclsyntax.TokenCParen, ")"))
+
+	case *hclsyntax.BinaryOpExpr:
+		syntax, ok := binaryOpSyntax[e.Op]
+		if !ok {
+			panic(fmt.Sprintf("cannot produce tokens for unsupported binary operation %#v", e.Op))
+		}
+		// Binary operators are left-associative, so an operand of the same
+		// precedence needs parentheses only on the right.
+		toks = appendTokensForOperand(e.LHS, syntax.Level, toks)
+		toks = append(toks, newToken(syntax.Type, syntax.Bytes))
+		return appendTokensForOperand(e.RHS, syntax.Level+1, toks)
+
+	case *hclsyntax.UnaryOpExpr:
+		switch e.Op {
+		case hclsyntax.OpLogicalNot:
+			toks = append(toks, newToken(hclsyntax.TokenBang, "!"))
+		case hclsyntax.OpNegate:
+			toks = append(toks, newToken(hclsyntax.TokenMinus, "-"))
+		default:
+			panic(fmt.Sprintf("cannot produce tokens for unsupported unary operation %#v", e.Op))
+		}
+		return appendTokensForOperand(e.Val, exprPrecUnary, toks)
+
+	case *hclsyntax.ConditionalExpr:
+		toks = appendTokensForOperand(e.Condition, exprPrecLowest+1, toks)
+		toks = append(toks, newToken(hclsyntax.TokenQuestion, "?"))
+		toks = appendTokensForExpression(e.TrueResult, toks)
+		toks = append(toks, newToken(hclsyntax.TokenColon, ":"))
+		return appendTokensForExpression(e.FalseResult, toks)
+
+	case *hclsyntax.LetExpr:
+		toks = append(
+			toks,
+			newToken(hclsyntax.TokenIdent, "let"),
+			newToken(hclsyntax.TokenIdent, e.Name),
+			newToken(hclsyntax.TokenEqual, "="),
+		)
+		toks = appendTokensForExpression(e.ValueExpr, toks)
+		toks = append(toks, newToken(hclsyntax.TokenIdent, "in"))
+		return appendTokensForExpression(e.BodyExpr, toks)
+
+	case *hclsyntax.TupleConsExpr:
+		toks = append(toks, newToken(hclsyntax.TokenOBrack, "["))
+		for i, elem := range e.Exprs {
+			if i > 0 {
+				toks = append(toks, newToken(hclsyntax.TokenComma, ","))
+			}
+			toks = appendTokensForExpression(elem, toks)
+		}
+		return append(toks, newToken(hclsyntax.TokenCBrack, "]"))
+
+	case *hclsyntax.ObjectConsExpr:
+		toks = append(toks, newToken(hclsyntax.TokenOBrace, "{"))
+		for i, item := range e.Items {
+			if i > 0 {
+				toks = append(toks, newToken(hclsyntax.TokenComma, ","))
+			}
+			toks = appendTokensForObjectKey(item.KeyExpr, toks)
+			toks = append(toks, newToken(hclsyntax.TokenEqual, "="))
+			toks = appendTokensForExpression(item.ValueExpr, toks)
+		}
+		return append(toks, newToken(hclsyntax.TokenCBrace, "}"))
+
+	case *hclsyntax.ObjectConsKeyExpr:
+		// An object key is meaningful only within an object constructor,
+		// so here we just produce the key as a standalone expression.
+		if name := hcl.ExprAsKeyword(e.Wrapped); name != "" {
+			return appendTokensForValue(cty.StringVal(name), toks)
+		}
+		return appendTokensForExpression(e.Wrapped, toks)
+
+	case *hclsyntax.ForExpr:
+		open, close := newToken(hclsyntax.TokenOBrack, "["), newToken(hclsyntax.TokenCBrack, "]")
+		if e.KeyExpr != nil {
+			open, close = newToken(hclsyntax.TokenOBrace, "{"), newToken(hclsyntax.TokenCBrace, "}")
+		}
+		toks = append(toks, open, newToken(hclsyntax.TokenIdent, "for"))
+		if e.KeyVar != "" {
+			toks = append(
+				toks,
+				newToken(hclsyntax.TokenIdent, e.KeyVar),
+				newToken(hclsyntax.TokenComma, ","),
+			)
+		}
+		toks = append(
+			toks,
+			newToken(hclsyntax.TokenIdent, e.ValVar),
+			newToken(hclsyntax.TokenIdent, "in"),
+		)
+		toks = appendTokensForExpression(e.CollExpr, toks)
+		toks = append(toks, newToken(hclsyntax.TokenColon, ":"))
+		if e.KeyExpr != nil {
+			toks = appendTokensForExpression(e.KeyExpr, toks)
+			toks = append(toks, newToken(hclsyntax.TokenFatArrow, "=>"))
+		}
+		toks = appendTokensForExpression(e.ValExpr, toks)
+		if e.Group {
+			toks = append(toks, newToken(hclsyntax.TokenEllipsis, "..."))
+		}
+		if e.CondExpr != nil {
+			toks = append(toks, newToken(hclsyntax.TokenIdent, "if"))
+			toks = appendTokensForExpression(e.CondExpr, toks)
+		}
+		return append(toks, close)
+
+	case *hclsyntax.TemplateExpr:
+		toks = append(toks, newToken(hclsyntax.TokenOQuote, `"`))
+		toks = appendTokensForTemplateParts(e.Parts, toks)
+		return append(toks, newToken(hclsyntax.TokenCQuote, `"`))
+
+	case *hclsyntax.TemplateWrapExpr:
+		toks = append(toks, newToken(hclsyntax.TokenOQuote, `"`))
+		toks = appendTokensForTemplateInterp(e.Wrapped, toks)
+		return append(toks, newToken(hclsyntax.TokenCQuote, `"`))
+
+	case *hclsyntax.TemplateJoinExpr:
+		toks = append(toks, newToken(hclsyntax.TokenOQuote, `"`))
+		toks = appendTokensForTemplateParts([]hclsyntax.Expression{e}, toks)
+		return append(toks, newToken(hclsyntax.TokenCQuote, `"`))
+
+	default:
+		panic(fmt.Sprintf("cannot produce tokens for %T", expr))
+	}
+}
+
+// appendTokensForObjectKey appends the tokens for the key of an item in an
+// object constructor expression.
+func appendTokensForObjectKey(expr hclsyntax.Expression, toks Tokens) Tokens {
+	if key, isKey := expr.(*hclsyntax.ObjectConsKeyExpr); isKey {
+		if name := hcl.ExprAsKeyword(key.Wrapped); name != "" {
+			return append(toks, newToken(hclsyntax.TokenIdent, name))
+		}
+		expr = key.Wrapped
+	}
+
+	// A naked identifier or traversal would be taken as a literal key, even
+	// if enclosed in parentheses, so we must use a template interpolation
+	// to represent a reference.
+	if _, diags := hcl.AbsTraversalForExpr(expr); !diags.HasErrors() {
+		if _, isLit := expr.(*hclsyntax.LiteralValueExpr); !isLit {
+			toks = append(toks, newToken(hclsyntax.TokenOQuote, `"`))
+			toks = appendTokensForTemplateInterp(expr, toks)
+			return append(toks, newToken(hclsyntax.TokenCQuote, `"`))
+		}
+	}
+	return appendTokensForExpression(expr, toks)
+}
+
+// appendTokensForTemplateParts appends the tokens for the given parts of a
+// template, without the surrounding quotes.
+func appendTokensForTemplateParts(parts []hclsyntax.Expression, toks Tokens) Tokens {
+	for _, part := range parts {
+		switch e := part.(type) {
+
+		case *hclsyntax.LiteralValueExpr:
+			if e.Val.Type() != cty.String || !e.Val.IsKnown() || e.Val.IsNull() {
+				toks = appendTokensForTemplateInterp(e, toks)
+				continue
+			}
+			if src := escapeQuotedStringLit(e.Val.AsString()); len(src) > 0 {
+				toks = append(toks, &Token{
+					Type:  hclsyntax.TokenQuotedLit,
+					Bytes: src,
+				})
+			}
+
+		case *hclsyntax.TemplateExpr:
+			toks = appendTokensForTemplateParts(e.Parts, toks)
+
+		case *hclsyntax.ConditionalExpr:
+			trueTmpl, trueOK := e.TrueResult.(*hclsyntax.TemplateExpr)
+			falseTmpl, falseOK := e.FalseResult.(*hclsyntax.TemplateExpr)
+			if !trueOK || !falseOK {
+				toks = appendTokensForTemplateInterp(e, toks)
+				continue
+			}
+			toks = appendTokensForTemplateDirective(toks, "if", e.Condition)
+			toks = appendTokensForTemplateParts(trueTmpl.Parts, toks)
+			if !templateIsEmpty(falseTmpl) {
+				toks = appendTokensForTemplateDirective(toks, "else", nil)
+				toks = appendTokensForTemplateParts(falseTmpl.Parts, toks)
+			}
+			toks = appendTokensForTemplateDirective(toks, "endif", nil)
+
+		case *hclsyntax.TemplateJoinExpr:
+			forExpr, isFor := e.Tuple.(*hclsyntax.ForExpr)
+			if !isFor || forExpr.KeyExpr != nil || forExpr.CondExpr != nil {
+				toks = appendTokensForTemplateInterp(e, toks)
+				continue
+			}
+			toks = append(
+				toks,
+				newToken(hclsyntax.TokenTemplateControl, "%{"),
+				newToken(hclsyntax.TokenIdent, "for"),
+			)
+			if forExpr.KeyVar != "" {
+				toks = append(
+					toks,
+					newToken(hclsyntax.TokenIdent, forExpr.KeyVar),
+					newToken(hclsyntax.TokenComma, ","),
+				)
+			}
+			toks = append(
+				toks,
+				newToken(hclsyntax.TokenIdent, forExpr.ValVar),
+				newToken(hclsyntax.TokenIdent, "in"),
+			)
+			toks = appendTokensForExpression(forExpr.CollExpr, toks)
+			toks = append(toks, newToken(hclsyntax.TokenTemplateSeqEnd, "}"))
+			toks = appendTokensForTemplateParts([]hclsyntax.Expression{forExpr.ValExpr}, toks)
+			toks = appendTokensForTemplateDirective(toks, "endfor", nil)
+
+		default:
+			toks = appendTokensForTemplateInterp(part, toks)
+		}
+	}
+	return toks
+}
+
+// appendTokensForTemplateInterp appends the tokens for a template
+// interpolation sequence that includes the result of the given expression.
+func appendTokensForTemplateInterp(expr hclsyntax.Expression, toks Tokens) Tokens {
+	toks = append(toks, newToken(hclsyntax.TokenTemplateInterp, "${"))
+	toks = appendTokensForExpression(expr, toks)
+	return append(toks, newToken(hclsyntax.TokenTemplateSeqEnd, "}"))
+}
+
+// appendTokensForTemplateDirective appends the tokens for a template directive
+// with the given keyword and optional expression.
+func appendTokensForTemplateDirective(toks Tokens, keyword string, expr hclsyntax.Expression) Tokens {
+	toks = append(
+		toks,
+		newToken(hclsyntax.TokenTemplateControl, "%{"),
+		newToken(hclsyntax.TokenIdent, keyword),
+	)
+	if expr != nil {
+		toks = appendTokensForExpression(expr, toks)
+	}
+	return append(toks, newToken(hclsyntax.TokenTemplateSeqEnd, "}"))
+}
+
+// templateIsEmpty returns true if the given template consists only of
+// literal parts that produce an empty string, as the parser generates for
+// the missing "else" clause of an "if" directive.
+func templateIsEmpty(tmpl *hclsyntax.TemplateExpr) bool {
+	for _, part := range tmpl.Parts {
+		lit, isLit := part.(*hclsyntax.LiteralValueExpr)
+		if !isLit || !lit.Val.RawEquals(cty.StringVal("")) {
+			return false
+		}
+	}
+	return true
+}
+
+func newToken(ty hclsyntax.TokenType, src string) *Token {
+	return &Token{
+		Type:  ty,
+		Bytes: []byte(src),
+	}
+}
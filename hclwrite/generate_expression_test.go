@@ -0,0 +1,221 @@
+package hclwrite
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+func TestTokensForExpression(t *testing.T) {
+	tests := []struct {
+		Input string
+		Want  string
+	}{
+		{`1`, `1`},
+		{`"hello"`, `"hello"`},
+		{`"a\nb\"c"`, `"a\nb\"c"`},
+		{`a`, `a`},
+		{`obj.name`, `obj.name`},
+		{`obj?.name`, `obj?.name`},
+		{`list[0]`, `list[0]`},
+		{`list[n - 2]`, `list[n - 2]`},
+		{`upper(a)`, `upper(a)`},
+		{`concat(list, [list]...)`, `concat(list, [list]...)`},
+		{`n + n`, `n + n`},
+		{`(n + n)`, `n + n`},
+		{`(n + 1) * n`, `(n + 1) * n`},
+		{`n + (2 * n)`, `n + 2 * n`},
+		{`n - n - n`, `n - n - n`},
+		{`n - (n - n)`, `n - (n - n)`},
+		{`(n - n) - n`, `n - n - n`},
+		{`n * (n % n)`, `n * (n % n)`},
+		{`t || f && t`, `t || f && t`},
+		{`(t || f) && t`, `(t || f) && t`},
+		{`n == 1 == t`, `n == 1 == t`},
+		{`n < 2 && n >= 0`, `n < 2 && n >= 0`},
+		{`a ?? b ?? "c"`, `a ?? b ?? "c"`},
+		{`(t ? a : b) ?? "c"`, `(t ? a : b) ?? "c"`},
+		{`!t`, `!t`},
+		{`!(t && f)`, `!(t && f)`},
+		{`-n`, `-n`},
+		{`-(n + 1)`, `-(n + 1)`},
+		{`-1`, `-1`},
+		{`(-1) * n`, `-1 * n`},
+		{`(-n) + 1`, `-n + 1`},
+		{`(-[n][0]) * 2`, `-[n][0] * 2`},
+		{`(obj ?? a).name`, `(obj ?? a).name`},
+		{`t ? a : b`, `t ? a : b`},
+		{`(t ? n : 1) + 2`, `(t ? n : 1) + 2`},
+		{`n + (t ? n : 1)`, `n + (t ? n : 1)`},
+		{`t ? f ? a : b : "c"`, `t ? f ? a : b : "c"`},
+		{`(t ? f : t) ? a : b`, `(t ? f : t) ? a : b`},
+		{`[]`, `[]`},
+		{`[1, a, [n]]`, `[1, a, [n]]`},
+		{`{}`, `{}`},
+		{`{name = a, "the key" = n}`, `{ name = a, "the key" = n }`},
+		{`{"${a}" = n}`, `{ "${a}" = n }`},
+		{`{(a) = n}`, `{ a = n }`},
+		{`{"${obj.name}" = n}`, `{ "${obj.name}" = n }`},
+		{`{upper(a) = n}`, `{ upper(a) = n }`},
+		{`[for x in list: x.name]`, `[for x in list : x.name]`},
+		{`[for i, x in list: i if x.name != a]`, `[for i, x in list : i if x.name != a]`},
+		{`{for i, x in list: x.name => i...}`, `{ for i, x in list : x.name => i... }`},
+		{`{for i, x in list: i => -i}`, `{ for i, x in list : i => -i }`},
+		{`{for x in list: x.name => x...}`, `{ for x in list : x.name => x... }`},
+		{`a ?? -n`, `a ?? -n`},
+		{`list[*].name`, `list[*].name`},
+		{`list.*.name`, `list[*].name`},
+		{`list[*]`, `list[*]`},
+		{`(list[*].name)[0]`, `(list[*].name)[0]`},
+		{`[list][*][0].name`, `[list][*][0].name`},
+		{`[upper(a)][0]`, `[upper(a)][0]`},
+		{`"${a}"`, `"${a}"`},
+		{`"${a}-${n + 1}"`, `"${a}-${n + 1}"`},
+		{`"a%%{b}$${c}"`, `"a%%{b}$${c}"`},
+		{`"%{ if t }yes%{ endif }"`, `"%{if t}yes%{endif}"`},
+		{`"%{ if t }yes%{ else }no%{ endif }"`, `"%{if t}yes%{else}no%{endif}"`},
+		{`"%{ for x in list }${x.name},%{ endfor }"`, `"%{for x in list}${x.name},%{endfor}"`},
+		{`"%{ for i, x in list }${i}%{ endfor }"`, `"%{for i, x in list}${i}%{endfor}"`},
+		{`let x = n + 1 in x * 2`, `let x = n + 1 in x * 2`},
+		{`(let x = n in x) + 1`, `(let x = n in x) + 1`},
+	}
+
+	ctx := testTokensForExpressionContext()
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(test.Input), "", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors parsing input: %s", diags.Error())
+			}
+
+			got := string(TokensForExpression(expr).Bytes())
+			if got != test.Want {
+				t.Fatalf("wrong result\ninput: %s\ngot:   %s\nwant:  %s", test.Input, got, test.Want)
+			}
+
+			// The result must parse to an expression that produces the same
+			// result and renders identically.
+			reparsed, diags := hclsyntax.ParseExpression([]byte(got), "", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors parsing result: %s", diags.Error())
+			}
+			if again := string(TokensForExpression(reparsed).Bytes()); again != got {
+				t.Errorf("result does not round-trip\nfirst:  %s\nsecond: %s", got, again)
+			}
+			wantVal, diags := expr.Value(ctx)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors evaluating input: %s", diags.Error())
+			}
+			gotVal, diags := reparsed.Value(ctx)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors evaluating result: %s", diags.Error())
+			}
+			if !gotVal.RawEquals(wantVal) {
+				t.Errorf("wrong value\ngot:  %#v\nwant: %#v", gotVal, wantVal)
+			}
+		})
+	}
+}
+
+func TestTokensForExpressionConstructed(t *testing.T) {
+	// Expressions built directly, rather than by the parser, may have
+	// structures that require parentheses which the parser would not have
+	// recorded.
+	n := &hclsyntax.ScopeTraversalExpr{
+		Traversal: hcl.Traversal{hcl.TraverseRoot{Name: "n"}},
+	}
+	tests := []struct {
+		Expr hclsyntax.Expression
+		Want string
+	}{
+		{
+			&hclsyntax.BinaryOpExpr{
+				LHS: n,
+				Op:  hclsyntax.OpMultiply,
+				RHS: &hclsyntax.BinaryOpExpr{
+					LHS: n,
+					Op:  hclsyntax.OpAdd,
+					RHS: &hclsyntax.LiteralValueExpr{Val: cty.NumberIntVal(1)},
+				},
+			},
+			`n * (n + 1)`,
+		},
+		{
+			&hclsyntax.RelativeTraversalExpr{
+				Source: &hclsyntax.LiteralValueExpr{Val: cty.NumberIntVal(-2)},
+				Traversal: hcl.Traversal{
+					hcl.TraverseAttr{Name: "foo"},
+				},
+			},
+			`(-2).foo`,
+		},
+		{
+			&hclsyntax.UnaryOpExpr{
+				Op:  hclsyntax.OpNegate,
+				Val: &hclsyntax.LiteralValueExpr{Val: cty.NumberIntVal(-2)},
+			},
+			`--2`,
+		},
+		{
+			&hclsyntax.FunctionCallExpr{
+				Name: "max",
+				Args: []hclsyntax.Expression{
+					&hclsyntax.ConditionalExpr{
+						Condition:   &hclsyntax.LiteralValueExpr{Val: cty.True},
+						TrueResult:  n,
+						FalseResult: n,
+					},
+				},
+			},
+			`max(true ? n : n)`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Want, func(t *testing.T) {
+			got := string(TokensForExpression(test.Expr).Bytes())
+			if got != test.Want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestTokensForTraversal(t *testing.T) {
+	traversal := hcl.Traversal{
+		hcl.TraverseRoot{Name: "foo"},
+		hcl.TraverseAttr{Name: "bar"},
+		hcl.TraverseIndex{Key: cty.NumberIntVal(0)},
+		hcl.TraverseIndex{Key: cty.StringVal("baz")},
+	}
+	got := string(TokensForTraversal(traversal).Bytes())
+	want := `foo.bar[0]["baz"]`
+	if got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func testTokensForExpressionContext() *hcl.EvalContext {
+	obj := cty.ObjectVal(map[string]cty.Value{
+		"name": cty.StringVal("hello"),
+	})
+	return &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"a":    cty.StringVal("a"),
+			"b":    cty.StringVal("b"),
+			"n":    cty.NumberIntVal(3),
+			"t":    cty.True,
+			"f":    cty.False,
+			"obj":  obj,
+			"list": cty.TupleVal([]cty.Value{obj, obj}),
+		},
+		Functions: map[string]function.Function{
+			"upper":  stdlib.UpperFunc,
+			"concat": stdlib.ConcatFunc,
+		},
+	}
+}
@@ -0,0 +1,8 @@
+decimal    = 1234
+fractional = 12.5
+exponent   = 1.5e3
+separated  = 1_000_000
+hex        = 0xFF
+hex_upper  = 0XDEAD_BEEF
+octal      = 0o755
+binary     = 0b1010_1010
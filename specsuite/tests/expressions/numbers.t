@@ -0,0 +1,20 @@
+result = {
+  decimal    = 1234
+  fractional = 12.5
+  exponent   = 1500
+  separated  = 1000000
+  hex        = 255
+  hex_upper  = 3735928559
+  octal      = 493
+  binary     = 170
+}
+result_type = object({
+  decimal    = number
+  fractional = number
+  exponent   = number
+  separated  = number
+  hex        = number
+  hex_upper  = number
+  octal      = number
+  binary     = number
+})
@@ -0,0 +1,4 @@
+octal  = 0o758
+binary = 0b102
+hex    = 0xFG
+under  = 1__0
@@ -0,0 +1,57 @@
+diagnostics {
+  error {
+    # The digit "8" is not valid in an octal number.
+    from {
+      line   = 1
+      column = 14
+      byte   = 13
+    }
+    to {
+      line   = 1
+      column = 15
+      byte   = 14
+    }
+  }
+
+  error {
+    # The digit "2" is not valid in a binary number.
+    from {
+      line   = 2
+      column = 14
+      byte   = 28
+    }
+    to {
+      line   = 2
+      column = 15
+      byte   = 29
+    }
+  }
+
+  error {
+    # The character "G" is not a hexadecimal digit, so it begins a new token.
+    from {
+      line   = 3
+      column = 13
+      byte   = 42
+    }
+    to {
+      line   = 3
+      column = 14
+      byte   = 43
+    }
+  }
+
+  error {
+    # Underscores must be placed between two digits.
+    from {
+      line   = 4
+      column = 11
+      byte   = 54
+    }
+    to {
+      line   = 4
+      column = 12
+      byte   = 55
+    }
+  }
+}
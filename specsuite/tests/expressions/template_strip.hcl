@@ -0,0 +1,39 @@
+quoted = {
+  interp_both   = "Foo ${~ bar ~} Baz"
+  interp_before = "Foo ${~ bar} Baz"
+  interp_after  = "Foo ${bar ~} Baz"
+  cond_true     = "Foo %{~ if true ~} Bar %{~ endif ~} Baz"
+  cond_false    = "Foo %{~ if false ~} Bar %{~ endif ~} Baz"
+  loop          = "%{ for w in words ~} ${w} %{~ endfor }"
+}
+heredoc = {
+  loop = <<EOT
+%{ for w in words ~}
+${w}
+%{ endfor ~}
+EOT
+  loop_indented = <<-EOT
+    Start
+    %{~ for w in words ~}
+      ${w}
+    %{~ endfor ~}
+
+    End
+  EOT
+  cond = <<EOT
+Foo
+%{ if true ~}
+  Bar
+%{ endif ~}
+Baz
+EOT
+  blank_lines = <<EOT
+Foo
+
+
+  ${~ bar ~}
+
+
+Baz
+EOT
+}
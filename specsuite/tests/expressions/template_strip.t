@@ -0,0 +1,20 @@
+result = {
+    quoted = {
+        interp_both   = "FooBarBaz"
+        interp_before = "FooBar Baz"
+        interp_after  = "Foo BarBaz"
+        cond_true     = "FooBarBaz"
+        cond_false    = "FooBaz"
+        loop          = "FooBarBaz"
+    }
+    heredoc = {
+        loop          = "Foo\nBar\nBaz\n"
+        loop_indented = "StartFooBarBazEnd\n"
+        cond          = "Foo\nBar\nBaz\n"
+        blank_lines   = "FooBarBaz\n"
+    }
+}
+result_type = object({
+  quoted  = map(string)
+  heredoc = map(string)
+})